@@ -5,8 +5,10 @@ import (
 	config "go-incubator/internal/configuration"
 	"go-incubator/internal/grpc"
 	"go-incubator/internal/persistence"
+	"go-incubator/internal/persistence/cachedb"
 	"go-incubator/internal/persistence/memdb"
 	"go-incubator/internal/persistence/mysqldb"
+	"go-incubator/internal/persistence/sqlitedb"
 	"os"
 	"os/signal"
 	"sync"
@@ -22,6 +24,19 @@ func main() {
 		return
 	}
 
+	if len(cfg.Synonyms) > 0 {
+		fmt.Printf("using %d ingredient synonym groups\n", len(cfg.Synonyms))
+		persistence.SetSynonyms(cfg.Synonyms)
+	}
+
+	if cfg.Taxonomy != "" {
+		if err := persistence.LoadTaxonomy(cfg.Taxonomy); err != nil {
+			fmt.Printf("error loading ingredient taxonomy: %v\n", err)
+			return
+		}
+		fmt.Printf("using ingredient taxonomy from %s\n", cfg.Taxonomy)
+	}
+
 	var db persistence.Persistence
 	switch cfg.Database.DBMS {
 	case "inmem":
@@ -32,6 +47,19 @@ func main() {
 			return
 		}
 		db = &imp
+	case "file":
+		fmt.Println("using file backed inmem database")
+		imp, err := memdb.NewFileMemDB(cfg.Database.ConString, cfg.Database.SyncInterval, cfg.Database.SnapshotInterval)
+		if err != nil {
+			fmt.Printf("error creating file backed memdb database: %v\n", err)
+			return
+		}
+		defer func() {
+			if err := imp.Close(); err != nil {
+				fmt.Printf("error closing file backed memdb database: %v\n", err)
+			}
+		}()
+		db = &imp
 	case "mysql":
 		fmt.Println("using mysql database")
 		imp, err := mysqldb.NewMySqlDB(cfg.Database.ConString)
@@ -40,12 +68,35 @@ func main() {
 			return
 		}
 		db = &imp
+	case "sqlite":
+		fmt.Println("using sqlite database")
+		imp, err := sqlitedb.NewSqliteDB(cfg.Database.ConString)
+		if err != nil {
+			fmt.Printf("error creating sqlite database: %v\n", err)
+			return
+		}
+		defer imp.Close()
+		db = &imp
 	default:
 		fmt.Printf("unknown DBMS (%s) specified\n", cfg.Database.DBMS)
 		return
 	}
 
-	grpcServer, err := grpc.NewGrpcServer(cfg.GrpcPort, cfg.ApiKey, db)
+	if cfg.Database.CacheSize > 0 {
+		fmt.Printf("caching up to %d results (ttl %v)\n", cfg.Database.CacheSize, cfg.Database.CacheTTL)
+		cache, err := cachedb.NewCacheDB(db, cfg.Database.CacheSize, cfg.Database.CacheTTL)
+		if err != nil {
+			fmt.Printf("error creating cache: %v\n", err)
+			return
+		}
+		defer func() {
+			stats := cache.Stats()
+			fmt.Printf("cache hits: %d, misses: %d, evictions: %d\n", stats.Hits, stats.Misses, stats.Evictions)
+		}()
+		db = &cache
+	}
+
+	grpcServer, err := grpc.NewGrpcServer(cfg.GrpcPort, cfg.ApiKey, cfg.AdminKey, db)
 	if err != nil {
 		fmt.Printf("error creating gRPC server: %v\n", err)
 		return
@@ -6,9 +6,22 @@ import (
 	"go-incubator/internal/helpers"
 	"go-incubator/internal/http"
 	"go-incubator/internal/ui"
+	"strings"
 	"time"
 )
 
+// noneOfThese is the choice which turns down all suggestions
+const noneOfThese = "None of these"
+
+// nameMatches are the choices of how to match names when searching by name
+var nameMatches = []string{"Recipes whose names start with this", "Recipes whose names contain this"}
+
+// unitSystems are the choices of units for a scaled recipe, the first keeping the units it was written in
+var unitSystems = []string{"As written", "Metric", "Imperial"}
+
+// matchModes are the choices of which recipes to find when searching by ingredients
+var matchModes = []string{"Recipes using all of these ingredients", "Recipes using any of these ingredients", "Recipes I can make with these ingredients"}
+
 func main() {
 	cfg, err := config.ReadConfig("INCUBATOR_")
 	if err != nil {
@@ -22,8 +35,18 @@ func main() {
 		return
 	}
 
+	// completeIngredient suggests the ingredients which recipes use most that start with prefix
+	completeIngredient := func(prefix string) ([]string, error) {
+		ingredients, err := httpClient.CompleteIngredient(prefix, 10)
+		names := make([]string, 0, len(ingredients))
+		for _, v := range ingredients {
+			names = append(names, v.Name)
+		}
+		return names, err
+	}
+
 	for {
-		action := ui.Selection("What would you like to do?", []string{"Add a recipe", "Get a recipe", "Search by ingredients", "Run Benchmarks", "Quit"})
+		action := ui.Selection("What would you like to do?", []string{"Add a recipe", "Get a recipe", "Delete a recipe", "Search by ingredients", "Search by name", "List all recipes", "List all ingredients", "Show statistics", "Run Benchmarks", "Quit"})
 		fmt.Println()
 
 		switch action {
@@ -31,15 +54,29 @@ func main() {
 			fmt.Println("Adding a recipe:")
 			newRecipe := http.Recipe{}
 			newRecipe.Name = ui.GetValue("Enter name of recipe -> ")
+			newRecipe.Description = ui.GetValue("Enter a short description (optional) -> ")
 			addIngredients := true
 			for addIngredients {
-				ingredient := ui.GetValue("Enter ingredient name (blank to stop) -> ")
+				ingredient := ui.GetValue("Enter ingredient, e.g. 2 cups flour, sifted, or @Pizza Dough for another recipe (blank to stop) -> ")
 				if ingredient == "" {
 					addIngredients = false
 				} else {
-					newRecipe.AddIngredient(ingredient)
+					newRecipe.AddIngredient(http.ParseIngredient(ingredient))
+				}
+			}
+			addSteps := true
+			for addSteps {
+				step := ui.GetValue(fmt.Sprintf("Enter step %d of the method (blank to stop) -> ", len(newRecipe.Instructions)+1))
+				if step == "" {
+					addSteps = false
+				} else {
+					newRecipe.Instructions = append(newRecipe.Instructions, step)
 				}
 			}
+			newRecipe.Servings = ui.GetNumber("Enter number of servings (optional) -> ")
+			newRecipe.PrepMinutes = ui.GetNumber("Enter preparation time in minutes (optional) -> ")
+			newRecipe.CookMinutes = ui.GetNumber("Enter cooking time in minutes (optional) -> ")
+			newRecipe.Source = ui.GetValue("Enter source of recipe, e.g. a book or URL (optional) -> ")
 			fmt.Println()
 			err = httpClient.AddRecipe(newRecipe)
 			if err != nil {
@@ -52,7 +89,15 @@ func main() {
 			name := ui.GetValue("Enter name of recipe -> ")
 			fmt.Println()
 
-			recipe, err := httpClient.GetRecipe(name)
+			recipe, suggestions, err := httpClient.LookupRecipe(name, false)
+			if err == nil && recipe == nil && len(suggestions) > 0 {
+				choice := ui.Selection(fmt.Sprintf("Sorry, no recipe for %s found. Did you mean one of these?", name), append(suggestions, noneOfThese))
+				fmt.Println()
+				if choice != noneOfThese {
+					name = choice
+					recipe, err = httpClient.GetRecipe(name)
+				}
+			}
 			if err != nil {
 				fmt.Printf("Something went wrong when we tried to get the recipe: %v\n", err)
 			} else {
@@ -60,6 +105,58 @@ func main() {
 					fmt.Printf("Sorry, no recipe for %s found\n", name)
 				} else {
 					fmt.Printf("Recipe found:\n%s\n", recipe)
+					if components := recipe.Components(); len(components) > 0 {
+						if ui.Selection(fmt.Sprintf("Would you like to see all ingredients, including those of %s?", strings.Join(components, " and ")), []string{"Yes", "No"}) == "Yes" {
+							expanded, err := httpClient.ExpandRecipe(recipe.Name)
+							if err != nil {
+								fmt.Printf("Something went wrong when we tried to expand the recipe: %v\n", err)
+							} else if expanded != nil {
+								fmt.Println("All ingredients:")
+								for _, v := range expanded.Ingredients {
+									fmt.Printf("  - %s\n", v)
+								}
+							}
+						}
+					}
+					if recipe.Servings > 0 {
+						servings := ui.GetNumber(fmt.Sprintf("Enter number of servings to scale to (blank for %d) -> ", recipe.Servings))
+						if servings > 0 && servings != recipe.Servings {
+							units := ui.Selection("Which units would you like the quantities in?", unitSystems)
+							if units == unitSystems[0] {
+								units = ""
+							}
+							scaled, err := httpClient.ScaleRecipe(recipe.Name, servings, strings.ToLower(units))
+							if err != nil {
+								fmt.Printf("Something went wrong when we tried to scale the recipe: %v\n", err)
+							} else if scaled != nil {
+								fmt.Printf("\nRecipe for %d servings:\n%s\n", servings, scaled)
+							}
+						}
+					}
+					similar, err := httpClient.GetSimilarRecipes(recipe.Name, 0)
+					if err != nil {
+						fmt.Printf("Something went wrong when we tried to find similar recipes: %v\n", err)
+					} else if len(similar) > 0 {
+						fmt.Println("You might also like:")
+						for _, v := range similar {
+							fmt.Printf("  %s (%.0f%% similar, shares %s)\n", v.Name, v.Similarity*100, strings.Join(v.SharedIngredients, ", "))
+						}
+					}
+				}
+			}
+		case "Delete a recipe":
+			fmt.Println("Deleting a recipe:")
+			name := ui.GetValue("Enter name of recipe -> ")
+			fmt.Println()
+
+			found, err := httpClient.DeleteRecipe(name)
+			if err != nil {
+				fmt.Printf("Something went wrong when we tried to delete the recipe: %v\n", err)
+			} else {
+				if !found {
+					fmt.Printf("Sorry, no recipe for %s found\n", name)
+				} else {
+					fmt.Printf("%s deleted successfully\n", name)
 				}
 			}
 		case "Search by ingredients":
@@ -67,7 +164,7 @@ func main() {
 			ingredients := []string{}
 			addIngredients := true
 			for addIngredients {
-				ingredient := ui.GetValue("Enter ingredient name (blank to stop) -> ")
+				ingredient := ui.GetCompletedValue("Enter ingredient name (end with ? for suggestions, blank to stop) -> ", completeIngredient)
 				if ingredient == "" {
 					addIngredients = false
 				} else {
@@ -76,23 +173,158 @@ func main() {
 					}
 				}
 			}
+			var exclude []string
+			excludeIngredients := true
+			for excludeIngredients {
+				ingredient := ui.GetCompletedValue("Enter ingredient to exclude (end with ? for suggestions, blank to stop) -> ", completeIngredient)
+				if ingredient == "" {
+					excludeIngredients = false
+				} else {
+					if !helpers.StringSliceContains(exclude, ingredient) {
+						exclude = append(exclude, ingredient)
+					}
+				}
+			}
+			query := ui.GetValue("Enter an ingredient query such as Tomato AND (Basil OR Oregano) (blank for none) -> ")
+			mode := http.MatchAll
+			maxMissing := 0
+			switch ui.Selection("Which recipes would you like to find?", matchModes) {
+			case matchModes[1]:
+				mode = http.MatchAny
+			case matchModes[2]:
+				mode = http.MatchSubset
+				maxMissing = ui.GetNumber("How many other ingredients may be missing? (blank for none) -> ")
+			}
+			expand := ui.Selection("Should kinds of the ingredients match too, such as Mozzarella for Cheese?", []string{"Yes", "No"}) == "Yes"
 			fmt.Println()
 			fmt.Printf("Searching for recipes that make use of %+v\n", ingredients)
+			if len(exclude) > 0 {
+				fmt.Printf("and do not use any of %+v\n", exclude)
+			}
+			if query != "" {
+				fmt.Printf("and match %s\n", query)
+			}
 
-			recipes, err := httpClient.SearchByIngredients(ingredients)
+			search := http.Search{Ingredients: ingredients, Exclude: exclude, Mode: mode, MaxMissing: maxMissing, Query: query, Expand: expand}
+			results, err := httpClient.SearchRecipes(search)
+			if err == nil && len(results.Recipes) == 0 && len(results.Suggestions) > 0 {
+				for _, s := range results.Suggestions {
+					fmt.Printf("No recipe uses %s, did you mean %s?\n", s.Name, strings.Join(s.Suggestions, " or "))
+				}
+				fmt.Println()
+				if ui.Selection("Would you like to search for the closest ingredients instead?", []string{"Yes", "No"}) == "Yes" {
+					search.Fuzzy = true
+					results, err = httpClient.SearchRecipes(search)
+					fmt.Println()
+				}
+			}
+			recipes, matches := results.Recipes, results.Matches
 			if err != nil {
 				fmt.Printf("Something went wrong when we tried to find the recipes: %v\n", err)
 			} else {
 				if len(recipes) == 0 {
 					fmt.Printf("Sorry, no recipes found using these ingredients\n")
 				} else {
-					fmt.Printf("Found the following %d recipes using these ingredients:\n\n", len(recipes))
-					for _, r := range recipes {
+					fmt.Printf("Found the following %d recipes using these ingredients, best matches first:\n\n", len(recipes))
+					for i, r := range recipes {
 						fmt.Println(r)
+						if i < len(matches) {
+							fmt.Printf("(%s)\n", matches[i])
+						}
 						fmt.Println()
 					}
 				}
 			}
+		case "Search by name":
+			fmt.Println("Finding a recipe by name:")
+			name := ui.GetValue("Enter all or part of the name of the recipe -> ")
+			match := http.NamePrefix
+			if ui.Selection("Which recipes would you like to find?", nameMatches) == nameMatches[1] {
+				match = http.NameSubstring
+			}
+			fmt.Println()
+			count := 0
+			token := ""
+			for {
+				recipes, next, err := httpClient.SearchRecipesByName(name, match, 10, token)
+				if err != nil {
+					fmt.Printf("Something went wrong when we tried to find the recipes: %v\n", err)
+					break
+				}
+				for _, r := range recipes {
+					fmt.Println(r)
+					fmt.Println()
+				}
+				count += len(recipes)
+				if next == "" || ui.GetValue("Press enter for more recipes (q to stop) -> ") == "q" {
+					break
+				}
+				token = next
+			}
+			if count == 0 {
+				fmt.Printf("Sorry, no recipes called %s found\n", name)
+			}
+		case "List all recipes":
+			fmt.Println("Listing all recipes:")
+			fmt.Println()
+			count := 0
+			token := ""
+			for {
+				recipes, next, err := httpClient.ListRecipes(10, token)
+				if err != nil {
+					fmt.Printf("Something went wrong when we tried to list the recipes: %v\n", err)
+					break
+				}
+				for _, r := range recipes {
+					fmt.Println(r)
+					fmt.Println()
+				}
+				count += len(recipes)
+				if next == "" || ui.GetValue("Press enter for more recipes (q to stop) -> ") == "q" {
+					break
+				}
+				token = next
+			}
+			if count == 0 {
+				fmt.Printf("Sorry, no recipes found\n")
+			}
+		case "List all ingredients":
+			fmt.Println("Listing all ingredients:")
+			fmt.Println()
+			ingredients, err := httpClient.ListIngredients()
+			if err != nil {
+				fmt.Printf("Something went wrong when we tried to list the ingredients: %v\n", err)
+			} else {
+				if len(ingredients) == 0 {
+					fmt.Printf("Sorry, no ingredients found\n")
+				}
+				for _, v := range ingredients {
+					fmt.Printf("%s (recipes: %d)\n", v.Name, v.Recipes)
+				}
+			}
+		case "Show statistics":
+			fmt.Println("Showing statistics:")
+			fmt.Println()
+			stats, err := httpClient.GetStats()
+			if err != nil {
+				fmt.Printf("Something went wrong when we tried to get the statistics: %v\n", err)
+			} else {
+				fmt.Printf("Recipes: %d\n", stats.Recipes)
+				fmt.Printf("Ingredients: %d\n", stats.Ingredients)
+				fmt.Printf("Average ingredients per recipe: %.1f\n", stats.AverageIngredients)
+				fmt.Println("Most used ingredients:")
+				for _, v := range stats.MostUsed {
+					fmt.Printf("  %s (recipes: %d)\n", v.Name, v.Recipes)
+				}
+				fmt.Println("Least used ingredients:")
+				for _, v := range stats.LeastUsed {
+					fmt.Printf("  %s (recipes: %d)\n", v.Name, v.Recipes)
+				}
+				fmt.Println("Recipes which share no ingredients with any other:")
+				for _, v := range stats.IsolatedRecipes {
+					fmt.Printf("  %s\n", v)
+				}
+			}
 		case "Run Benchmarks":
 			httpClient.Benchmarks(1 * time.Minute)
 		case "Quit":
@@ -0,0 +1 @@
+go build -o ../../bin/migrate.exe main.go
\ No newline at end of file
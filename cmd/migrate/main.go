@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	config "go-incubator/internal/configuration"
+	"go-incubator/internal/persistence/mysqldb"
+	"go-incubator/internal/persistence/sqlitedb"
+)
+
+const usage = "usage: migrate [up | down [steps] | version]"
+
+// migrator is implemented by the databases which support schema migrations
+type migrator interface {
+	MigrateUp(context.Context) error
+	MigrateDown(context.Context, int) error
+	SchemaVersion(context.Context) (int, error)
+}
+
+func main() {
+	cfg, err := config.ReadConfig("INCUBATOR_")
+	if err != nil {
+		fmt.Printf("error reading config: %v\n", err)
+		return
+	}
+
+	var db migrator
+	switch cfg.Database.DBMS {
+	case "mysql":
+		imp, err := mysqldb.OpenMySqlDB(cfg.Database.ConString)
+		if err != nil {
+			fmt.Printf("error opening mysql database: %v\n", err)
+			return
+		}
+		db = &imp
+	case "sqlite":
+		imp, err := sqlitedb.OpenSqliteDB(cfg.Database.ConString)
+		if err != nil {
+			fmt.Printf("error opening sqlite database: %v\n", err)
+			return
+		}
+		defer imp.Close()
+		db = &imp
+	default:
+		fmt.Printf("schema migrations are not supported for DBMS (%s)\n", cfg.Database.DBMS)
+		return
+	}
+
+	command := "up"
+	if len(os.Args) > 1 {
+		command = os.Args[1]
+	}
+
+	ctx := context.Background()
+	switch command {
+	case "up":
+		err = db.MigrateUp(ctx)
+	case "down":
+		steps := 1
+		if len(os.Args) > 2 {
+			steps, err = strconv.Atoi(os.Args[2])
+			if err != nil || steps < 1 {
+				fmt.Printf("invalid number of steps (%s)\n", os.Args[2])
+				return
+			}
+		}
+		err = db.MigrateDown(ctx, steps)
+	case "version":
+	default:
+		fmt.Println(usage)
+		return
+	}
+	if err != nil {
+		fmt.Printf("error migrating database: %v\n", err)
+		return
+	}
+
+	version, err := db.SchemaVersion(ctx)
+	if err != nil {
+		fmt.Printf("error reading schema version: %v\n", err)
+		return
+	}
+	fmt.Printf("schema version %d\n", version)
+}
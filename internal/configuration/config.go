@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type Configuration struct {
@@ -11,12 +13,22 @@ type Configuration struct {
 	Address  string
 	GrpcPort int
 	ApiKey   string
+	// AdminKey is the key which clients must also present to change the taxonomy, which
+	// nobody may do if it is empty
+	AdminKey string
 	Database DBConfig
+	Synonyms [][]string
+	// Taxonomy is the path of the JSON file holding the ingredient taxonomy, if any
+	Taxonomy string
 }
 
 type DBConfig struct {
-	DBMS      string
-	ConString string
+	DBMS             string
+	ConString        string
+	SyncInterval     time.Duration
+	SnapshotInterval time.Duration
+	CacheSize        int
+	CacheTTL         time.Duration
 }
 
 func ReadConfig(prefix string) (Configuration, error) {
@@ -51,10 +63,66 @@ func ReadConfig(prefix string) (Configuration, error) {
 
 	cfg.ApiKey = os.Getenv(prefix + "APIKEY")
 
+	// Changing the taxonomy is disabled unless an admin key is provided
+	cfg.AdminKey = os.Getenv(prefix + "ADMINKEY")
+
 	cfg.Database = DBConfig{
 		DBMS:      os.Getenv(prefix + "DBMS"),
 		ConString: os.Getenv(prefix + "CONSTRING"),
 	}
 
+	// Sync and snapshot intervals are only used by the file backed database, and default to 0
+	if d := os.Getenv(prefix + "SYNCINTERVAL"); d != "" {
+		cfg.Database.SyncInterval, err = time.ParseDuration(d)
+		if err != nil {
+			return Configuration{}, fmt.Errorf("unable to parse value for %sSYNCINTERVAL (%s)", prefix, d)
+		}
+	}
+
+	if d := os.Getenv(prefix + "SNAPSHOTINTERVAL"); d != "" {
+		cfg.Database.SnapshotInterval, err = time.ParseDuration(d)
+		if err != nil {
+			return Configuration{}, fmt.Errorf("unable to parse value for %sSNAPSHOTINTERVAL (%s)", prefix, d)
+		}
+	}
+
+	// Caching is disabled unless a cache size is provided, and cached results never expire unless a TTL is provided
+	if p := os.Getenv(prefix + "CACHESIZE"); p != "" {
+		size, err := strconv.ParseInt(p, 10, 64)
+		if err != nil || size < 0 {
+			return Configuration{}, fmt.Errorf("unable to parse value for %sCACHESIZE (%s)", prefix, p)
+		}
+		cfg.Database.CacheSize = int(size)
+	}
+
+	if d := os.Getenv(prefix + "CACHETTL"); d != "" {
+		cfg.Database.CacheTTL, err = time.ParseDuration(d)
+		if err != nil || cfg.Database.CacheTTL < 0 {
+			return Configuration{}, fmt.Errorf("unable to parse value for %sCACHETTL (%s)", prefix, d)
+		}
+	}
+
+	// Synonyms are groups of names for the same ingredient, such as "Coriander=Cilantro;Aubergine=Eggplant"
+	if v := os.Getenv(prefix + "SYNONYMS"); v != "" {
+		for _, g := range strings.Split(v, ";") {
+			if strings.TrimSpace(g) == "" {
+				continue
+			}
+			var group []string
+			for _, name := range strings.Split(g, "=") {
+				if name = strings.TrimSpace(name); name != "" {
+					group = append(group, name)
+				}
+			}
+			if len(group) < 2 {
+				return Configuration{}, fmt.Errorf("unable to parse value for %sSYNONYMS (%s)", prefix, v)
+			}
+			cfg.Synonyms = append(cfg.Synonyms, group)
+		}
+	}
+
+	// The taxonomy file is created when the taxonomy is first changed, so it need not exist yet
+	cfg.Taxonomy = os.Getenv(prefix + "TAXONOMY")
+
 	return cfg, nil
 }
@@ -4,6 +4,7 @@ import (
 	"os"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestReadConfig(t *testing.T) {
@@ -11,9 +12,23 @@ func TestReadConfig(t *testing.T) {
 	os.Setenv("TEST_HTTPPORT", "1234")
 	os.Setenv("TEST_GRPCPORT", "4321")
 	os.Setenv("TEST_APIKEY", "1234")
+	os.Setenv("TEST_ADMINKEY", "5678")
 	os.Setenv("TEST_DBMS", "inmem")
 	os.Setenv("INVALID1_HTTPPORT", "abcd")
 	os.Setenv("INVALID2_GRPCPORT", "abcd")
+	os.Setenv("FILE_DBMS", "file")
+	os.Setenv("FILE_CONSTRING", "/var/lib/incubator")
+	os.Setenv("FILE_SYNCINTERVAL", "1s")
+	os.Setenv("FILE_SNAPSHOTINTERVAL", "5m")
+	os.Setenv("INVALID3_SYNCINTERVAL", "abcd")
+	os.Setenv("INVALID4_SNAPSHOTINTERVAL", "5")
+	os.Setenv("CACHE_CACHESIZE", "1000")
+	os.Setenv("CACHE_CACHETTL", "30s")
+	os.Setenv("INVALID5_CACHESIZE", "-1")
+	os.Setenv("INVALID6_CACHETTL", "abcd")
+	os.Setenv("SYNONYMS_SYNONYMS", "Coriander = Cilantro;;Aubergine=Eggplant=Brinjal;")
+	os.Setenv("INVALID7_SYNONYMS", "Coriander=Cilantro;Aubergine")
+	os.Setenv("TAXONOMY_TAXONOMY", "/etc/incubator/taxonomy.json")
 
 	type args struct {
 		prefix string
@@ -50,10 +65,84 @@ func TestReadConfig(t *testing.T) {
 				HttpPort: 1234,
 				GrpcPort: 4321,
 				ApiKey:   "1234",
+				AdminKey: "5678",
 				Database: DBConfig{DBMS: "inmem"},
 			},
 			wantErr: false,
 		},
+		{
+			name: "5",
+			args: args{"FILE_"},
+			want: Configuration{
+				Address:  "127.0.0.1",
+				HttpPort: 80,
+				GrpcPort: 80,
+				Database: DBConfig{DBMS: "file", ConString: "/var/lib/incubator", SyncInterval: time.Second, SnapshotInterval: 5 * time.Minute},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "6",
+			args:    args{"INVALID3_"},
+			want:    Configuration{},
+			wantErr: true,
+		},
+		{
+			name:    "7",
+			args:    args{"INVALID4_"},
+			want:    Configuration{},
+			wantErr: true,
+		},
+		{
+			name: "8",
+			args: args{"CACHE_"},
+			want: Configuration{
+				Address:  "127.0.0.1",
+				HttpPort: 80,
+				GrpcPort: 80,
+				Database: DBConfig{CacheSize: 1000, CacheTTL: 30 * time.Second},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "9",
+			args:    args{"INVALID5_"},
+			want:    Configuration{},
+			wantErr: true,
+		},
+		{
+			name:    "10",
+			args:    args{"INVALID6_"},
+			want:    Configuration{},
+			wantErr: true,
+		},
+		{
+			name: "11",
+			args: args{"SYNONYMS_"},
+			want: Configuration{
+				Address:  "127.0.0.1",
+				HttpPort: 80,
+				GrpcPort: 80,
+				Synonyms: [][]string{{"Coriander", "Cilantro"}, {"Aubergine", "Eggplant", "Brinjal"}},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "12",
+			args:    args{"INVALID7_"},
+			want:    Configuration{},
+			wantErr: true,
+		}, {
+			name: "13",
+			args: args{"TAXONOMY_"},
+			want: Configuration{
+				Address:  "127.0.0.1",
+				HttpPort: 80,
+				GrpcPort: 80,
+				Taxonomy: "/etc/incubator/taxonomy.json",
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -12,6 +12,7 @@ import (
 	"google.golang.org/grpc/codes"
 	grpcMetadata "google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 type GrpcClient struct {
@@ -59,10 +60,7 @@ func (c *GrpcClient) auth(
 func (c *GrpcClient) AddRecipe(recipe http.Recipe) error {
 	_, err := c.client.AddRecipe(
 		context.Background(),
-		&proto.Recipe{
-			Name:        recipe.Name,
-			Ingredients: recipe.Ingredients,
-		},
+		recipeToProto(recipe),
 	)
 	if err != nil {
 		return fmt.Errorf("calling gRPC function: %w", err)
@@ -73,45 +71,307 @@ func (c *GrpcClient) AddRecipe(recipe http.Recipe) error {
 
 // GetRecipe calls the `RecipeService/GetRecipe` gRPC function
 func (c *GrpcClient) GetRecipe(name string) (*http.Recipe, error) {
-	rsp, err := c.client.GetRecipe(
+	recipe, _, err := c.LookupRecipe(name, false)
+
+	return recipe, err
+}
+
+// LookupRecipe calls the `RecipeService/GetRecipe` gRPC function, optionally for the recipe with the closest
+// name. If there is no such recipe, it returns a nil recipe and the names of similar recipes, closest first.
+func (c *GrpcClient) LookupRecipe(name string, fuzzy bool) (*http.Recipe, []string, error) {
+	return c.lookupRecipe(&proto.RecipeRequest{Name: name, Fuzzy: fuzzy})
+}
+
+// ExpandRecipe calls the `RecipeService/GetRecipe` gRPC function with the components of the recipe replaced
+// by their ingredients. It returns a nil recipe if there is no such recipe.
+func (c *GrpcClient) ExpandRecipe(name string) (*http.Recipe, error) {
+	recipe, _, err := c.lookupRecipe(&proto.RecipeRequest{Name: name, Expand: true})
+
+	return recipe, err
+}
+
+// ScaleRecipe calls the `RecipeService/GetRecipe` gRPC function with the quantities of the recipe scaled to the
+// servings (unless 0) and converted to "metric" or "imperial" units (unless empty). It returns a nil recipe if
+// there is no such recipe.
+func (c *GrpcClient) ScaleRecipe(name string, servings int, units string) (*http.Recipe, error) {
+	recipe, _, err := c.lookupRecipe(&proto.RecipeRequest{Name: name, Servings: int32(servings), Units: units})
+
+	return recipe, err
+}
+
+// lookupRecipe calls the `RecipeService/GetRecipe` gRPC function, returning the suggestions of a NotFound error
+func (c *GrpcClient) lookupRecipe(r *proto.RecipeRequest) (*http.Recipe, []string, error) {
+	rsp, err := c.client.GetRecipe(context.Background(), r)
+	if err != nil {
+		if e, ok := status.FromError(err); ok {
+			if e.Code() == codes.NotFound {
+				var suggestions []string
+				for _, detail := range e.Details() {
+					if s, ok := detail.(*proto.Suggestion); ok {
+						suggestions = append(suggestions, s.Suggestions...)
+					}
+				}
+				return nil, suggestions, nil
+			}
+		}
+		return nil, nil, fmt.Errorf("calling gRPC function: %w", err)
+	}
+
+	recipe := recipeFromProto(rsp)
+
+	return &recipe, nil, nil
+}
+
+// DeleteRecipe calls the `RecipeService/DeleteRecipe` gRPC function, returning false if no such recipe exists
+func (c *GrpcClient) DeleteRecipe(name string) (bool, error) {
+	_, err := c.client.DeleteRecipe(
 		context.Background(),
 		&proto.RecipeRequest{Name: name},
 	)
 	if err != nil {
 		if e, ok := status.FromError(err); ok {
 			if e.Code() == codes.NotFound {
-				return nil, nil
+				return false, nil
 			}
 		}
-		return nil, fmt.Errorf("calling gRPC function: %w", err)
+		return false, fmt.Errorf("calling gRPC function: %w", err)
 	}
 
-	return &http.Recipe{
-		Name:        rsp.Name,
-		Ingredients: rsp.Ingredients,
-	}, nil
+	return true, nil
 }
 
 // SearchByIngredients calls the `RecipeService/FindRecipes` gRPC function
 func (c *GrpcClient) SearchByIngredients(ingredients []string) ([]http.Recipe, error) {
-	var recipes []http.Recipe
+	recipes, err := c.SearchRecipes(http.Search{Ingredients: ingredients})
+
+	return recipes.Recipes, err
+}
+
+// SearchRecipes calls the `RecipeService/FindRecipes` gRPC function with a match mode and exclusions, returning the
+// recipes found, how each of them matched and suggestions for any unknown ingredients
+func (c *GrpcClient) SearchRecipes(search http.Search) (http.Recipes, error) {
+	var recipes http.Recipes
+
+	mode := search.Mode
+	if mode == "" {
+		mode = http.MatchAll
+	}
+	value, ok := proto.MatchMode_value[string(mode)]
+	if !ok {
+		return http.Recipes{}, fmt.Errorf("unknown match mode (%s)", mode)
+	}
 
 	rsp, err := c.client.FindRecipes(
 		context.Background(),
-		&proto.FindRequest{Ingredients: ingredients},
+		&proto.FindRequest{
+			Ingredients: search.Ingredients,
+			Exclude:     search.Exclude,
+			Query:       search.Query,
+			Mode:        proto.MatchMode(value),
+			MaxMissing:  int32(search.MaxMissing),
+			Fuzzy:       search.Fuzzy,
+			Expand:      search.Expand,
+		},
 	)
 	if err != nil {
-		return nil, fmt.Errorf("calling gRPC function: %w", err)
+		return http.Recipes{}, fmt.Errorf("calling gRPC function: %w", err)
 	}
 
-	// Convert *proto.Recipes to []http.Recipe
+	// Convert *proto.Recipes to http.Recipes
 	for _, r := range rsp.Recipes {
-		recipes = append(recipes, http.Recipe{Name: r.Name, Ingredients: r.Ingredients})
+		recipes.Recipes = append(recipes.Recipes, recipeFromProto(r))
+	}
+	for _, m := range rsp.Matches {
+		recipes.Matches = append(recipes.Matches, http.Match{
+			Recipe:             m.Recipe,
+			MissingIngredients: m.MissingIngredients,
+			MatchedIngredients: m.MatchedIngredients,
+			Matched:            int(m.Matched),
+			Missing:            int(m.Missing),
+			Extra:              int(m.Extra),
+			Score:              m.Score,
+		})
+	}
+	for _, s := range rsp.Suggestions {
+		recipes.Suggestions = append(recipes.Suggestions, http.Suggestion{Name: s.Name, Suggestions: s.Suggestions})
 	}
 
 	return recipes, nil
 }
 
+// ListRecipes calls the `RecipeService/ListRecipes` gRPC function, returning a page of recipes and
+// the token of the next page, which is empty on the last page
+func (c *GrpcClient) ListRecipes(pageSize int, pageToken string) ([]http.Recipe, string, error) {
+	var recipes []http.Recipe
+
+	rsp, err := c.client.ListRecipes(
+		context.Background(),
+		&proto.ListRequest{PageSize: int32(pageSize), PageToken: pageToken},
+	)
+	if err != nil {
+		return nil, "", fmt.Errorf("calling gRPC function: %w", err)
+	}
+
+	// Convert *proto.RecipePage to []http.Recipe
+	for _, r := range rsp.Recipes {
+		recipes = append(recipes, recipeFromProto(r))
+	}
+
+	return recipes, rsp.NextPageToken, nil
+}
+
+// SearchRecipesByName calls the `RecipeService/SearchRecipesByName` gRPC function, returning a page of the recipes
+// whose names start with or contain name and the token of the next page, which is empty on the last page
+func (c *GrpcClient) SearchRecipesByName(name string, match http.NameMatch, pageSize int, pageToken string) ([]http.Recipe, string, error) {
+	var recipes []http.Recipe
+
+	if match == "" {
+		match = http.NamePrefix
+	}
+	value, ok := proto.NameMatch_value[string(match)]
+	if !ok {
+		return nil, "", fmt.Errorf("unknown name match (%s)", match)
+	}
+
+	rsp, err := c.client.SearchRecipesByName(
+		context.Background(),
+		&proto.NameSearchRequest{Name: name, Match: proto.NameMatch(value), PageSize: int32(pageSize), PageToken: pageToken},
+	)
+	if err != nil {
+		return nil, "", fmt.Errorf("calling gRPC function: %w", err)
+	}
+
+	// Convert *proto.RecipePage to []http.Recipe
+	for _, r := range rsp.Recipes {
+		recipes = append(recipes, recipeFromProto(r))
+	}
+
+	return recipes, rsp.NextPageToken, nil
+}
+
+// ListIngredients calls the `RecipeService/ListIngredients` gRPC function, returning all ingredients which
+// recipes use with the number of recipes using each, in name order
+func (c *GrpcClient) ListIngredients() ([]http.IngredientUsage, error) {
+	rsp, err := c.client.ListIngredients(context.Background(), &emptypb.Empty{})
+	if err != nil {
+		return nil, fmt.Errorf("calling gRPC function: %w", err)
+	}
+
+	return ingredientsFromProto(rsp), nil
+}
+
+// CompleteIngredient calls the `RecipeService/CompleteIngredient` gRPC function, returning up to limit
+// ingredients whose names start with prefix, the most used first
+func (c *GrpcClient) CompleteIngredient(prefix string, limit int) ([]http.IngredientUsage, error) {
+	rsp, err := c.client.CompleteIngredient(context.Background(), &proto.CompleteRequest{Prefix: prefix, Limit: int32(limit)})
+	if err != nil {
+		return nil, fmt.Errorf("calling gRPC function: %w", err)
+	}
+
+	return ingredientsFromProto(rsp), nil
+}
+
+// GetSimilarRecipes calls the `RecipeService/GetSimilarRecipes` gRPC function, returning up to limit recipes
+// which share ingredients with the named recipe, the most similar first
+func (c *GrpcClient) GetSimilarRecipes(name string, limit int) ([]http.SimilarRecipe, error) {
+	rsp, err := c.client.GetSimilarRecipes(context.Background(), &proto.SimilarRequest{Name: name, Limit: int32(limit)})
+	if err != nil {
+		return nil, fmt.Errorf("calling gRPC function: %w", err)
+	}
+
+	similar := []http.SimilarRecipe{}
+	for _, v := range rsp.Recipes {
+		similar = append(similar, http.SimilarRecipe{Name: v.Name, Similarity: v.Similarity, SharedIngredients: v.SharedIngredients})
+	}
+
+	return similar, nil
+}
+
+// GetStats calls the `RecipeService/GetStats` gRPC function, returning statistics about all recipes
+func (c *GrpcClient) GetStats() (http.Stats, error) {
+	rsp, err := c.client.GetStats(context.Background(), &emptypb.Empty{})
+	if err != nil {
+		return http.Stats{}, fmt.Errorf("calling gRPC function: %w", err)
+	}
+
+	return http.Stats{
+		Recipes:            int(rsp.Recipes),
+		Ingredients:        int(rsp.Ingredients),
+		MostUsed:           ingredientsFromProto(&proto.Ingredients{Ingredients: rsp.MostUsed}),
+		LeastUsed:          ingredientsFromProto(&proto.Ingredients{Ingredients: rsp.LeastUsed}),
+		AverageIngredients: rsp.AverageIngredients,
+		IsolatedRecipes:    rsp.IsolatedRecipes,
+	}, nil
+}
+
+// ingredientsFromProto converts *proto.Ingredients to []http.IngredientUsage
+func ingredientsFromProto(r *proto.Ingredients) []http.IngredientUsage {
+	var ingredients []http.IngredientUsage
+	for _, v := range r.Ingredients {
+		ingredients = append(ingredients, http.IngredientUsage{Name: v.Name, Recipes: int(v.Recipes)})
+	}
+
+	return ingredients
+}
+
+// recipeToProto converts an http.Recipe to a *proto.Recipe, sending the ingredient names
+// as well so that servers which do not know about structured ingredients still get them
+func recipeToProto(r http.Recipe) *proto.Recipe {
+	recipe := &proto.Recipe{
+		Name:         r.Name,
+		Ingredients:  r.IngredientNames(),
+		Description:  r.Description,
+		Instructions: r.Instructions,
+		Servings:     int32(r.Servings),
+		PrepMinutes:  int32(r.PrepMinutes),
+		CookMinutes:  int32(r.CookMinutes),
+		Source:       r.Source,
+	}
+	for _, ingredient := range r.Ingredients {
+		recipe.StructuredIngredients = append(recipe.StructuredIngredients, &proto.Ingredient{
+			Name:      ingredient.Name,
+			Quantity:  ingredient.Quantity,
+			Unit:      ingredient.Unit,
+			Note:      ingredient.Note,
+			Component: ingredient.Component,
+		})
+	}
+
+	return recipe
+}
+
+// recipeFromProto converts a *proto.Recipe to an http.Recipe, falling back to the
+// ingredient names if the server did not send structured ingredients
+func recipeFromProto(r *proto.Recipe) http.Recipe {
+	recipe := http.Recipe{
+		Name:         r.Name,
+		Description:  r.Description,
+		Instructions: r.Instructions,
+		Servings:     int(r.Servings),
+		PrepMinutes:  int(r.PrepMinutes),
+		CookMinutes:  int(r.CookMinutes),
+		Source:       r.Source,
+	}
+	if len(r.StructuredIngredients) == 0 {
+		for _, name := range r.Ingredients {
+			recipe.Ingredients = append(recipe.Ingredients, http.Ingredient{Name: name})
+		}
+		return recipe
+	}
+
+	for _, ingredient := range r.StructuredIngredients {
+		recipe.Ingredients = append(recipe.Ingredients, http.Ingredient{
+			Name:      ingredient.GetName(),
+			Quantity:  ingredient.GetQuantity(),
+			Unit:      ingredient.GetUnit(),
+			Note:      ingredient.GetNote(),
+			Component: ingredient.GetComponent(),
+		})
+	}
+
+	return recipe
+}
+
 func (c *GrpcClient) Benchmarks(duration time.Duration) {
 	numRoutines := 100
 	fmt.Printf("Calling SearchByIngredients([]string{\"Tomato\"}) on %d concurrent routines for %s, please wait\n", numRoutines, duration)
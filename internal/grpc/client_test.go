@@ -31,6 +31,9 @@ func (s *mockServer) AddRecipe(ctx context.Context, r *proto.Recipe) (*emptypb.E
 	if r.Name == "expect error" {
 		return &emptypb.Empty{}, status.Errorf(codes.Internal, "expected error")
 	}
+	if len(r.Ingredients) != len(r.StructuredIngredients) {
+		return &emptypb.Empty{}, status.Errorf(codes.InvalidArgument, "ingredient names and structured ingredients differ")
+	}
 
 	return &emptypb.Empty{}, nil
 }
@@ -39,6 +42,45 @@ func (s *mockServer) GetRecipe(ctx context.Context, r *proto.RecipeRequest) (*pr
 	switch r.Name {
 	case "BLT":
 		return &proto.Recipe{Name: "BLT", Ingredients: []string{"Bacon", "Lettuce", "Tomato"}}, nil
+	case "Pancakes":
+		return &proto.Recipe{
+			Name:                  "Pancakes",
+			Ingredients:           []string{"Flour", "Egg"},
+			StructuredIngredients: []*proto.Ingredient{{Name: "Flour", Quantity: 2, Unit: "cups", Note: "sifted"}, {Name: "Egg", Quantity: 1}},
+			Instructions:          []string{"Whisk.", "Fry."},
+			Servings:              4,
+			CookMinutes:           20,
+		}, nil
+	case "Tomato Toast":
+		if r.Expand {
+			return &proto.Recipe{Name: "Tomato Toast", StructuredIngredients: []*proto.Ingredient{{Name: "Bread"}, {Name: "Mozzarella"}, {Name: "Tomato"}}}, nil
+		}
+		return &proto.Recipe{Name: "Tomato Toast", StructuredIngredients: []*proto.Ingredient{{Name: "Bread"}, {Name: "Caprese Salad", Component: true}}}, nil
+	case "Scones":
+		if r.Servings == 0 && r.Units == "" {
+			return &proto.Recipe{Name: "Scones", StructuredIngredients: []*proto.Ingredient{{Name: "Flour", Quantity: 225, Unit: "g"}}, Servings: 8}, nil
+		}
+		if r.Servings == 16 && r.Units == "imperial" {
+			return &proto.Recipe{Name: "Scones", StructuredIngredients: []*proto.Ingredient{{Name: "Flour", Quantity: 1, Unit: "lb"}}, Servings: 16}, nil
+		}
+		return nil, status.Errorf(codes.FailedPrecondition, "unexpected request")
+	case "expect error":
+		return nil, status.Errorf(codes.Internal, "expected error")
+	case "blt":
+		if r.Fuzzy {
+			return &proto.Recipe{Name: "BLT", Ingredients: []string{"Bacon", "Lettuce", "Tomato"}}, nil
+		}
+		st, _ := status.New(codes.NotFound, "recipe (blt) not found").WithDetails(&proto.Suggestion{Name: "blt", Suggestions: []string{"BLT"}})
+		return nil, st.Err()
+	}
+
+	return nil, status.Errorf(codes.NotFound, "recipe (%s) not found", r.Name)
+}
+
+func (s *mockServer) DeleteRecipe(ctx context.Context, r *proto.RecipeRequest) (*emptypb.Empty, error) {
+	switch r.Name {
+	case "BLT":
+		return &emptypb.Empty{}, nil
 	case "expect error":
 		return nil, status.Errorf(codes.Internal, "expected error")
 	}
@@ -54,10 +96,97 @@ func (s *mockServer) FindRecipes(ctx context.Context, r *proto.FindRequest) (*pr
 		return &proto.Recipes{}, nil
 	case "expected error":
 		return nil, status.Errorf(codes.Internal, "expected error")
+	case "Mozarella Macaroni":
+		if r.Fuzzy {
+			return &proto.Recipes{
+				Recipes:     []*proto.Recipe{{Name: "Mac & Cheese", Ingredients: []string{"Mozzarella", "Macaroni"}}},
+				Matches:     []*proto.Match{{Recipe: "Mac & Cheese", MissingIngredients: []string{}, MatchedIngredients: []string{"Mozzarella", "Macaroni"}, Matched: 2, Score: 1}},
+				Suggestions: []*proto.Suggestion{{Name: "Mozarella", Suggestions: []string{"Mozzarella"}}},
+			}, nil
+		}
+	case "Cheese":
+		if r.Expand {
+			return &proto.Recipes{
+				Recipes: []*proto.Recipe{{Name: "Cheese Fondue", Ingredients: []string{"Gruyere", "Emmental"}}},
+				Matches: []*proto.Match{{Recipe: "Cheese Fondue", MissingIngredients: []string{"Emmental"}, MatchedIngredients: []string{"Gruyere"}, Matched: 1, Missing: 1, Score: 0.5}},
+			}, nil
+		}
+	case "Mozzarella Macaroni":
+		if r.Mode == proto.MatchMode_MATCH_SUBSET && r.MaxMissing == 1 && len(r.Exclude) == 0 && r.Query == "" {
+			return &proto.Recipes{
+				Recipes: []*proto.Recipe{{Name: "Caprese Salad", Ingredients: []string{"Mozzarella", "Tomato"}}},
+				Matches: []*proto.Match{{Recipe: "Caprese Salad", MissingIngredients: []string{"Tomato"}, MatchedIngredients: []string{"Mozzarella"}, Matched: 1, Missing: 1, Extra: 1, Score: 1.0 / 3}},
+			}, nil
+		}
 	}
 	return &proto.Recipes{}, nil
 }
 
+func (s *mockServer) ListRecipes(ctx context.Context, r *proto.ListRequest) (*proto.RecipePage, error) {
+	switch r.PageToken {
+	case "":
+		return &proto.RecipePage{Recipes: []*proto.Recipe{{Name: "one", Ingredients: []string{"oneone", "onetwo"}}}, NextPageToken: "next"}, nil
+	case "next":
+		return &proto.RecipePage{Recipes: []*proto.Recipe{{Name: "two", Ingredients: []string{"twoone", "twotwo"}}}}, nil
+	}
+
+	return nil, status.Errorf(codes.InvalidArgument, "invalid page token (%s)", r.PageToken)
+}
+func (s *mockServer) SearchRecipesByName(ctx context.Context, r *proto.NameSearchRequest) (*proto.RecipePage, error) {
+	switch {
+	case r.Name == "salad" && r.Match == proto.NameMatch_NAME_SUBSTRING && r.PageToken == "":
+		return &proto.RecipePage{Recipes: []*proto.Recipe{{Name: "Caprese Salad", Ingredients: []string{"Mozzarella", "Tomato"}}}, NextPageToken: "next"}, nil
+	case r.Name == "salad" && r.Match == proto.NameMatch_NAME_SUBSTRING && r.PageToken == "next":
+		return &proto.RecipePage{Recipes: []*proto.Recipe{{Name: "Greek Salad", Ingredients: []string{"Feta", "Tomato"}}}}, nil
+	case r.Name == "salad" && r.Match == proto.NameMatch_NAME_PREFIX:
+		return &proto.RecipePage{Recipes: []*proto.Recipe{}}, nil
+	}
+
+	return nil, status.Errorf(codes.InvalidArgument, "no name specified")
+}
+
+func (s *mockServer) ListIngredients(ctx context.Context, r *emptypb.Empty) (*proto.Ingredients, error) {
+	return &proto.Ingredients{Ingredients: []*proto.IngredientUsage{{Name: "Mozzarella", Recipes: 2}, {Name: "Tomato", Recipes: 5}}}, nil
+}
+
+func (s *mockServer) GetSimilarRecipes(ctx context.Context, r *proto.SimilarRequest) (*proto.SimilarRecipes, error) {
+	if r.Name != "SpagBol" {
+		return nil, status.Errorf(codes.NotFound, "recipe (%s) not found", r.Name)
+	}
+
+	return &proto.SimilarRecipes{Recipes: []*proto.SimilarRecipe{{Name: "Meatballs", Similarity: 2.0 / 3, SharedIngredients: []string{"Ground Beef", "Tomato"}}}}, nil
+}
+
+func (s *mockServer) GetStats(ctx context.Context, r *emptypb.Empty) (*proto.Stats, error) {
+	return &proto.Stats{
+		Recipes:            2,
+		Ingredients:        2,
+		MostUsed:           []*proto.IngredientUsage{{Name: "Tomato", Recipes: 2}},
+		LeastUsed:          []*proto.IngredientUsage{{Name: "Basil", Recipes: 1}},
+		AverageIngredients: 1.5,
+		IsolatedRecipes:    []string{},
+	}, nil
+}
+
+func (s *mockServer) CompleteIngredient(ctx context.Context, r *proto.CompleteRequest) (*proto.Ingredients, error) {
+	switch {
+	case r.Prefix == "to" && r.Limit == 10:
+		return &proto.Ingredients{Ingredients: []*proto.IngredientUsage{{Name: "Tomato", Recipes: 5}}}, nil
+	case r.Prefix == "x":
+		return &proto.Ingredients{}, nil
+	}
+
+	return nil, status.Errorf(codes.InvalidArgument, "invalid limit (%d)", r.Limit)
+}
+
+func (s *mockServer) GetTaxonomy(ctx context.Context, r *emptypb.Empty) (*proto.Taxonomy, error) {
+	return &proto.Taxonomy{}, nil
+}
+
+func (s *mockServer) SetIngredientKind(ctx context.Context, r *proto.Kind) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, nil
+}
+
 func bufDialer(context.Context, string) (net.Conn, error) {
 	return lis.Dial()
 }
@@ -191,13 +320,13 @@ func TestGrpcClient_AddRecipe(t *testing.T) {
 		{
 			name:    "1",
 			c:       &GrpcClient{client: client, apiKey: "1234"},
-			args:    args{recipe: http.Recipe{Name: "expect ok", Ingredients: []string{"one", "two", "three"}}},
+			args:    args{recipe: http.Recipe{Name: "expect ok", Ingredients: []http.Ingredient{{Name: "one"}, {Name: "two"}, {Name: "three"}}}},
 			wantErr: false,
 		},
 		{
 			name:    "2",
 			c:       &GrpcClient{client: client, apiKey: "1234"},
-			args:    args{recipe: http.Recipe{Name: "expect error", Ingredients: []string{"one", "two", "three"}}},
+			args:    args{recipe: http.Recipe{Name: "expect error", Ingredients: []http.Ingredient{{Name: "one"}, {Name: "two"}, {Name: "three"}}}},
 			wantErr: true,
 		},
 	}
@@ -233,7 +362,7 @@ func TestGrpcClient_GetRecipe(t *testing.T) {
 			name:    "1",
 			c:       &GrpcClient{client: client, apiKey: "1234"},
 			args:    args{name: "BLT"},
-			want:    &http.Recipe{Name: "BLT", Ingredients: []string{"Bacon", "Lettuce", "Tomato"}},
+			want:    &http.Recipe{Name: "BLT", Ingredients: []http.Ingredient{{Name: "Bacon"}, {Name: "Lettuce"}, {Name: "Tomato"}}},
 			wantErr: false,
 		},
 		{
@@ -250,6 +379,19 @@ func TestGrpcClient_GetRecipe(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name: "4",
+			c:    &GrpcClient{client: client, apiKey: "1234"},
+			args: args{name: "Pancakes"},
+			want: &http.Recipe{
+				Name:         "Pancakes",
+				Ingredients:  []http.Ingredient{{Name: "Flour", Quantity: 2, Unit: "cups", Note: "sifted"}, {Name: "Egg", Quantity: 1}},
+				Instructions: []string{"Whisk.", "Fry."},
+				Servings:     4,
+				CookMinutes:  20,
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -265,6 +407,237 @@ func TestGrpcClient_GetRecipe(t *testing.T) {
 	}
 }
 
+func TestGrpcClient_LookupRecipe(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+	defer conn.Close()
+	client := proto.NewRecipeServiceClient(conn)
+
+	tests := []struct {
+		name            string
+		c               *GrpcClient
+		rname           string
+		fuzzy           bool
+		want            *http.Recipe
+		wantSuggestions []string
+		wantErr         bool
+	}{
+		{
+			name:            "1",
+			c:               &GrpcClient{client: client, apiKey: "1234"},
+			rname:           "blt",
+			want:            nil,
+			wantSuggestions: []string{"BLT"},
+			wantErr:         false,
+		},
+		{
+			name:            "2",
+			c:               &GrpcClient{client: client, apiKey: "1234"},
+			rname:           "blt",
+			fuzzy:           true,
+			want:            &http.Recipe{Name: "BLT", Ingredients: []http.Ingredient{{Name: "Bacon"}, {Name: "Lettuce"}, {Name: "Tomato"}}},
+			wantSuggestions: nil,
+			wantErr:         false,
+		},
+		{
+			name:            "3",
+			c:               &GrpcClient{client: client, apiKey: "1234"},
+			rname:           "Bobotie",
+			want:            nil,
+			wantSuggestions: nil,
+			wantErr:         false,
+		},
+		{
+			name:            "4",
+			c:               &GrpcClient{client: client, apiKey: "1234"},
+			rname:           "expect error",
+			want:            nil,
+			wantSuggestions: nil,
+			wantErr:         true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotSuggestions, err := tt.c.LookupRecipe(tt.rname, tt.fuzzy)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GrpcClient.LookupRecipe() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) || !reflect.DeepEqual(gotSuggestions, tt.wantSuggestions) {
+				t.Errorf("GrpcClient.LookupRecipe() = %v, %v, want %v, %v", got, gotSuggestions, tt.want, tt.wantSuggestions)
+			}
+		})
+	}
+}
+
+func TestGrpcClient_ExpandRecipe(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+	defer conn.Close()
+	client := proto.NewRecipeServiceClient(conn)
+
+	tests := []struct {
+		name    string
+		c       *GrpcClient
+		rname   string
+		want    *http.Recipe
+		wantErr bool
+	}{
+		{
+			name:    "1",
+			c:       &GrpcClient{client: client, apiKey: "1234"},
+			rname:   "Tomato Toast",
+			want:    &http.Recipe{Name: "Tomato Toast", Ingredients: []http.Ingredient{{Name: "Bread"}, {Name: "Mozzarella"}, {Name: "Tomato"}}},
+			wantErr: false,
+		},
+		{
+			name:    "2",
+			c:       &GrpcClient{client: client, apiKey: "1234"},
+			rname:   "Bobotie",
+			want:    nil,
+			wantErr: false,
+		},
+		{
+			name:    "3",
+			c:       &GrpcClient{client: client, apiKey: "1234"},
+			rname:   "expect error",
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.c.ExpandRecipe(tt.rname)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GrpcClient.ExpandRecipe() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GrpcClient.ExpandRecipe() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGrpcClient_ScaleRecipe(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+	defer conn.Close()
+	client := proto.NewRecipeServiceClient(conn)
+
+	tests := []struct {
+		name     string
+		c        *GrpcClient
+		rname    string
+		servings int
+		units    string
+		want     *http.Recipe
+		wantErr  bool
+	}{
+		{
+			name:     "1",
+			c:        &GrpcClient{client: client, apiKey: "1234"},
+			rname:    "Scones",
+			servings: 16,
+			units:    "imperial",
+			want:     &http.Recipe{Name: "Scones", Ingredients: []http.Ingredient{{Name: "Flour", Quantity: 1, Unit: "lb"}}, Servings: 16},
+			wantErr:  false,
+		},
+		{
+			name:     "2",
+			c:        &GrpcClient{client: client, apiKey: "1234"},
+			rname:    "Bobotie",
+			servings: 2,
+			want:     nil,
+			wantErr:  false,
+		},
+		{
+			name:     "3",
+			c:        &GrpcClient{client: client, apiKey: "1234"},
+			rname:    "Scones",
+			servings: 2,
+			want:     nil,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.c.ScaleRecipe(tt.rname, tt.servings, tt.units)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GrpcClient.ScaleRecipe() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GrpcClient.ScaleRecipe() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGrpcClient_DeleteRecipe(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+	defer conn.Close()
+	client := proto.NewRecipeServiceClient(conn)
+
+	type args struct {
+		name string
+	}
+	tests := []struct {
+		name    string
+		c       *GrpcClient
+		args    args
+		want    bool
+		wantErr bool
+	}{
+		{
+			name:    "1",
+			c:       &GrpcClient{client: client, apiKey: "1234"},
+			args:    args{name: "BLT"},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name:    "2",
+			c:       &GrpcClient{client: client, apiKey: "1234"},
+			args:    args{name: "Bobotie"},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name:    "3",
+			c:       &GrpcClient{client: client, apiKey: "1234"},
+			args:    args{name: "expect error"},
+			want:    false,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.c.DeleteRecipe(tt.args.name)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GrpcClient.DeleteRecipe() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GrpcClient.DeleteRecipe() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGrpcClient_SearchByIngredients(t *testing.T) {
 	ctx := context.Background()
 	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithInsecure())
@@ -288,7 +661,7 @@ func TestGrpcClient_SearchByIngredients(t *testing.T) {
 			name:    "1",
 			c:       &GrpcClient{client: client, apiKey: "1234"},
 			args:    args{ingredients: []string{"expected", "ok"}},
-			want:    []http.Recipe{{Name: "one", Ingredients: []string{"oneone", "onetwo"}}, {Name: "two", Ingredients: []string{"twoone", "twotwo"}}},
+			want:    []http.Recipe{{Name: "one", Ingredients: []http.Ingredient{{Name: "oneone"}, {Name: "onetwo"}}}, {Name: "two", Ingredients: []http.Ingredient{{Name: "twoone"}, {Name: "twotwo"}}}},
 			wantErr: false,
 		},
 		{
@@ -320,6 +693,380 @@ func TestGrpcClient_SearchByIngredients(t *testing.T) {
 	}
 }
 
+func TestGrpcClient_SearchRecipes(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+	defer conn.Close()
+	client := proto.NewRecipeServiceClient(conn)
+
+	tests := []struct {
+		name            string
+		c               *GrpcClient
+		search          http.Search
+		want            []http.Recipe
+		wantMatches     []http.Match
+		wantSuggestions []http.Suggestion
+		wantErr         bool
+	}{
+		{
+			name:        "1",
+			c:           &GrpcClient{client: client, apiKey: "1234"},
+			search:      http.Search{Ingredients: []string{"Mozzarella", "Macaroni"}, Mode: http.MatchSubset, MaxMissing: 1},
+			want:        []http.Recipe{{Name: "Caprese Salad", Ingredients: []http.Ingredient{{Name: "Mozzarella"}, {Name: "Tomato"}}}},
+			wantMatches: []http.Match{{Recipe: "Caprese Salad", MissingIngredients: []string{"Tomato"}, MatchedIngredients: []string{"Mozzarella"}, Matched: 1, Missing: 1, Extra: 1, Score: 1.0 / 3}},
+			wantErr:     false,
+		},
+		{
+			name:        "2",
+			c:           &GrpcClient{client: client, apiKey: "1234"},
+			search:      http.Search{Ingredients: []string{"Mozzarella", "Macaroni"}, Mode: http.MatchAny},
+			want:        nil,
+			wantMatches: nil,
+			wantErr:     false,
+		},
+		{
+			name:        "3",
+			c:           &GrpcClient{client: client, apiKey: "1234"},
+			search:      http.Search{Ingredients: []string{"Mozzarella", "Macaroni"}, Mode: http.MatchMode("MATCH_SOME")},
+			want:        nil,
+			wantMatches: nil,
+			wantErr:     true,
+		},
+		{
+			name:        "4",
+			c:           &GrpcClient{client: client, apiKey: "1234"},
+			search:      http.Search{Ingredients: []string{"Mozzarella", "Macaroni"}, Exclude: []string{"Tomato"}, Mode: http.MatchSubset, MaxMissing: 1},
+			want:        nil,
+			wantMatches: nil,
+			wantErr:     false,
+		},
+		{
+			name:        "5",
+			c:           &GrpcClient{client: client, apiKey: "1234"},
+			search:      http.Search{Ingredients: []string{"Mozzarella", "Macaroni"}, Mode: http.MatchSubset, MaxMissing: 1, Query: "-Tomato"},
+			want:        nil,
+			wantMatches: nil,
+			wantErr:     false,
+		},
+		{
+			name:        "6",
+			c:           &GrpcClient{client: client, apiKey: "1234"},
+			search:      http.Search{Ingredients: []string{"expected", "error"}, Mode: http.MatchSubset, MaxMissing: 1},
+			want:        nil,
+			wantMatches: nil,
+			wantErr:     true,
+		},
+		{
+			name:            "7",
+			c:               &GrpcClient{client: client, apiKey: "1234"},
+			search:          http.Search{Ingredients: []string{"Mozarella", "Macaroni"}, Fuzzy: true},
+			want:            []http.Recipe{{Name: "Mac & Cheese", Ingredients: []http.Ingredient{{Name: "Mozzarella"}, {Name: "Macaroni"}}}},
+			wantMatches:     []http.Match{{Recipe: "Mac & Cheese", MatchedIngredients: []string{"Mozzarella", "Macaroni"}, Matched: 2, Score: 1}},
+			wantSuggestions: []http.Suggestion{{Name: "Mozarella", Suggestions: []string{"Mozzarella"}}},
+			wantErr:         false,
+		},
+		{
+			name:        "8",
+			c:           &GrpcClient{client: client, apiKey: "1234"},
+			search:      http.Search{Ingredients: []string{"Cheese"}, Expand: true},
+			want:        []http.Recipe{{Name: "Cheese Fondue", Ingredients: []http.Ingredient{{Name: "Gruyere"}, {Name: "Emmental"}}}},
+			wantMatches: []http.Match{{Recipe: "Cheese Fondue", MissingIngredients: []string{"Emmental"}, MatchedIngredients: []string{"Gruyere"}, Matched: 1, Missing: 1, Score: 0.5}},
+			wantErr:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.c.SearchRecipes(tt.search)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GrpcClient.SearchRecipes() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got.Recipes, tt.want) || !reflect.DeepEqual(got.Matches, tt.wantMatches) || !reflect.DeepEqual(got.Suggestions, tt.wantSuggestions) {
+				t.Errorf("GrpcClient.SearchRecipes() = %v, %v, %v, want %v, %v, %v", got.Recipes, got.Matches, got.Suggestions, tt.want, tt.wantMatches, tt.wantSuggestions)
+			}
+		})
+	}
+}
+
+func TestGrpcClient_ListRecipes(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+	defer conn.Close()
+	client := proto.NewRecipeServiceClient(conn)
+
+	type args struct {
+		pageSize  int
+		pageToken string
+	}
+	tests := []struct {
+		name      string
+		c         *GrpcClient
+		args      args
+		want      []http.Recipe
+		wantToken string
+		wantErr   bool
+	}{
+		{
+			name:      "1",
+			c:         &GrpcClient{client: client, apiKey: "1234"},
+			args:      args{pageSize: 1, pageToken: ""},
+			want:      []http.Recipe{{Name: "one", Ingredients: []http.Ingredient{{Name: "oneone"}, {Name: "onetwo"}}}},
+			wantToken: "next",
+			wantErr:   false,
+		},
+		{
+			name:      "2",
+			c:         &GrpcClient{client: client, apiKey: "1234"},
+			args:      args{pageSize: 1, pageToken: "next"},
+			want:      []http.Recipe{{Name: "two", Ingredients: []http.Ingredient{{Name: "twoone"}, {Name: "twotwo"}}}},
+			wantToken: "",
+			wantErr:   false,
+		},
+		{
+			name:      "3",
+			c:         &GrpcClient{client: client, apiKey: "1234"},
+			args:      args{pageSize: 1, pageToken: "invalid"},
+			want:      nil,
+			wantToken: "",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotToken, err := tt.c.ListRecipes(tt.args.pageSize, tt.args.pageToken)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GrpcClient.ListRecipes() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) || gotToken != tt.wantToken {
+				t.Errorf("GrpcClient.ListRecipes() = %v, %v, want %v, %v", got, gotToken, tt.want, tt.wantToken)
+			}
+		})
+	}
+}
+func TestGrpcClient_SearchRecipesByName(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+	defer conn.Close()
+	client := proto.NewRecipeServiceClient(conn)
+
+	type args struct {
+		name      string
+		match     http.NameMatch
+		pageSize  int
+		pageToken string
+	}
+	tests := []struct {
+		name      string
+		c         *GrpcClient
+		args      args
+		want      []http.Recipe
+		wantToken string
+		wantErr   bool
+	}{
+		{
+			name:      "1",
+			c:         &GrpcClient{client: client, apiKey: "1234"},
+			args:      args{name: "salad", match: http.NameSubstring, pageSize: 1, pageToken: ""},
+			want:      []http.Recipe{{Name: "Caprese Salad", Ingredients: []http.Ingredient{{Name: "Mozzarella"}, {Name: "Tomato"}}}},
+			wantToken: "next",
+			wantErr:   false,
+		},
+		{
+			name:      "2",
+			c:         &GrpcClient{client: client, apiKey: "1234"},
+			args:      args{name: "salad", match: http.NameSubstring, pageSize: 1, pageToken: "next"},
+			want:      []http.Recipe{{Name: "Greek Salad", Ingredients: []http.Ingredient{{Name: "Feta"}, {Name: "Tomato"}}}},
+			wantToken: "",
+			wantErr:   false,
+		},
+		{
+			name:      "3",
+			c:         &GrpcClient{client: client, apiKey: "1234"},
+			args:      args{name: "salad", match: "", pageSize: 1},
+			want:      nil,
+			wantToken: "",
+			wantErr:   false,
+		},
+		{
+			name:      "4",
+			c:         &GrpcClient{client: client, apiKey: "1234"},
+			args:      args{name: "salad", match: "NAME_SOUNDEX", pageSize: 1},
+			want:      nil,
+			wantToken: "",
+			wantErr:   true,
+		},
+		{
+			name:      "5",
+			c:         &GrpcClient{client: client, apiKey: "1234"},
+			args:      args{name: "", match: http.NamePrefix, pageSize: 1},
+			want:      nil,
+			wantToken: "",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotToken, err := tt.c.SearchRecipesByName(tt.args.name, tt.args.match, tt.args.pageSize, tt.args.pageToken)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GrpcClient.SearchRecipesByName() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) || gotToken != tt.wantToken {
+				t.Errorf("GrpcClient.SearchRecipesByName() = %v, %v, want %v, %v", got, gotToken, tt.want, tt.wantToken)
+			}
+		})
+	}
+}
+
+func TestGrpcClient_ListIngredients(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+	defer conn.Close()
+
+	c := &GrpcClient{client: proto.NewRecipeServiceClient(conn), apiKey: "1234"}
+	got, err := c.ListIngredients()
+	want := []http.IngredientUsage{{Name: "Mozzarella", Recipes: 2}, {Name: "Tomato", Recipes: 5}}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("GrpcClient.ListIngredients() = %v, %v, want %v", got, err, want)
+	}
+}
+
+func TestGrpcClient_GetSimilarRecipes(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+	defer conn.Close()
+	client := proto.NewRecipeServiceClient(conn)
+
+	tests := []struct {
+		name    string
+		c       *GrpcClient
+		recipe  string
+		want    []http.SimilarRecipe
+		wantErr bool
+	}{
+		{
+			name:    "1",
+			c:       &GrpcClient{client: client, apiKey: "1234"},
+			recipe:  "SpagBol",
+			want:    []http.SimilarRecipe{{Name: "Meatballs", Similarity: 2.0 / 3, SharedIngredients: []string{"Ground Beef", "Tomato"}}},
+			wantErr: false,
+		},
+		{
+			name:    "2",
+			c:       &GrpcClient{client: client, apiKey: "1234"},
+			recipe:  "Pizza",
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.c.GetSimilarRecipes(tt.recipe, 5)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GrpcClient.GetSimilarRecipes() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GrpcClient.GetSimilarRecipes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGrpcClient_GetStats(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+	defer conn.Close()
+
+	c := &GrpcClient{client: proto.NewRecipeServiceClient(conn), apiKey: "1234"}
+	got, err := c.GetStats()
+	want := http.Stats{
+		Recipes:            2,
+		Ingredients:        2,
+		MostUsed:           []http.IngredientUsage{{Name: "Tomato", Recipes: 2}},
+		LeastUsed:          []http.IngredientUsage{{Name: "Basil", Recipes: 1}},
+		AverageIngredients: 1.5,
+	}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("GrpcClient.GetStats() = %v, %v, want %v", got, err, want)
+	}
+}
+
+func TestGrpcClient_CompleteIngredient(t *testing.T) {
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+	defer conn.Close()
+	client := proto.NewRecipeServiceClient(conn)
+
+	type args struct {
+		prefix string
+		limit  int
+	}
+	tests := []struct {
+		name    string
+		c       *GrpcClient
+		args    args
+		want    []http.IngredientUsage
+		wantErr bool
+	}{
+		{
+			name:    "1",
+			c:       &GrpcClient{client: client, apiKey: "1234"},
+			args:    args{prefix: "to", limit: 10},
+			want:    []http.IngredientUsage{{Name: "Tomato", Recipes: 5}},
+			wantErr: false,
+		},
+		{
+			name:    "2",
+			c:       &GrpcClient{client: client, apiKey: "1234"},
+			args:    args{prefix: "x", limit: 10},
+			want:    nil,
+			wantErr: false,
+		},
+		{
+			name:    "3",
+			c:       &GrpcClient{client: client, apiKey: "1234"},
+			args:    args{prefix: "to", limit: -1},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.c.CompleteIngredient(tt.args.prefix, tt.args.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GrpcClient.CompleteIngredient() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GrpcClient.CompleteIngredient() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGrpcClient_Benchmarks(t *testing.T) {
 	ctx := context.Background()
 	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), grpc.WithInsecure())
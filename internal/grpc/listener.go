@@ -2,10 +2,13 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"go-incubator/internal/persistence"
+	"go-incubator/internal/units"
 	"go-incubator/proto"
 	"net"
+	"strings"
 	"sync"
 	"time"
 
@@ -18,18 +21,21 @@ import (
 )
 
 type GrpcServer struct {
-	server *grpc.Server
-	port   int
-	apiKey string
-	db     persistence.Persistence
+	server   *grpc.Server
+	port     int
+	apiKey   string
+	adminKey string
+	db       persistence.Persistence
 }
 
-// NewGrpcServer creates and returns a new GrpcServer with a listener on the specified port
-func NewGrpcServer(port int, apiKey string, persistence persistence.Persistence) (GrpcServer, error) {
+// NewGrpcServer creates and returns a new GrpcServer with a listener on the specified port,
+// and an admin key which SetIngredientKind requires, if it is not empty
+func NewGrpcServer(port int, apiKey string, adminKey string, persistence persistence.Persistence) (GrpcServer, error) {
 	s := GrpcServer{
-		port:   port,
-		apiKey: apiKey,
-		db:     persistence,
+		port:     port,
+		apiKey:   apiKey,
+		adminKey: adminKey,
+		db:       persistence,
 	}
 
 	return s, nil
@@ -48,7 +54,7 @@ func (s *GrpcServer) Start(wg *sync.WaitGroup) {
 		}
 
 		s.server = grpc.NewServer(grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(s.tracer, s.auth)))
-		proto.RegisterRecipeServiceServer(s.server, &serviceServer{db: s.db})
+		proto.RegisterRecipeServiceServer(s.server, &serviceServer{db: s.db, adminKey: s.adminKey})
 
 		fmt.Printf("starting gRPC listener on port %d\n", s.port)
 		defer fmt.Printf("gRPC listener on port %d stopped\n", s.port)
@@ -92,7 +98,161 @@ func (s *GrpcServer) tracer(ctx context.Context, req interface{}, info *grpc.Una
 
 // server is used to implement RecipeServiceServer
 type serviceServer struct {
-	db persistence.Persistence
+	db       persistence.Persistence
+	adminKey string
+}
+
+// dbError converts an error returned by the persistence layer into a gRPC status error,
+// reporting cancelled calls and expired deadlines with their own status codes
+func dbError(err error, msg string) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+
+	return status.Errorf(codes.Internal, "%s: %v", msg, err)
+}
+
+// notFound returns the NotFound error for the named recipe, with the names of similar recipes in its details
+func notFound(name string, suggestions []string) error {
+	st := status.Newf(codes.NotFound, "recipe (%s) not found", name)
+	if detailed, err := st.WithDetails(&proto.Suggestion{Name: name, Suggestions: suggestions}); err == nil {
+		st = detailed
+	}
+
+	return st.Err()
+}
+
+// queryToDB converts a *proto.FindRequest to a persistence.Query
+func queryToDB(r *proto.FindRequest) (persistence.Query, error) {
+	query := persistence.Query{Ingredients: r.Ingredients, Exclude: r.Exclude, MaxMissing: int(r.MaxMissing), Expand: r.Expand}
+	switch r.Mode {
+	case proto.MatchMode_MATCH_ALL:
+		query.Mode = persistence.MatchAll
+	case proto.MatchMode_MATCH_ANY:
+		query.Mode = persistence.MatchAny
+	case proto.MatchMode_MATCH_SUBSET:
+		query.Mode = persistence.MatchSubset
+	default:
+		return query, fmt.Errorf("unknown match mode (%d)", r.Mode)
+	}
+
+	if r.MaxMissing < 0 {
+		return query, fmt.Errorf("invalid max missing (%d)", r.MaxMissing)
+	}
+
+	if r.Query != "" {
+		expr, err := persistence.ParseExpr(r.Query)
+		if err != nil {
+			return query, err
+		}
+		query.Expr = expr
+	}
+
+	return query, nil
+}
+
+// nameQueryToDB converts a *proto.NameSearchRequest to a persistence.NameQuery
+func nameQueryToDB(r *proto.NameSearchRequest) (persistence.NameQuery, error) {
+	query := persistence.NameQuery{Text: r.Name}
+	switch r.Match {
+	case proto.NameMatch_NAME_PREFIX:
+		query.Match = persistence.NamePrefix
+	case proto.NameMatch_NAME_SUBSTRING:
+		query.Match = persistence.NameSubstring
+	default:
+		return query, fmt.Errorf("unknown name match (%d)", r.Match)
+	}
+
+	return query, nil
+}
+
+// matchFromDB converts a persistence.Ranked to a *proto.Match
+func matchFromDB(r persistence.Ranked) *proto.Match {
+	return &proto.Match{
+		Recipe:             r.Recipe.Name,
+		MissingIngredients: r.Score.Missing,
+		MatchedIngredients: r.Score.Matched,
+		Matched:            int32(len(r.Score.Matched)),
+		Missing:            int32(len(r.Score.Missing)),
+		Extra:              int32(r.Score.Extra),
+		Score:              r.Score.Value(),
+	}
+}
+
+// ingredientsFromDB converts a []persistence.IngredientUsage to *proto.Ingredients
+func ingredientsFromDB(r []persistence.IngredientUsage) *proto.Ingredients {
+	ingredients := &proto.Ingredients{Ingredients: []*proto.IngredientUsage{}}
+	for _, v := range r {
+		ingredients.Ingredients = append(ingredients.Ingredients, &proto.IngredientUsage{Name: v.Name, Recipes: int32(v.Recipes)})
+	}
+
+	return ingredients
+}
+
+// similarFromDB converts a []persistence.SimilarRecipe to *proto.SimilarRecipes
+func similarFromDB(r []persistence.SimilarRecipe) *proto.SimilarRecipes {
+	similar := &proto.SimilarRecipes{Recipes: []*proto.SimilarRecipe{}}
+	for _, v := range r {
+		similar.Recipes = append(similar.Recipes, &proto.SimilarRecipe{Name: v.Name, Similarity: v.Similarity, SharedIngredients: v.Shared})
+	}
+
+	return similar
+}
+
+// recipeToDB converts a *proto.Recipe to a persistence.Recipe. Structured ingredients win
+// when present, so that clients which only send ingredient names keep working.
+func recipeToDB(r *proto.Recipe) persistence.Recipe {
+	recipe := persistence.Recipe{
+		Name:         r.Name,
+		Description:  r.Description,
+		Instructions: r.Instructions,
+		Servings:     int(r.Servings),
+		PrepMinutes:  int(r.PrepMinutes),
+		CookMinutes:  int(r.CookMinutes),
+		Source:       r.Source,
+	}
+	if len(r.StructuredIngredients) == 0 {
+		recipe.Ingredients = persistence.NamedIngredients(r.Ingredients)
+		return recipe
+	}
+
+	for _, ingredient := range r.StructuredIngredients {
+		recipe.Ingredients = append(recipe.Ingredients, persistence.Ingredient{
+			Name:      ingredient.GetName(),
+			Quantity:  ingredient.GetQuantity(),
+			Unit:      ingredient.GetUnit(),
+			Note:      ingredient.GetNote(),
+			Component: ingredient.GetComponent(),
+		})
+	}
+
+	return recipe
+}
+
+// recipeFromDB converts a persistence.Recipe to a *proto.Recipe, filling in the ingredient
+// names as well for clients which do not know about structured ingredients
+func recipeFromDB(r persistence.Recipe) *proto.Recipe {
+	recipe := &proto.Recipe{
+		Name:         r.Name,
+		Ingredients:  r.IngredientNames(),
+		Description:  r.Description,
+		Instructions: r.Instructions,
+		Servings:     int32(r.Servings),
+		PrepMinutes:  int32(r.PrepMinutes),
+		CookMinutes:  int32(r.CookMinutes),
+		Source:       r.Source,
+	}
+	for _, ingredient := range r.Ingredients {
+		recipe.StructuredIngredients = append(recipe.StructuredIngredients, &proto.Ingredient{
+			Name:      ingredient.Name,
+			Quantity:  ingredient.Quantity,
+			Unit:      ingredient.Unit,
+			Note:      ingredient.Note,
+			Component: ingredient.Component,
+		})
+	}
+
+	return recipe
 }
 
 func (s *serviceServer) AddRecipe(ctx context.Context, r *proto.Recipe) (*emptypb.Empty, error) {
@@ -100,58 +260,315 @@ func (s *serviceServer) AddRecipe(ctx context.Context, r *proto.Recipe) (*emptyp
 		return nil, status.Errorf(codes.InvalidArgument, "no name specified")
 	}
 
-	if len(r.Ingredients) == 0 {
+	if len(r.Ingredients) == 0 && len(r.StructuredIngredients) == 0 {
 		return nil, status.Errorf(codes.InvalidArgument, "no ingredients specified")
 	}
 
-	// Convert *proto.Recipe to persistence.Recipe
-	recipe := persistence.Recipe{}
-	recipe.Name = r.Name
-	recipe.Ingredients = r.Ingredients
+	if r.Servings < 0 || r.PrepMinutes < 0 || r.CookMinutes < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "servings and times must not be negative")
+	}
+
+	recipe := recipeToDB(r)
+	for i, ingredient := range recipe.Ingredients {
+		if ingredient.Name == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "no name specified for ingredient %d", i+1)
+		}
+	}
 
-	err := s.db.AddRecipe(recipe)
+	err := s.db.AddRecipe(ctx, recipe)
+	if errors.Is(err, persistence.ErrRecipeCycle) {
+		return nil, status.Errorf(codes.InvalidArgument, "recipe (%s) would be a component of itself", r.Name)
+	}
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "writing recipe to db: %v", err)
+		return nil, dbError(err, "writing recipe to db")
 	}
 
 	return &emptypb.Empty{}, nil
 }
 
 func (s *serviceServer) GetRecipe(ctx context.Context, r *proto.RecipeRequest) (*proto.Recipe, error) {
-	recipe, err := s.db.GetRecipe(r.Name)
+	if r.Servings < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid servings (%d)", r.Servings)
+	}
+	system, err := units.ParseSystem(r.Units)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	recipe, err := s.db.GetRecipe(ctx, r.Name)
 	if err == persistence.ErrNoResults {
-		return nil, status.Errorf(codes.NotFound, "recipe (%s) not found", r.Name)
+		suggestions, serr := persistence.SuggestRecipes(ctx, s.db, r.Name)
+		if serr != nil {
+			return nil, dbError(serr, "finding similar recipes in db")
+		}
+		if r.Fuzzy && len(suggestions) > 0 {
+			recipe, err = s.db.GetRecipe(ctx, suggestions[0])
+		}
+		if err == persistence.ErrNoResults {
+			return nil, notFound(r.Name, suggestions)
+		}
 	}
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "getting recipe from db: %v", err)
+		return nil, dbError(err, "getting recipe from db")
 	}
 
-	// Convert persistence.Recipe to *proto.Recipe
-	rsp := &proto.Recipe{}
-	rsp.Name = recipe.Name
-	rsp.Ingredients = recipe.Ingredients
+	if r.Expand {
+		recipe, err = persistence.ExpandRecipe(ctx, s.db, recipe)
+		if err != nil {
+			return nil, dbError(err, "expanding recipe from db")
+		}
+	}
 
-	return rsp, nil
+	if r.Servings != 0 || system != "" {
+		scaled, err := persistence.ScaleRecipe(recipe, int(r.Servings), system)
+		if errors.Is(err, persistence.ErrUnknownServings) {
+			return nil, status.Errorf(codes.FailedPrecondition, "recipe (%s) does not say how many servings it makes", recipe.Name)
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "scaling recipe: %v", err)
+		}
+		recipe = scaled
+	}
+
+	return recipeFromDB(recipe), nil
+}
+
+func (s *serviceServer) DeleteRecipe(ctx context.Context, r *proto.RecipeRequest) (*emptypb.Empty, error) {
+	if r.Name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "no name specified")
+	}
+
+	err := s.db.DeleteRecipe(ctx, r.Name)
+	if err == persistence.ErrNoResults {
+		return nil, status.Errorf(codes.NotFound, "recipe (%s) not found", r.Name)
+	}
+	if err != nil {
+		return nil, dbError(err, "deleting recipe from db")
+	}
+
+	return &emptypb.Empty{}, nil
 }
 
 func (s *serviceServer) FindRecipes(ctx context.Context, r *proto.FindRequest) (*proto.Recipes, error) {
-	if len(r.Ingredients) == 0 {
+	if len(r.Ingredients) == 0 && r.Query == "" {
 		return nil, status.Errorf(codes.InvalidArgument, "no ingredients specified")
 	}
 
-	dbrecipes, err := s.db.FindRecipes(r.Ingredients)
+	query, err := queryToDB(r)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	// A fuzzy search looks for the closest ingredients to those which no recipe uses instead
+	var suggestions []persistence.Suggestion
+	if r.Fuzzy {
+		suggestions, err = persistence.SuggestIngredients(ctx, s.db, query.Names())
+		if err != nil {
+			return nil, dbError(err, "finding similar ingredients in db")
+		}
+		query = query.Corrected(suggestions)
+	}
+
+	dbrecipes, err := s.db.SearchRecipes(ctx, query)
+	if err != nil {
+		return nil, dbError(err, "reading recipes from db")
+	}
+
+	// Explain a search which found nothing, in case an ingredient was misspelt
+	if len(dbrecipes) == 0 && !r.Fuzzy {
+		suggestions, err = persistence.SuggestIngredients(ctx, s.db, query.Names())
+		if err != nil {
+			return nil, dbError(err, "finding similar ingredients in db")
+		}
+	}
+
+	// Convert []persistence.Recipe to *proto.Recipes, the best matches first
+	rsp := &proto.Recipes{Recipes: []*proto.Recipe{}, Matches: []*proto.Match{}, Suggestions: []*proto.Suggestion{}}
+	for _, r := range query.Rank(dbrecipes) {
+		rsp.Recipes = append(rsp.Recipes, recipeFromDB(r.Recipe))
+		rsp.Matches = append(rsp.Matches, matchFromDB(r))
+	}
+	for _, suggestion := range suggestions {
+		rsp.Suggestions = append(rsp.Suggestions, &proto.Suggestion{Name: suggestion.Name, Suggestions: suggestion.Suggestions})
+	}
+
+	return rsp, nil
+}
+
+func (s *serviceServer) ListRecipes(ctx context.Context, r *proto.ListRequest) (*proto.RecipePage, error) {
+	size, err := persistence.PageSize(int(r.PageSize))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	cursor, err := persistence.DecodePageToken(r.PageToken)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	// Ask for one more recipe than fits on the page, to find out whether there is a next page
+	dbrecipes, err := s.db.ListRecipes(ctx, cursor, size+1)
+	if err != nil {
+		return nil, dbError(err, "reading recipes from db")
+	}
+
+	rsp := &proto.RecipePage{Recipes: []*proto.Recipe{}}
+	if len(dbrecipes) > size {
+		dbrecipes = dbrecipes[:size]
+		rsp.NextPageToken = persistence.EncodePageToken(dbrecipes[size-1].Name)
+	}
+
+	// Convert []persistence.Recipe to *proto.RecipePage
+	for _, r := range dbrecipes {
+		rsp.Recipes = append(rsp.Recipes, recipeFromDB(r))
+	}
+
+	return rsp, nil
+}
+
+func (s *serviceServer) SearchRecipesByName(ctx context.Context, r *proto.NameSearchRequest) (*proto.RecipePage, error) {
+	if strings.TrimSpace(r.Name) == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "no name specified")
+	}
+
+	query, err := nameQueryToDB(r)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	size, err := persistence.PageSize(int(r.PageSize))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	cursor, err := persistence.DecodePageToken(r.PageToken)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "reading recipes from db: %v", err)
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
-	// Convert []persistence.Recipe to *proto.Recipes
-	rsp := &proto.Recipes{Recipes: []*proto.Recipe{}}
+	// Ask for one more recipe than fits on the page, to find out whether there is a next page
+	dbrecipes, err := s.db.SearchRecipesByName(ctx, query, cursor, size+1)
+	if err != nil {
+		return nil, dbError(err, "searching recipes in db")
+	}
+
+	rsp := &proto.RecipePage{Recipes: []*proto.Recipe{}}
+	if len(dbrecipes) > size {
+		dbrecipes = dbrecipes[:size]
+		rsp.NextPageToken = persistence.EncodePageToken(dbrecipes[size-1].Name)
+	}
+
+	// Convert []persistence.Recipe to *proto.RecipePage
 	for _, r := range dbrecipes {
-		recipe := &proto.Recipe{}
-		recipe.Name = r.Name
-		recipe.Ingredients = r.Ingredients
-		rsp.Recipes = append(rsp.Recipes, recipe)
+		rsp.Recipes = append(rsp.Recipes, recipeFromDB(r))
+	}
+
+	return rsp, nil
+}
+
+func (s *serviceServer) ListIngredients(ctx context.Context, r *emptypb.Empty) (*proto.Ingredients, error) {
+	dbingredients, err := s.db.ListIngredients(ctx)
+	if err != nil {
+		return nil, dbError(err, "reading ingredients from db")
+	}
+
+	return ingredientsFromDB(dbingredients), nil
+}
+
+func (s *serviceServer) CompleteIngredient(ctx context.Context, r *proto.CompleteRequest) (*proto.Ingredients, error) {
+	limit, err := persistence.CompletionLimit(int(r.Limit))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	dbingredients, err := s.db.CompleteIngredient(ctx, r.Prefix, limit)
+	if err != nil {
+		return nil, dbError(err, "reading ingredients from db")
+	}
+
+	return ingredientsFromDB(dbingredients), nil
+}
+
+func (s *serviceServer) GetSimilarRecipes(ctx context.Context, r *proto.SimilarRequest) (*proto.SimilarRecipes, error) {
+	if r.Name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "no name specified")
+	}
+	limit, err := persistence.SimilarLimit(int(r.Limit))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	similar, err := s.db.SimilarRecipes(ctx, r.Name, limit)
+	if err == persistence.ErrNoResults {
+		suggestions, serr := persistence.SuggestRecipes(ctx, s.db, r.Name)
+		if serr != nil {
+			return nil, dbError(serr, "finding similar recipes in db")
+		}
+		return nil, notFound(r.Name, suggestions)
+	}
+	if err != nil {
+		return nil, dbError(err, "finding similar recipes in db")
+	}
+
+	return similarFromDB(similar), nil
+}
+
+func (s *serviceServer) GetStats(ctx context.Context, r *emptypb.Empty) (*proto.Stats, error) {
+	stats, err := s.db.CatalogueStats(ctx, persistence.StatsTop)
+	if err != nil {
+		return nil, dbError(err, "reading stats from db")
+	}
+
+	return &proto.Stats{
+		Recipes:            int32(stats.Recipes),
+		Ingredients:        int32(stats.Ingredients),
+		MostUsed:           ingredientsFromDB(stats.MostUsed).Ingredients,
+		LeastUsed:          ingredientsFromDB(stats.LeastUsed).Ingredients,
+		AverageIngredients: stats.AverageIngredients,
+		IsolatedRecipes:    stats.IsolatedRecipes,
+	}, nil
+}
+
+func (s *serviceServer) GetTaxonomy(ctx context.Context, r *emptypb.Empty) (*proto.Taxonomy, error) {
+	rsp := &proto.Taxonomy{Kinds: []*proto.Kind{}}
+	for _, kind := range persistence.Taxonomy() {
+		rsp.Kinds = append(rsp.Kinds, &proto.Kind{Ingredient: kind.Ingredient, Parent: kind.Parent})
 	}
 
 	return rsp, nil
 }
+
+func (s *serviceServer) SetIngredientKind(ctx context.Context, r *proto.Kind) (*emptypb.Empty, error) {
+	if err := s.admin(ctx); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(r.Ingredient) == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "no ingredient specified")
+	}
+
+	if err := persistence.SetKind(r.Ingredient, r.Parent); err != nil {
+		if errors.Is(err, persistence.ErrTaxonomyCycle) {
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "saving taxonomy: %v", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// admin checks that a request which changes the taxonomy contains the admin key, and refuses it
+// if there is none, as every client has the API key
+func (s *serviceServer) admin(ctx context.Context) error {
+	if s.adminKey == "" {
+		return status.Error(codes.PermissionDenied, "taxonomy changes are disabled")
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	adminHeader, ok := md["x-admin-key"]
+	if !ok || adminHeader[0] != s.adminKey {
+		return status.Error(codes.PermissionDenied, "admin authentication failed")
+	}
+
+	return nil
+}
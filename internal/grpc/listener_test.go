@@ -18,7 +18,9 @@ import (
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	pb "google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
@@ -30,25 +32,31 @@ type mockdb struct {
 func NewMockDB() *mockdb {
 	mdb := &mockdb{}
 	mdb.recipes = make(map[string]persistence.Recipe)
-	mdb.recipes["Cheese Fondue"] = persistence.Recipe{Name: "Cheese Fondue", Ingredients: []string{"Gruyere", "Emmental"}}
-	mdb.recipes["Mac & Cheese"] = persistence.Recipe{Name: "Mac & Cheese", Ingredients: []string{"Mozzarella", "Macaroni"}}
-	mdb.recipes["SpagBol"] = persistence.Recipe{Name: "SpagBol", Ingredients: []string{"Spaghetti", "Ground Beef", "Tomato"}}
-	mdb.recipes["BLT"] = persistence.Recipe{Name: "BLT", Ingredients: []string{"Tomato", "Bacon", "Lettuce"}}
-	mdb.recipes["Greek Salad"] = persistence.Recipe{Name: "Greek Salad", Ingredients: []string{"Feta", "Tomato", "Cucumber"}}
-	mdb.recipes["Caprese Salad"] = persistence.Recipe{Name: "Caprese Salad", Ingredients: []string{"Mozzarella", "Tomato"}}
-	mdb.recipes["Meatballs"] = persistence.Recipe{Name: "Meatballs", Ingredients: []string{"Ground Beef", "Tomato"}}
+	mdb.recipes["Cheese Fondue"] = persistence.Recipe{Name: "Cheese Fondue", Ingredients: persistence.NamedIngredients([]string{"Gruyere", "Emmental"})}
+	mdb.recipes["Mac & Cheese"] = persistence.Recipe{Name: "Mac & Cheese", Ingredients: persistence.NamedIngredients([]string{"Mozzarella", "Macaroni"})}
+	mdb.recipes["SpagBol"] = persistence.Recipe{Name: "SpagBol", Ingredients: persistence.NamedIngredients([]string{"Spaghetti", "Ground Beef", "Tomato"})}
+	mdb.recipes["BLT"] = persistence.Recipe{Name: "BLT", Ingredients: persistence.NamedIngredients([]string{"Tomato", "Bacon", "Lettuce"})}
+	mdb.recipes["Greek Salad"] = persistence.Recipe{Name: "Greek Salad", Ingredients: persistence.NamedIngredients([]string{"Feta", "Tomato", "Cucumber"})}
+	mdb.recipes["Caprese Salad"] = persistence.Recipe{Name: "Caprese Salad", Ingredients: persistence.NamedIngredients([]string{"Mozzarella", "Tomato"})}
+	mdb.recipes["Meatballs"] = persistence.Recipe{Name: "Meatballs", Ingredients: persistence.NamedIngredients([]string{"Ground Beef", "Tomato"})}
 
 	return mdb
 }
 
-func (db *mockdb) AddRecipe(recipe persistence.Recipe) error {
+func (db *mockdb) AddRecipe(ctx context.Context, recipe persistence.Recipe) error {
 	if recipe.Name == "Expected Error" {
 		return fmt.Errorf("database error")
 	}
-	return nil
+	return persistence.CheckComponents(recipe, func(name string) ([]string, error) {
+		component := db.recipes[name]
+		return component.Components(), nil
+	})
 }
 
-func (db *mockdb) GetRecipe(name string) (persistence.Recipe, error) {
+func (db *mockdb) GetRecipe(ctx context.Context, name string) (persistence.Recipe, error) {
+	if err := ctx.Err(); err != nil {
+		return persistence.Recipe{}, err
+	}
 	if name == "Expected Error" {
 		return persistence.Recipe{}, fmt.Errorf("database error")
 	}
@@ -60,8 +68,26 @@ func (db *mockdb) GetRecipe(name string) (persistence.Recipe, error) {
 	return r, nil
 }
 
-func (db *mockdb) FindRecipes(ingredients []string) ([]persistence.Recipe, error) {
-	if strings.Join(ingredients, " ") == "Expected Error" {
+func (db *mockdb) DeleteRecipe(ctx context.Context, name string) error {
+	if name == "Expected Error" {
+		return fmt.Errorf("database error")
+	}
+	if _, ok := db.recipes[name]; !ok {
+		return persistence.ErrNoResults
+	}
+
+	return nil
+}
+
+func (db *mockdb) FindRecipes(ctx context.Context, ingredients []string) ([]persistence.Recipe, error) {
+	return db.SearchRecipes(ctx, persistence.Query{Ingredients: ingredients})
+}
+
+func (db *mockdb) SearchRecipes(ctx context.Context, query persistence.Query) ([]persistence.Recipe, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if strings.Join(query.Ingredients, " ") == "Expected Error" {
 		return nil, fmt.Errorf("database error")
 	}
 	keys := make([]string, 0, len(db.recipes))
@@ -73,7 +99,7 @@ func (db *mockdb) FindRecipes(ingredients []string) ([]persistence.Recipe, error
 	recipes := make([]persistence.Recipe, 0)
 	for _, k := range keys {
 		recipe := db.recipes[k]
-		if UsesIngredients(recipe, ingredients) {
+		if query.Matches(&recipe) {
 			recipes = append(recipes, recipe)
 		}
 	}
@@ -81,24 +107,196 @@ func (db *mockdb) FindRecipes(ingredients []string) ([]persistence.Recipe, error
 	return recipes, nil
 }
 
-func UsesIngredient(r persistence.Recipe, ingredient string) bool {
-	for _, v := range r.Ingredients {
-		if v == ingredient {
-			return true
+func (db *mockdb) ListRecipes(ctx context.Context, cursor string, limit int) ([]persistence.Recipe, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if cursor == "Expected Error" {
+		return nil, fmt.Errorf("database error")
+	}
+	keys := make([]string, 0, len(db.recipes))
+	for k := range db.recipes {
+		if k > cursor {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	if len(keys) > limit {
+		keys = keys[:limit]
+	}
+
+	recipes := make([]persistence.Recipe, 0, len(keys))
+	for _, k := range keys {
+		recipes = append(recipes, db.recipes[k])
+	}
+
+	return recipes, nil
+}
+
+func (db *mockdb) SearchRecipesByName(ctx context.Context, query persistence.NameQuery, cursor string, limit int) ([]persistence.Recipe, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if query.Text == "Expected Error" {
+		return nil, fmt.Errorf("database error")
+	}
+	keys := make([]string, 0, len(db.recipes))
+	for k := range db.recipes {
+		if k > cursor && query.Matches(k) {
+			keys = append(keys, k)
 		}
 	}
+	sort.Strings(keys)
+	if len(keys) > limit {
+		keys = keys[:limit]
+	}
+
+	recipes := make([]persistence.Recipe, 0, len(keys))
+	for _, k := range keys {
+		recipes = append(recipes, db.recipes[k])
+	}
 
-	return false
+	return recipes, nil
+}
+
+func (db *mockdb) RecipeNames(ctx context.Context) ([]string, error) {
+	names := make([]string, 0, len(db.recipes))
+	for k := range db.recipes {
+		names = append(names, k)
+	}
+
+	return names, nil
 }
 
-func UsesIngredients(r persistence.Recipe, ingredients []string) bool {
-	for _, v := range ingredients {
-		if !UsesIngredient(r, v) {
-			return false
+func (db *mockdb) IngredientNames(ctx context.Context) ([]string, error) {
+	seen := make(map[string]bool)
+	names := []string{}
+	for _, recipe := range db.recipes {
+		for _, ingredient := range recipe.Ingredients {
+			if !seen[ingredient.Name] {
+				seen[ingredient.Name] = true
+				names = append(names, ingredient.Name)
+			}
 		}
 	}
 
-	return true
+	return names, nil
+}
+
+func (db *mockdb) ListIngredients(ctx context.Context) ([]persistence.IngredientUsage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	ingredients := db.usage("")
+	sort.Slice(ingredients, func(i, j int) bool { return ingredients[i].Name < ingredients[j].Name })
+
+	return ingredients, nil
+}
+
+func (db *mockdb) CompleteIngredient(ctx context.Context, prefix string, limit int) ([]persistence.IngredientUsage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if prefix == "Expected Error" {
+		return nil, fmt.Errorf("database error")
+	}
+	ingredients := db.usage(prefix)
+	persistence.SortByUsage(ingredients)
+	if len(ingredients) > limit {
+		ingredients = ingredients[:limit]
+	}
+
+	return ingredients, nil
+}
+
+func (db *mockdb) CatalogueStats(ctx context.Context, top int) (persistence.CatalogueStats, error) {
+	if err := ctx.Err(); err != nil {
+		return persistence.CatalogueStats{}, err
+	}
+	counts := make(map[string]int)
+	lines := 0
+	for _, usage := range db.usage("") {
+		counts[usage.Name] = usage.Recipes
+	}
+
+	stats := persistence.CatalogueStats{Recipes: len(db.recipes), Ingredients: len(counts), IsolatedRecipes: []string{}}
+	for _, recipe := range db.recipes {
+		isolated := true
+		for _, ingredient := range recipe.Ingredients {
+			lines++
+			if counts[ingredient.Name] > 1 {
+				isolated = false
+			}
+		}
+		if isolated {
+			stats.IsolatedRecipes = append(stats.IsolatedRecipes, recipe.Name)
+		}
+	}
+	sort.Strings(stats.IsolatedRecipes)
+	stats.AverageIngredients = persistence.Average(lines, len(db.recipes))
+
+	ingredients := db.usage("")
+	persistence.SortByUsage(ingredients)
+	stats.MostUsed = append(stats.MostUsed, ingredients[:top]...)
+	sort.SliceStable(ingredients, func(i, j int) bool { return ingredients[i].Recipes < ingredients[j].Recipes })
+	stats.LeastUsed = append(stats.LeastUsed, ingredients[:top]...)
+
+	return stats, nil
+}
+
+func (db *mockdb) SimilarRecipes(ctx context.Context, name string, limit int) ([]persistence.SimilarRecipe, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if name == "Expected Error" {
+		return nil, fmt.Errorf("database error")
+	}
+	recipe, ok := db.recipes[name]
+	if !ok {
+		return nil, persistence.ErrNoResults
+	}
+
+	similar := []persistence.SimilarRecipe{}
+	for _, other := range db.recipes {
+		if other.Name == name {
+			continue
+		}
+		shared := []string{}
+		for _, ingredient := range recipe.Ingredients {
+			if other.UsesIngredient(ingredient.Name) {
+				shared = append(shared, ingredient.Name)
+			}
+		}
+		if len(shared) > 0 {
+			similar = append(similar, persistence.SimilarRecipe{Name: other.Name, Similarity: persistence.Jaccard(len(shared), len(recipe.Ingredients), len(other.Ingredients)), Shared: shared})
+		}
+	}
+	persistence.SortBySimilarity(similar)
+	if len(similar) > limit {
+		similar = similar[:limit]
+	}
+
+	return similar, nil
+}
+
+// usage counts the recipes which use each ingredient that completes the prefix
+func (db *mockdb) usage(prefix string) []persistence.IngredientUsage {
+	prefixes := persistence.CompletionPrefixes(prefix)
+	counts := make(map[string]int)
+	for _, recipe := range db.recipes {
+		for _, ingredient := range recipe.Ingredients {
+			if persistence.CompletesTo(persistence.NormaliseIngredient(ingredient.Name), prefixes) {
+				counts[ingredient.Name]++
+			}
+		}
+	}
+
+	ingredients := []persistence.IngredientUsage{}
+	for name, count := range counts {
+		ingredients = append(ingredients, persistence.IngredientUsage{Name: name, Recipes: count})
+	}
+
+	return ingredients
 }
 
 func captureOutput(f func()) string {
@@ -135,6 +333,7 @@ func TestNewGrpcServer(t *testing.T) {
 	type args struct {
 		port        int
 		apiKey      string
+		adminKey    string
 		persistence persistence.Persistence
 	}
 	tests := []struct {
@@ -148,14 +347,15 @@ func TestNewGrpcServer(t *testing.T) {
 			args: args{
 				port:        1234,
 				apiKey:      "1234",
+				adminKey:    "5678",
 				persistence: NewMockDB(),
 			},
-			want: GrpcServer{port: 1234, apiKey: "1234", db: NewMockDB()},
+			want: GrpcServer{port: 1234, apiKey: "1234", adminKey: "5678", db: NewMockDB()},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := NewGrpcServer(tt.args.port, tt.args.apiKey, tt.args.persistence)
+			got, err := NewGrpcServer(tt.args.port, tt.args.apiKey, tt.args.adminKey, tt.args.persistence)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("NewGrpcServer() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -337,6 +537,46 @@ func Test_serviceServer_AddRecipe(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name: "6",
+			s:    &serviceServer{db: NewMockDB()},
+			args: args{
+				ctx: context.Background(),
+				r:   &proto.Recipe{Name: "Pancakes", StructuredIngredients: []*proto.Ingredient{{Name: "Flour", Quantity: 2, Unit: "cups", Note: "sifted"}, {Name: "Egg", Quantity: 1}}},
+			},
+			want:    &emptypb.Empty{},
+			wantErr: false,
+		},
+		{
+			name: "7",
+			s:    &serviceServer{db: NewMockDB()},
+			args: args{
+				ctx: context.Background(),
+				r:   &proto.Recipe{Name: "Pancakes", StructuredIngredients: []*proto.Ingredient{{Name: "Flour"}, {Quantity: 1, Unit: "cup"}}},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "8",
+			s:    &serviceServer{db: NewMockDB()},
+			args: args{
+				ctx: context.Background(),
+				r:   &proto.Recipe{Name: "Pancakes", Ingredients: []string{"Flour"}, CookMinutes: -5},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "9",
+			s:    &serviceServer{db: NewMockDB()},
+			args: args{
+				ctx: context.Background(),
+				r:   &proto.Recipe{Name: "Pizza", StructuredIngredients: []*proto.Ingredient{{Name: "Mozzarella"}, {Name: "Pizza", Component: true}}},
+			},
+			want:    nil,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -353,29 +593,33 @@ func Test_serviceServer_AddRecipe(t *testing.T) {
 }
 
 func Test_serviceServer_GetRecipe(t *testing.T) {
+	toast := NewMockDB()
+	toast.recipes["Tomato Toast"] = persistence.Recipe{Name: "Tomato Toast", Ingredients: []persistence.Ingredient{{Name: "Bread", Quantity: 2, Unit: "slices"}, {Name: "Caprese Salad", Component: true}}, Servings: 2}
+
 	type args struct {
 		ctx context.Context
 		r   *proto.RecipeRequest
 	}
 	tests := []struct {
-		name    string
-		s       *serviceServer
-		args    args
-		want    *proto.Recipe
-		wantErr bool
+		name            string
+		s               *serviceServer
+		args            args
+		want            *proto.Recipe
+		wantErr         bool
+		wantSuggestions []string
 	}{
 		{
 			name:    "1",
 			s:       &serviceServer{db: NewMockDB()},
 			args:    args{ctx: context.Background(), r: &proto.RecipeRequest{Name: "Cheese Fondue"}},
-			want:    &proto.Recipe{Name: "Cheese Fondue", Ingredients: []string{"Gruyere", "Emmental"}},
+			want:    &proto.Recipe{Name: "Cheese Fondue", Ingredients: []string{"Gruyere", "Emmental"}, StructuredIngredients: []*proto.Ingredient{{Name: "Gruyere"}, {Name: "Emmental"}}},
 			wantErr: false,
 		},
 		{
 			name:    "2",
 			s:       &serviceServer{db: NewMockDB()},
 			args:    args{ctx: context.Background(), r: &proto.RecipeRequest{Name: "SpagBol"}},
-			want:    &proto.Recipe{Name: "SpagBol", Ingredients: []string{"Spaghetti", "Ground Beef", "Tomato"}},
+			want:    &proto.Recipe{Name: "SpagBol", Ingredients: []string{"Spaghetti", "Ground Beef", "Tomato"}, StructuredIngredients: []*proto.Ingredient{{Name: "Spaghetti"}, {Name: "Ground Beef"}, {Name: "Tomato"}}},
 			wantErr: false,
 		},
 		{
@@ -392,6 +636,63 @@ func Test_serviceServer_GetRecipe(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name:            "5",
+			s:               &serviceServer{db: NewMockDB()},
+			args:            args{ctx: context.Background(), r: &proto.RecipeRequest{Name: "greek salat"}},
+			want:            nil,
+			wantErr:         true,
+			wantSuggestions: []string{"Greek Salad"},
+		},
+		{
+			name:    "6",
+			s:       &serviceServer{db: NewMockDB()},
+			args:    args{ctx: context.Background(), r: &proto.RecipeRequest{Name: "greek salat", Fuzzy: true}},
+			want:    &proto.Recipe{Name: "Greek Salad", Ingredients: []string{"Feta", "Tomato", "Cucumber"}, StructuredIngredients: []*proto.Ingredient{{Name: "Feta"}, {Name: "Tomato"}, {Name: "Cucumber"}}},
+			wantErr: false,
+		},
+		{
+			name:    "7",
+			s:       &serviceServer{db: toast},
+			args:    args{ctx: context.Background(), r: &proto.RecipeRequest{Name: "Tomato Toast"}},
+			want:    &proto.Recipe{Name: "Tomato Toast", Ingredients: []string{"Bread", "Caprese Salad"}, StructuredIngredients: []*proto.Ingredient{{Name: "Bread", Quantity: 2, Unit: "slices"}, {Name: "Caprese Salad", Component: true}}, Servings: 2},
+			wantErr: false,
+		},
+		{
+			name:    "8",
+			s:       &serviceServer{db: toast},
+			args:    args{ctx: context.Background(), r: &proto.RecipeRequest{Name: "Tomato Toast", Expand: true}},
+			want:    &proto.Recipe{Name: "Tomato Toast", Ingredients: []string{"Bread", "Mozzarella", "Tomato"}, StructuredIngredients: []*proto.Ingredient{{Name: "Bread", Quantity: 2, Unit: "slices"}, {Name: "Mozzarella"}, {Name: "Tomato"}}, Servings: 2},
+			wantErr: false,
+		},
+		{
+			name:    "9",
+			s:       &serviceServer{db: toast},
+			args:    args{ctx: context.Background(), r: &proto.RecipeRequest{Name: "Tomato Toast", Servings: 5}},
+			want:    &proto.Recipe{Name: "Tomato Toast", Ingredients: []string{"Bread", "Caprese Salad"}, StructuredIngredients: []*proto.Ingredient{{Name: "Bread", Quantity: 5, Unit: "slices"}, {Name: "Caprese Salad", Component: true}}, Servings: 5},
+			wantErr: false,
+		},
+		{
+			name:    "10",
+			s:       &serviceServer{db: NewMockDB()},
+			args:    args{ctx: context.Background(), r: &proto.RecipeRequest{Name: "BLT", Servings: 2}},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "11",
+			s:       &serviceServer{db: NewMockDB()},
+			args:    args{ctx: context.Background(), r: &proto.RecipeRequest{Name: "BLT", Servings: -1}},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "12",
+			s:       &serviceServer{db: NewMockDB()},
+			args:    args{ctx: context.Background(), r: &proto.RecipeRequest{Name: "BLT", Units: "nautical"}},
+			want:    nil,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -403,11 +704,82 @@ func Test_serviceServer_GetRecipe(t *testing.T) {
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("serviceServer.GetRecipe() = %v, want %v", got, tt.want)
 			}
+			if tt.wantSuggestions != nil {
+				var suggestions []string
+				for _, detail := range status.Convert(err).Details() {
+					if s, ok := detail.(*proto.Suggestion); ok {
+						suggestions = s.Suggestions
+					}
+				}
+				if !reflect.DeepEqual(suggestions, tt.wantSuggestions) {
+					t.Errorf("serviceServer.GetRecipe() suggestions = %v, want %v", suggestions, tt.wantSuggestions)
+				}
+			}
+		})
+	}
+}
+
+func Test_serviceServer_DeleteRecipe(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		r   *proto.RecipeRequest
+	}
+	tests := []struct {
+		name     string
+		s        *serviceServer
+		args     args
+		want     *emptypb.Empty
+		wantCode codes.Code
+	}{
+		{
+			name:     "1",
+			s:        &serviceServer{db: NewMockDB()},
+			args:     args{ctx: context.Background(), r: &proto.RecipeRequest{Name: "Cheese Fondue"}},
+			want:     &emptypb.Empty{},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "2",
+			s:        &serviceServer{db: NewMockDB()},
+			args:     args{ctx: context.Background(), r: &proto.RecipeRequest{Name: "Pizza"}},
+			want:     nil,
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "3",
+			s:        &serviceServer{db: NewMockDB()},
+			args:     args{ctx: context.Background(), r: &proto.RecipeRequest{}},
+			want:     nil,
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "4",
+			s:        &serviceServer{db: NewMockDB()},
+			args:     args{ctx: context.Background(), r: &proto.RecipeRequest{Name: "Expected Error"}},
+			want:     nil,
+			wantCode: codes.Internal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.DeleteRecipe(tt.args.ctx, tt.args.r)
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("serviceServer.DeleteRecipe() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if !pb.Equal(got, tt.want) {
+				t.Errorf("serviceServer.DeleteRecipe() = %v, want %v", got, tt.want)
+			}
 		})
 	}
 }
 
 func Test_serviceServer_FindRecipes(t *testing.T) {
+	if err := persistence.SetTaxonomy([]persistence.Kind{{Ingredient: "Mozzarella", Parent: "Cheese"}, {Ingredient: "Feta", Parent: "Cheese"}}); err != nil {
+		t.Fatalf("SetTaxonomy() error = %v", err)
+	}
+	t.Cleanup(func() { persistence.SetTaxonomy(nil) })
+
 	type args struct {
 		ctx context.Context
 		r   *proto.FindRequest
@@ -423,28 +795,28 @@ func Test_serviceServer_FindRecipes(t *testing.T) {
 			name:    "1",
 			s:       &serviceServer{db: NewMockDB()},
 			args:    args{ctx: context.Background(), r: &proto.FindRequest{Ingredients: []string{"Gruyere", "Emmental"}}},
-			want:    &proto.Recipes{Recipes: []*proto.Recipe{{Name: "Cheese Fondue", Ingredients: []string{"Gruyere", "Emmental"}}}},
+			want:    &proto.Recipes{Recipes: []*proto.Recipe{{Name: "Cheese Fondue", Ingredients: []string{"Gruyere", "Emmental"}, StructuredIngredients: []*proto.Ingredient{{Name: "Gruyere"}, {Name: "Emmental"}}}}, Matches: []*proto.Match{{Recipe: "Cheese Fondue", MissingIngredients: []string{}, MatchedIngredients: []string{"Gruyere", "Emmental"}, Matched: 2, Missing: 0, Extra: 0, Score: 1}}, Suggestions: []*proto.Suggestion{}},
 			wantErr: false,
 		},
 		{
 			name:    "2",
 			s:       &serviceServer{db: NewMockDB()},
 			args:    args{ctx: context.Background(), r: &proto.FindRequest{Ingredients: []string{"Emmental", "Gruyere"}}},
-			want:    &proto.Recipes{Recipes: []*proto.Recipe{{Name: "Cheese Fondue", Ingredients: []string{"Gruyere", "Emmental"}}}},
+			want:    &proto.Recipes{Recipes: []*proto.Recipe{{Name: "Cheese Fondue", Ingredients: []string{"Gruyere", "Emmental"}, StructuredIngredients: []*proto.Ingredient{{Name: "Gruyere"}, {Name: "Emmental"}}}}, Matches: []*proto.Match{{Recipe: "Cheese Fondue", MissingIngredients: []string{}, MatchedIngredients: []string{"Gruyere", "Emmental"}, Matched: 2, Missing: 0, Extra: 0, Score: 1}}, Suggestions: []*proto.Suggestion{}},
 			wantErr: false,
 		},
 		{
 			name:    "3",
 			s:       &serviceServer{db: NewMockDB()},
 			args:    args{ctx: context.Background(), r: &proto.FindRequest{Ingredients: []string{"Tomato"}}},
-			want:    &proto.Recipes{Recipes: []*proto.Recipe{{Name: "BLT", Ingredients: []string{"Tomato", "Bacon", "Lettuce"}}, {Name: "Caprese Salad", Ingredients: []string{"Mozzarella", "Tomato"}}, {Name: "Greek Salad", Ingredients: []string{"Feta", "Tomato", "Cucumber"}}, {Name: "Meatballs", Ingredients: []string{"Ground Beef", "Tomato"}}, {Name: "SpagBol", Ingredients: []string{"Spaghetti", "Ground Beef", "Tomato"}}}},
+			want:    &proto.Recipes{Recipes: []*proto.Recipe{{Name: "Caprese Salad", Ingredients: []string{"Mozzarella", "Tomato"}, StructuredIngredients: []*proto.Ingredient{{Name: "Mozzarella"}, {Name: "Tomato"}}}, {Name: "Meatballs", Ingredients: []string{"Ground Beef", "Tomato"}, StructuredIngredients: []*proto.Ingredient{{Name: "Ground Beef"}, {Name: "Tomato"}}}, {Name: "BLT", Ingredients: []string{"Tomato", "Bacon", "Lettuce"}, StructuredIngredients: []*proto.Ingredient{{Name: "Tomato"}, {Name: "Bacon"}, {Name: "Lettuce"}}}, {Name: "Greek Salad", Ingredients: []string{"Feta", "Tomato", "Cucumber"}, StructuredIngredients: []*proto.Ingredient{{Name: "Feta"}, {Name: "Tomato"}, {Name: "Cucumber"}}}, {Name: "SpagBol", Ingredients: []string{"Spaghetti", "Ground Beef", "Tomato"}, StructuredIngredients: []*proto.Ingredient{{Name: "Spaghetti"}, {Name: "Ground Beef"}, {Name: "Tomato"}}}}, Matches: []*proto.Match{{Recipe: "Caprese Salad", MissingIngredients: []string{"Mozzarella"}, MatchedIngredients: []string{"Tomato"}, Matched: 1, Missing: 1, Extra: 0, Score: 0.5}, {Recipe: "Meatballs", MissingIngredients: []string{"Ground Beef"}, MatchedIngredients: []string{"Tomato"}, Matched: 1, Missing: 1, Extra: 0, Score: 0.5}, {Recipe: "BLT", MissingIngredients: []string{"Bacon", "Lettuce"}, MatchedIngredients: []string{"Tomato"}, Matched: 1, Missing: 2, Extra: 0, Score: 1.0 / 3}, {Recipe: "Greek Salad", MissingIngredients: []string{"Feta", "Cucumber"}, MatchedIngredients: []string{"Tomato"}, Matched: 1, Missing: 2, Extra: 0, Score: 1.0 / 3}, {Recipe: "SpagBol", MissingIngredients: []string{"Spaghetti", "Ground Beef"}, MatchedIngredients: []string{"Tomato"}, Matched: 1, Missing: 2, Extra: 0, Score: 1.0 / 3}}, Suggestions: []*proto.Suggestion{}},
 			wantErr: false,
 		},
 		{
 			name:    "4",
 			s:       &serviceServer{db: NewMockDB()},
 			args:    args{ctx: context.Background(), r: &proto.FindRequest{Ingredients: []string{"Tomato", "Onion"}}},
-			want:    &proto.Recipes{Recipes: []*proto.Recipe{}},
+			want:    &proto.Recipes{Recipes: []*proto.Recipe{}, Matches: []*proto.Match{}, Suggestions: []*proto.Suggestion{}},
 			wantErr: false,
 		},
 		{
@@ -461,6 +833,90 @@ func Test_serviceServer_FindRecipes(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name:    "7",
+			s:       &serviceServer{db: NewMockDB()},
+			args:    args{ctx: context.Background(), r: &proto.FindRequest{Ingredients: []string{"Tomato", "Mozzarella", "Ground Beef"}, Mode: proto.MatchMode_MATCH_SUBSET, MaxMissing: 1}},
+			want:    &proto.Recipes{Recipes: []*proto.Recipe{{Name: "Caprese Salad", Ingredients: []string{"Mozzarella", "Tomato"}, StructuredIngredients: []*proto.Ingredient{{Name: "Mozzarella"}, {Name: "Tomato"}}}, {Name: "Meatballs", Ingredients: []string{"Ground Beef", "Tomato"}, StructuredIngredients: []*proto.Ingredient{{Name: "Ground Beef"}, {Name: "Tomato"}}}, {Name: "SpagBol", Ingredients: []string{"Spaghetti", "Ground Beef", "Tomato"}, StructuredIngredients: []*proto.Ingredient{{Name: "Spaghetti"}, {Name: "Ground Beef"}, {Name: "Tomato"}}}, {Name: "Mac & Cheese", Ingredients: []string{"Mozzarella", "Macaroni"}, StructuredIngredients: []*proto.Ingredient{{Name: "Mozzarella"}, {Name: "Macaroni"}}}}, Matches: []*proto.Match{{Recipe: "Caprese Salad", MissingIngredients: []string{}, MatchedIngredients: []string{"Mozzarella", "Tomato"}, Matched: 2, Missing: 0, Extra: 1, Score: 2.0 / 3}, {Recipe: "Meatballs", MissingIngredients: []string{}, MatchedIngredients: []string{"Ground Beef", "Tomato"}, Matched: 2, Missing: 0, Extra: 1, Score: 2.0 / 3}, {Recipe: "SpagBol", MissingIngredients: []string{"Spaghetti"}, MatchedIngredients: []string{"Ground Beef", "Tomato"}, Matched: 2, Missing: 1, Extra: 1, Score: 0.5}, {Recipe: "Mac & Cheese", MissingIngredients: []string{"Macaroni"}, MatchedIngredients: []string{"Mozzarella"}, Matched: 1, Missing: 1, Extra: 2, Score: 0.25}}, Suggestions: []*proto.Suggestion{}},
+			wantErr: false,
+		},
+		{
+			name:    "8",
+			s:       &serviceServer{db: NewMockDB()},
+			args:    args{ctx: context.Background(), r: &proto.FindRequest{Ingredients: []string{"Gruyere", "Macaroni"}, Mode: proto.MatchMode_MATCH_ANY}},
+			want:    &proto.Recipes{Recipes: []*proto.Recipe{{Name: "Cheese Fondue", Ingredients: []string{"Gruyere", "Emmental"}, StructuredIngredients: []*proto.Ingredient{{Name: "Gruyere"}, {Name: "Emmental"}}}, {Name: "Mac & Cheese", Ingredients: []string{"Mozzarella", "Macaroni"}, StructuredIngredients: []*proto.Ingredient{{Name: "Mozzarella"}, {Name: "Macaroni"}}}}, Matches: []*proto.Match{{Recipe: "Cheese Fondue", MissingIngredients: []string{"Emmental"}, MatchedIngredients: []string{"Gruyere"}, Matched: 1, Missing: 1, Extra: 1, Score: 1.0 / 3}, {Recipe: "Mac & Cheese", MissingIngredients: []string{"Mozzarella"}, MatchedIngredients: []string{"Macaroni"}, Matched: 1, Missing: 1, Extra: 1, Score: 1.0 / 3}}, Suggestions: []*proto.Suggestion{}},
+			wantErr: false,
+		},
+		{
+			name:    "9",
+			s:       &serviceServer{db: NewMockDB()},
+			args:    args{ctx: context.Background(), r: &proto.FindRequest{Ingredients: []string{"Tomato"}, Mode: proto.MatchMode(7)}},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "10",
+			s:       &serviceServer{db: NewMockDB()},
+			args:    args{ctx: context.Background(), r: &proto.FindRequest{Ingredients: []string{"Tomato"}, Mode: proto.MatchMode_MATCH_SUBSET, MaxMissing: -1}},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "11",
+			s:       &serviceServer{db: NewMockDB()},
+			args:    args{ctx: context.Background(), r: &proto.FindRequest{Ingredients: []string{"Tomato"}, Exclude: []string{"Ground Beef", "Bacon"}}},
+			want:    &proto.Recipes{Recipes: []*proto.Recipe{{Name: "Caprese Salad", Ingredients: []string{"Mozzarella", "Tomato"}, StructuredIngredients: []*proto.Ingredient{{Name: "Mozzarella"}, {Name: "Tomato"}}}, {Name: "Greek Salad", Ingredients: []string{"Feta", "Tomato", "Cucumber"}, StructuredIngredients: []*proto.Ingredient{{Name: "Feta"}, {Name: "Tomato"}, {Name: "Cucumber"}}}}, Matches: []*proto.Match{{Recipe: "Caprese Salad", MissingIngredients: []string{"Mozzarella"}, MatchedIngredients: []string{"Tomato"}, Matched: 1, Missing: 1, Extra: 0, Score: 0.5}, {Recipe: "Greek Salad", MissingIngredients: []string{"Feta", "Cucumber"}, MatchedIngredients: []string{"Tomato"}, Matched: 1, Missing: 2, Extra: 0, Score: 1.0 / 3}}, Suggestions: []*proto.Suggestion{}},
+			wantErr: false,
+		},
+		{
+			name:    "12",
+			s:       &serviceServer{db: NewMockDB()},
+			args:    args{ctx: context.Background(), r: &proto.FindRequest{Ingredients: []string{"Mozzarella", "Gruyere"}, Exclude: []string{"Tomato"}, Mode: proto.MatchMode_MATCH_ANY}},
+			want:    &proto.Recipes{Recipes: []*proto.Recipe{{Name: "Cheese Fondue", Ingredients: []string{"Gruyere", "Emmental"}, StructuredIngredients: []*proto.Ingredient{{Name: "Gruyere"}, {Name: "Emmental"}}}, {Name: "Mac & Cheese", Ingredients: []string{"Mozzarella", "Macaroni"}, StructuredIngredients: []*proto.Ingredient{{Name: "Mozzarella"}, {Name: "Macaroni"}}}}, Matches: []*proto.Match{{Recipe: "Cheese Fondue", MissingIngredients: []string{"Emmental"}, MatchedIngredients: []string{"Gruyere"}, Matched: 1, Missing: 1, Extra: 1, Score: 1.0 / 3}, {Recipe: "Mac & Cheese", MissingIngredients: []string{"Macaroni"}, MatchedIngredients: []string{"Mozzarella"}, Matched: 1, Missing: 1, Extra: 1, Score: 1.0 / 3}}, Suggestions: []*proto.Suggestion{}},
+			wantErr: false,
+		},
+		{
+			name:    "13",
+			s:       &serviceServer{db: NewMockDB()},
+			args:    args{ctx: context.Background(), r: &proto.FindRequest{Query: "Tomato AND (Mozzarella OR Feta) -Bacon"}},
+			want:    &proto.Recipes{Recipes: []*proto.Recipe{{Name: "Caprese Salad", Ingredients: []string{"Mozzarella", "Tomato"}, StructuredIngredients: []*proto.Ingredient{{Name: "Mozzarella"}, {Name: "Tomato"}}}, {Name: "Greek Salad", Ingredients: []string{"Feta", "Tomato", "Cucumber"}, StructuredIngredients: []*proto.Ingredient{{Name: "Feta"}, {Name: "Tomato"}, {Name: "Cucumber"}}}}, Matches: []*proto.Match{{Recipe: "Caprese Salad", MissingIngredients: []string{}, MatchedIngredients: []string{"Mozzarella", "Tomato"}, Matched: 2, Missing: 0, Extra: 1, Score: 2.0 / 3}, {Recipe: "Greek Salad", MissingIngredients: []string{"Cucumber"}, MatchedIngredients: []string{"Feta", "Tomato"}, Matched: 2, Missing: 1, Extra: 1, Score: 0.5}}, Suggestions: []*proto.Suggestion{}},
+			wantErr: false,
+		},
+		{
+			name:    "14",
+			s:       &serviceServer{db: NewMockDB()},
+			args:    args{ctx: context.Background(), r: &proto.FindRequest{Ingredients: []string{"Tomato"}, Query: "NOT Ground Beef"}},
+			want:    &proto.Recipes{Recipes: []*proto.Recipe{{Name: "Caprese Salad", Ingredients: []string{"Mozzarella", "Tomato"}, StructuredIngredients: []*proto.Ingredient{{Name: "Mozzarella"}, {Name: "Tomato"}}}, {Name: "BLT", Ingredients: []string{"Tomato", "Bacon", "Lettuce"}, StructuredIngredients: []*proto.Ingredient{{Name: "Tomato"}, {Name: "Bacon"}, {Name: "Lettuce"}}}, {Name: "Greek Salad", Ingredients: []string{"Feta", "Tomato", "Cucumber"}, StructuredIngredients: []*proto.Ingredient{{Name: "Feta"}, {Name: "Tomato"}, {Name: "Cucumber"}}}}, Matches: []*proto.Match{{Recipe: "Caprese Salad", MissingIngredients: []string{"Mozzarella"}, MatchedIngredients: []string{"Tomato"}, Matched: 1, Missing: 1, Extra: 0, Score: 0.5}, {Recipe: "BLT", MissingIngredients: []string{"Bacon", "Lettuce"}, MatchedIngredients: []string{"Tomato"}, Matched: 1, Missing: 2, Extra: 0, Score: 1.0 / 3}, {Recipe: "Greek Salad", MissingIngredients: []string{"Feta", "Cucumber"}, MatchedIngredients: []string{"Tomato"}, Matched: 1, Missing: 2, Extra: 0, Score: 1.0 / 3}}, Suggestions: []*proto.Suggestion{}},
+			wantErr: false,
+		},
+		{
+			name:    "15",
+			s:       &serviceServer{db: NewMockDB()},
+			args:    args{ctx: context.Background(), r: &proto.FindRequest{Ingredients: []string{"Tomato"}, Query: "Mozzarella OR"}},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "16",
+			s:       &serviceServer{db: NewMockDB()},
+			args:    args{ctx: context.Background(), r: &proto.FindRequest{Ingredients: []string{"Tomatoe", "Mozarella"}}},
+			want:    &proto.Recipes{Recipes: []*proto.Recipe{}, Matches: []*proto.Match{}, Suggestions: []*proto.Suggestion{{Name: "Tomatoe", Suggestions: []string{"Tomato"}}, {Name: "Mozarella", Suggestions: []string{"Mozzarella"}}}},
+			wantErr: false,
+		},
+		{
+			name:    "17",
+			s:       &serviceServer{db: NewMockDB()},
+			args:    args{ctx: context.Background(), r: &proto.FindRequest{Ingredients: []string{"Tomatoe", "Mozarella"}, Fuzzy: true}},
+			want:    &proto.Recipes{Recipes: []*proto.Recipe{{Name: "Caprese Salad", Ingredients: []string{"Mozzarella", "Tomato"}, StructuredIngredients: []*proto.Ingredient{{Name: "Mozzarella"}, {Name: "Tomato"}}}}, Matches: []*proto.Match{{Recipe: "Caprese Salad", MissingIngredients: []string{}, MatchedIngredients: []string{"Mozzarella", "Tomato"}, Matched: 2, Missing: 0, Extra: 0, Score: 1}}, Suggestions: []*proto.Suggestion{{Name: "Tomatoe", Suggestions: []string{"Tomato"}}, {Name: "Mozarella", Suggestions: []string{"Mozzarella"}}}},
+			wantErr: false,
+		},
+		{
+			name:    "18",
+			s:       &serviceServer{db: NewMockDB()},
+			args:    args{ctx: context.Background(), r: &proto.FindRequest{Ingredients: []string{"Cheese", "Tomato"}, Expand: true}},
+			want:    &proto.Recipes{Recipes: []*proto.Recipe{{Name: "Caprese Salad", Ingredients: []string{"Mozzarella", "Tomato"}, StructuredIngredients: []*proto.Ingredient{{Name: "Mozzarella"}, {Name: "Tomato"}}}, {Name: "Greek Salad", Ingredients: []string{"Feta", "Tomato", "Cucumber"}, StructuredIngredients: []*proto.Ingredient{{Name: "Feta"}, {Name: "Tomato"}, {Name: "Cucumber"}}}}, Matches: []*proto.Match{{Recipe: "Caprese Salad", MissingIngredients: []string{}, MatchedIngredients: []string{"Mozzarella", "Tomato"}, Matched: 2, Missing: 0, Extra: 0, Score: 1}, {Recipe: "Greek Salad", MissingIngredients: []string{"Cucumber"}, MatchedIngredients: []string{"Feta", "Tomato"}, Matched: 2, Missing: 1, Extra: 0, Score: 2.0 / 3}}, Suggestions: []*proto.Suggestion{}},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -475,3 +931,632 @@ func Test_serviceServer_FindRecipes(t *testing.T) {
 		})
 	}
 }
+
+func Test_serviceServer_ListRecipes(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		r   *proto.ListRequest
+	}
+	tests := []struct {
+		name     string
+		s        *serviceServer
+		args     args
+		want     *proto.RecipePage
+		wantCode codes.Code
+	}{
+		{
+			name:     "1",
+			s:        &serviceServer{db: NewMockDB()},
+			args:     args{ctx: context.Background(), r: &proto.ListRequest{PageSize: 3}},
+			want:     &proto.RecipePage{Recipes: []*proto.Recipe{{Name: "BLT", Ingredients: []string{"Tomato", "Bacon", "Lettuce"}, StructuredIngredients: []*proto.Ingredient{{Name: "Tomato"}, {Name: "Bacon"}, {Name: "Lettuce"}}}, {Name: "Caprese Salad", Ingredients: []string{"Mozzarella", "Tomato"}, StructuredIngredients: []*proto.Ingredient{{Name: "Mozzarella"}, {Name: "Tomato"}}}, {Name: "Cheese Fondue", Ingredients: []string{"Gruyere", "Emmental"}, StructuredIngredients: []*proto.Ingredient{{Name: "Gruyere"}, {Name: "Emmental"}}}}, NextPageToken: persistence.EncodePageToken("Cheese Fondue")},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "2",
+			s:        &serviceServer{db: NewMockDB()},
+			args:     args{ctx: context.Background(), r: &proto.ListRequest{PageSize: 3, PageToken: persistence.EncodePageToken("Mac & Cheese")}},
+			want:     &proto.RecipePage{Recipes: []*proto.Recipe{{Name: "Meatballs", Ingredients: []string{"Ground Beef", "Tomato"}, StructuredIngredients: []*proto.Ingredient{{Name: "Ground Beef"}, {Name: "Tomato"}}}, {Name: "SpagBol", Ingredients: []string{"Spaghetti", "Ground Beef", "Tomato"}, StructuredIngredients: []*proto.Ingredient{{Name: "Spaghetti"}, {Name: "Ground Beef"}, {Name: "Tomato"}}}}},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "3",
+			s:        &serviceServer{db: NewMockDB()},
+			args:     args{ctx: context.Background(), r: &proto.ListRequest{PageSize: 2, PageToken: persistence.EncodePageToken("Greek Salad")}},
+			want:     &proto.RecipePage{Recipes: []*proto.Recipe{{Name: "Mac & Cheese", Ingredients: []string{"Mozzarella", "Macaroni"}, StructuredIngredients: []*proto.Ingredient{{Name: "Mozzarella"}, {Name: "Macaroni"}}}, {Name: "Meatballs", Ingredients: []string{"Ground Beef", "Tomato"}, StructuredIngredients: []*proto.Ingredient{{Name: "Ground Beef"}, {Name: "Tomato"}}}}, NextPageToken: persistence.EncodePageToken("Meatballs")},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "4",
+			s:        &serviceServer{db: NewMockDB()},
+			args:     args{ctx: context.Background(), r: &proto.ListRequest{PageToken: persistence.EncodePageToken("SpagBol")}},
+			want:     &proto.RecipePage{Recipes: []*proto.Recipe{}},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "5",
+			s:        &serviceServer{db: NewMockDB()},
+			args:     args{ctx: context.Background(), r: &proto.ListRequest{PageSize: -1}},
+			want:     nil,
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "6",
+			s:        &serviceServer{db: NewMockDB()},
+			args:     args{ctx: context.Background(), r: &proto.ListRequest{PageToken: "not a token!"}},
+			want:     nil,
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "7",
+			s:        &serviceServer{db: NewMockDB()},
+			args:     args{ctx: context.Background(), r: &proto.ListRequest{PageToken: persistence.EncodePageToken("Expected Error")}},
+			want:     nil,
+			wantCode: codes.Internal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.ListRecipes(tt.args.ctx, tt.args.r)
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("serviceServer.ListRecipes() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if !pb.Equal(got, tt.want) {
+				t.Errorf("serviceServer.ListRecipes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+func Test_serviceServer_SearchRecipesByName(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		r   *proto.NameSearchRequest
+	}
+	tests := []struct {
+		name     string
+		s        *serviceServer
+		args     args
+		want     *proto.RecipePage
+		wantCode codes.Code
+	}{
+		{
+			name:     "1",
+			s:        &serviceServer{db: NewMockDB()},
+			args:     args{ctx: context.Background(), r: &proto.NameSearchRequest{Name: "salad", Match: proto.NameMatch_NAME_SUBSTRING}},
+			want:     &proto.RecipePage{Recipes: []*proto.Recipe{{Name: "Caprese Salad", Ingredients: []string{"Mozzarella", "Tomato"}, StructuredIngredients: []*proto.Ingredient{{Name: "Mozzarella"}, {Name: "Tomato"}}}, {Name: "Greek Salad", Ingredients: []string{"Feta", "Tomato", "Cucumber"}, StructuredIngredients: []*proto.Ingredient{{Name: "Feta"}, {Name: "Tomato"}, {Name: "Cucumber"}}}}},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "2",
+			s:        &serviceServer{db: NewMockDB()},
+			args:     args{ctx: context.Background(), r: &proto.NameSearchRequest{Name: "salad"}},
+			want:     &proto.RecipePage{Recipes: []*proto.Recipe{}},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "3",
+			s:        &serviceServer{db: NewMockDB()},
+			args:     args{ctx: context.Background(), r: &proto.NameSearchRequest{Name: "MAC", Match: proto.NameMatch_NAME_PREFIX}},
+			want:     &proto.RecipePage{Recipes: []*proto.Recipe{{Name: "Mac & Cheese", Ingredients: []string{"Mozzarella", "Macaroni"}, StructuredIngredients: []*proto.Ingredient{{Name: "Mozzarella"}, {Name: "Macaroni"}}}}},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "4",
+			s:        &serviceServer{db: NewMockDB()},
+			args:     args{ctx: context.Background(), r: &proto.NameSearchRequest{Name: "e", Match: proto.NameMatch_NAME_SUBSTRING, PageSize: 1}},
+			want:     &proto.RecipePage{Recipes: []*proto.Recipe{{Name: "Caprese Salad", Ingredients: []string{"Mozzarella", "Tomato"}, StructuredIngredients: []*proto.Ingredient{{Name: "Mozzarella"}, {Name: "Tomato"}}}}, NextPageToken: persistence.EncodePageToken("Caprese Salad")},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "5",
+			s:        &serviceServer{db: NewMockDB()},
+			args:     args{ctx: context.Background(), r: &proto.NameSearchRequest{Name: "e", Match: proto.NameMatch_NAME_SUBSTRING, PageSize: 1, PageToken: persistence.EncodePageToken("Caprese Salad")}},
+			want:     &proto.RecipePage{Recipes: []*proto.Recipe{{Name: "Cheese Fondue", Ingredients: []string{"Gruyere", "Emmental"}, StructuredIngredients: []*proto.Ingredient{{Name: "Gruyere"}, {Name: "Emmental"}}}}, NextPageToken: persistence.EncodePageToken("Cheese Fondue")},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "6",
+			s:        &serviceServer{db: NewMockDB()},
+			args:     args{ctx: context.Background(), r: &proto.NameSearchRequest{Name: " "}},
+			want:     nil,
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "7",
+			s:        &serviceServer{db: NewMockDB()},
+			args:     args{ctx: context.Background(), r: &proto.NameSearchRequest{Name: "salad", Match: 5}},
+			want:     nil,
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "8",
+			s:        &serviceServer{db: NewMockDB()},
+			args:     args{ctx: context.Background(), r: &proto.NameSearchRequest{Name: "salad", PageSize: -1}},
+			want:     nil,
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "9",
+			s:        &serviceServer{db: NewMockDB()},
+			args:     args{ctx: context.Background(), r: &proto.NameSearchRequest{Name: "salad", PageToken: "not a token!"}},
+			want:     nil,
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "10",
+			s:        &serviceServer{db: NewMockDB()},
+			args:     args{ctx: context.Background(), r: &proto.NameSearchRequest{Name: "Expected Error"}},
+			want:     nil,
+			wantCode: codes.Internal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.SearchRecipesByName(tt.args.ctx, tt.args.r)
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("serviceServer.SearchRecipesByName() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if !pb.Equal(got, tt.want) {
+				t.Errorf("serviceServer.SearchRecipesByName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_serviceServer_ListIngredients(t *testing.T) {
+	s := &serviceServer{db: NewMockDB()}
+	got, err := s.ListIngredients(context.Background(), &emptypb.Empty{})
+	if err != nil {
+		t.Fatalf("serviceServer.ListIngredients() error = %v", err)
+	}
+	want := &proto.Ingredients{Ingredients: []*proto.IngredientUsage{
+		{Name: "Bacon", Recipes: 1}, {Name: "Cucumber", Recipes: 1}, {Name: "Emmental", Recipes: 1}, {Name: "Feta", Recipes: 1},
+		{Name: "Ground Beef", Recipes: 2}, {Name: "Gruyere", Recipes: 1}, {Name: "Lettuce", Recipes: 1}, {Name: "Macaroni", Recipes: 1},
+		{Name: "Mozzarella", Recipes: 2}, {Name: "Spaghetti", Recipes: 1}, {Name: "Tomato", Recipes: 5},
+	}}
+	if !pb.Equal(got, want) {
+		t.Errorf("serviceServer.ListIngredients() = %v, want %v", got, want)
+	}
+}
+
+func Test_serviceServer_GetSimilarRecipes(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		r   *proto.SimilarRequest
+	}
+	tests := []struct {
+		name     string
+		s        *serviceServer
+		args     args
+		want     *proto.SimilarRecipes
+		wantCode codes.Code
+	}{
+		{
+			name: "1",
+			s:    &serviceServer{db: NewMockDB()},
+			args: args{ctx: context.Background(), r: &proto.SimilarRequest{Name: "SpagBol"}},
+			want: &proto.SimilarRecipes{Recipes: []*proto.SimilarRecipe{
+				{Name: "Meatballs", Similarity: 2.0 / 3, SharedIngredients: []string{"Ground Beef", "Tomato"}},
+				{Name: "Caprese Salad", Similarity: 0.25, SharedIngredients: []string{"Tomato"}},
+				{Name: "BLT", Similarity: 0.2, SharedIngredients: []string{"Tomato"}},
+				{Name: "Greek Salad", Similarity: 0.2, SharedIngredients: []string{"Tomato"}},
+			}},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "2",
+			s:        &serviceServer{db: NewMockDB()},
+			args:     args{ctx: context.Background(), r: &proto.SimilarRequest{Name: "SpagBol", Limit: 1}},
+			want:     &proto.SimilarRecipes{Recipes: []*proto.SimilarRecipe{{Name: "Meatballs", Similarity: 2.0 / 3, SharedIngredients: []string{"Ground Beef", "Tomato"}}}},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "3",
+			s:        &serviceServer{db: NewMockDB()},
+			args:     args{ctx: context.Background(), r: &proto.SimilarRequest{Name: "Cheese Fondue"}},
+			want:     &proto.SimilarRecipes{Recipes: []*proto.SimilarRecipe{}},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "4",
+			s:        &serviceServer{db: NewMockDB()},
+			args:     args{ctx: context.Background(), r: &proto.SimilarRequest{}},
+			want:     nil,
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "5",
+			s:        &serviceServer{db: NewMockDB()},
+			args:     args{ctx: context.Background(), r: &proto.SimilarRequest{Name: "SpagBol", Limit: -1}},
+			want:     nil,
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "6",
+			s:        &serviceServer{db: NewMockDB()},
+			args:     args{ctx: context.Background(), r: &proto.SimilarRequest{Name: "Spag Bol"}},
+			want:     nil,
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "7",
+			s:        &serviceServer{db: NewMockDB()},
+			args:     args{ctx: context.Background(), r: &proto.SimilarRequest{Name: "Expected Error"}},
+			want:     nil,
+			wantCode: codes.Internal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.GetSimilarRecipes(tt.args.ctx, tt.args.r)
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("serviceServer.GetSimilarRecipes() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if !pb.Equal(got, tt.want) {
+				t.Errorf("serviceServer.GetSimilarRecipes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_serviceServer_GetStats(t *testing.T) {
+	s := &serviceServer{db: NewMockDB()}
+	got, err := s.GetStats(context.Background(), &emptypb.Empty{})
+	if err != nil {
+		t.Fatalf("serviceServer.GetStats() error = %v", err)
+	}
+	want := &proto.Stats{
+		Recipes:     7,
+		Ingredients: 11,
+		MostUsed: []*proto.IngredientUsage{
+			{Name: "Tomato", Recipes: 5}, {Name: "Ground Beef", Recipes: 2}, {Name: "Mozzarella", Recipes: 2}, {Name: "Bacon", Recipes: 1}, {Name: "Cucumber", Recipes: 1},
+		},
+		LeastUsed: []*proto.IngredientUsage{
+			{Name: "Bacon", Recipes: 1}, {Name: "Cucumber", Recipes: 1}, {Name: "Emmental", Recipes: 1}, {Name: "Feta", Recipes: 1}, {Name: "Gruyere", Recipes: 1},
+		},
+		AverageIngredients: 17.0 / 7,
+		IsolatedRecipes:    []string{"Cheese Fondue"},
+	}
+	if !pb.Equal(got, want) {
+		t.Errorf("serviceServer.GetStats() = %v, want %v", got, want)
+	}
+}
+
+func Test_serviceServer_CompleteIngredient(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		r   *proto.CompleteRequest
+	}
+	tests := []struct {
+		name     string
+		s        *serviceServer
+		args     args
+		want     *proto.Ingredients
+		wantCode codes.Code
+	}{
+		{
+			name:     "1",
+			s:        &serviceServer{db: NewMockDB()},
+			args:     args{ctx: context.Background(), r: &proto.CompleteRequest{Prefix: "m"}},
+			want:     &proto.Ingredients{Ingredients: []*proto.IngredientUsage{{Name: "Mozzarella", Recipes: 2}, {Name: "Macaroni", Recipes: 1}}},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "2",
+			s:        &serviceServer{db: NewMockDB()},
+			args:     args{ctx: context.Background(), r: &proto.CompleteRequest{Limit: 2}},
+			want:     &proto.Ingredients{Ingredients: []*proto.IngredientUsage{{Name: "Tomato", Recipes: 5}, {Name: "Ground Beef", Recipes: 2}}},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "3",
+			s:        &serviceServer{db: NewMockDB()},
+			args:     args{ctx: context.Background(), r: &proto.CompleteRequest{Prefix: "Tomatoes"}},
+			want:     &proto.Ingredients{Ingredients: []*proto.IngredientUsage{{Name: "Tomato", Recipes: 5}}},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "4",
+			s:        &serviceServer{db: NewMockDB()},
+			args:     args{ctx: context.Background(), r: &proto.CompleteRequest{Prefix: "x"}},
+			want:     &proto.Ingredients{Ingredients: []*proto.IngredientUsage{}},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "5",
+			s:        &serviceServer{db: NewMockDB()},
+			args:     args{ctx: context.Background(), r: &proto.CompleteRequest{Prefix: "m", Limit: -1}},
+			want:     nil,
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "6",
+			s:        &serviceServer{db: NewMockDB()},
+			args:     args{ctx: context.Background(), r: &proto.CompleteRequest{Prefix: "Expected Error"}},
+			want:     nil,
+			wantCode: codes.Internal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.CompleteIngredient(tt.args.ctx, tt.args.r)
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("serviceServer.CompleteIngredient() error = %v, wantCode %v", err, tt.wantCode)
+				return
+			}
+			if !pb.Equal(got, tt.want) {
+				t.Errorf("serviceServer.CompleteIngredient() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_serviceServer_GetTaxonomy(t *testing.T) {
+	if err := persistence.SetTaxonomy([]persistence.Kind{{Ingredient: "Mozzarella", Parent: "Cheese"}, {Ingredient: "Cheese", Parent: "Dairy"}}); err != nil {
+		t.Fatalf("SetTaxonomy() error = %v", err)
+	}
+	t.Cleanup(func() { persistence.SetTaxonomy(nil) })
+
+	s := &serviceServer{db: NewMockDB()}
+	got, err := s.GetTaxonomy(context.Background(), &emptypb.Empty{})
+	if err != nil {
+		t.Fatalf("serviceServer.GetTaxonomy() error = %v", err)
+	}
+	want := &proto.Taxonomy{Kinds: []*proto.Kind{{Ingredient: "Cheese", Parent: "Dairy"}, {Ingredient: "Mozzarella", Parent: "Cheese"}}}
+	if !pb.Equal(got, want) {
+		t.Errorf("serviceServer.GetTaxonomy() = %v, want %v", got, want)
+	}
+}
+
+func Test_serviceServer_SetIngredientKind(t *testing.T) {
+	if err := persistence.SetTaxonomy([]persistence.Kind{{Ingredient: "Mozzarella", Parent: "Cheese"}}); err != nil {
+		t.Fatalf("SetTaxonomy() error = %v", err)
+	}
+	t.Cleanup(func() { persistence.SetTaxonomy(nil) })
+
+	admin := metadata.NewIncomingContext(context.Background(), metadata.MD{"x-admin-key": []string{"5678"}})
+
+	tests := []struct {
+		name     string
+		adminKey string
+		ctx      context.Context
+		r        *proto.Kind
+		wantCode codes.Code
+		want     []persistence.Kind
+	}{
+		{
+			name:     "1",
+			adminKey: "5678",
+			ctx:      admin,
+			r:        &proto.Kind{Ingredient: "Cheese", Parent: "Dairy"},
+			wantCode: codes.OK,
+			want:     []persistence.Kind{{Ingredient: "Cheese", Parent: "Dairy"}, {Ingredient: "Mozzarella", Parent: "Cheese"}},
+		},
+		{
+			name:     "2",
+			adminKey: "5678",
+			ctx:      admin,
+			r:        &proto.Kind{Ingredient: "Dairy", Parent: "Mozzarella"},
+			wantCode: codes.InvalidArgument,
+			want:     []persistence.Kind{{Ingredient: "Cheese", Parent: "Dairy"}, {Ingredient: "Mozzarella", Parent: "Cheese"}},
+		},
+		{
+			name:     "3",
+			adminKey: "5678",
+			ctx:      admin,
+			r:        &proto.Kind{Ingredient: " ", Parent: "Dairy"},
+			wantCode: codes.InvalidArgument,
+			want:     []persistence.Kind{{Ingredient: "Cheese", Parent: "Dairy"}, {Ingredient: "Mozzarella", Parent: "Cheese"}},
+		},
+		{
+			name:     "4",
+			adminKey: "5678",
+			ctx:      admin,
+			r:        &proto.Kind{Ingredient: "Mozzarella"},
+			wantCode: codes.OK,
+			want:     []persistence.Kind{{Ingredient: "Cheese", Parent: "Dairy"}},
+		},
+		{
+			name:     "5",
+			adminKey: "5678",
+			ctx:      metadata.NewIncomingContext(context.Background(), metadata.MD{"x-admin-key": []string{"1234"}}),
+			r:        &proto.Kind{Ingredient: "Cheese"},
+			wantCode: codes.PermissionDenied,
+			want:     []persistence.Kind{{Ingredient: "Cheese", Parent: "Dairy"}},
+		},
+		{
+			name:     "6",
+			adminKey: "5678",
+			ctx:      context.Background(),
+			r:        &proto.Kind{Ingredient: "Cheese"},
+			wantCode: codes.PermissionDenied,
+			want:     []persistence.Kind{{Ingredient: "Cheese", Parent: "Dairy"}},
+		},
+		{
+			name:     "7",
+			ctx:      admin,
+			r:        &proto.Kind{Ingredient: "Cheese"},
+			wantCode: codes.PermissionDenied,
+			want:     []persistence.Kind{{Ingredient: "Cheese", Parent: "Dairy"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &serviceServer{db: NewMockDB(), adminKey: tt.adminKey}
+			if _, err := s.SetIngredientKind(tt.ctx, tt.r); status.Code(err) != tt.wantCode {
+				t.Errorf("serviceServer.SetIngredientKind() error = %v, wantCode %v", err, tt.wantCode)
+			}
+			if got := persistence.Taxonomy(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("persistence.Taxonomy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_serviceServer_ContextErrors(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	tests := []struct {
+		name     string
+		s        *serviceServer
+		ctx      context.Context
+		call     func(s *serviceServer, ctx context.Context) error
+		wantCode codes.Code
+	}{
+		{
+			name: "1",
+			s:    &serviceServer{db: NewMockDB()},
+			ctx:  cancelled,
+			call: func(s *serviceServer, ctx context.Context) error {
+				_, err := s.GetRecipe(ctx, &proto.RecipeRequest{Name: "BLT"})
+				return err
+			},
+			wantCode: codes.Canceled,
+		},
+		{
+			name: "2",
+			s:    &serviceServer{db: NewMockDB()},
+			ctx:  expired,
+			call: func(s *serviceServer, ctx context.Context) error {
+				_, err := s.FindRecipes(ctx, &proto.FindRequest{Ingredients: []string{"Tomato"}})
+				return err
+			},
+			wantCode: codes.DeadlineExceeded,
+		},
+		{
+			name: "3",
+			s:    &serviceServer{db: NewMockDB()},
+			ctx:  cancelled,
+			call: func(s *serviceServer, ctx context.Context) error {
+				_, err := s.ListIngredients(ctx, &emptypb.Empty{})
+				return err
+			},
+			wantCode: codes.Canceled,
+		},
+		{
+			name: "4",
+			s:    &serviceServer{db: NewMockDB()},
+			ctx:  expired,
+			call: func(s *serviceServer, ctx context.Context) error {
+				_, err := s.GetStats(ctx, &emptypb.Empty{})
+				return err
+			},
+			wantCode: codes.DeadlineExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call(tt.s, tt.ctx)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("serviceServer error code = %v, want %v", got, tt.wantCode)
+			}
+		})
+	}
+}
+
+func Test_recipeToDB(t *testing.T) {
+	tests := []struct {
+		name string
+		r    *proto.Recipe
+		want persistence.Recipe
+	}{
+		{
+			name: "1",
+			r:    &proto.Recipe{Name: "BLT", Ingredients: []string{"Tomato", "Bacon"}},
+			want: persistence.Recipe{Name: "BLT", Ingredients: []persistence.Ingredient{{Name: "Tomato"}, {Name: "Bacon"}}},
+		},
+		{
+			name: "2",
+			r:    &proto.Recipe{Name: "BLT", StructuredIngredients: []*proto.Ingredient{{Name: "Tomato", Quantity: 1, Note: "sliced"}, {Name: "Bacon", Quantity: 4, Unit: "rashers"}}},
+			want: persistence.Recipe{Name: "BLT", Ingredients: []persistence.Ingredient{{Name: "Tomato", Quantity: 1, Note: "sliced"}, {Name: "Bacon", Quantity: 4, Unit: "rashers"}}},
+		},
+		{
+			name: "3",
+			r:    &proto.Recipe{Name: "BLT", Ingredients: []string{"Lettuce"}, StructuredIngredients: []*proto.Ingredient{{Name: "Bacon", Quantity: 4, Unit: "rashers"}}},
+			want: persistence.Recipe{Name: "BLT", Ingredients: []persistence.Ingredient{{Name: "Bacon", Quantity: 4, Unit: "rashers"}}},
+		},
+		{
+			name: "4",
+			r:    &proto.Recipe{Name: "Water"},
+			want: persistence.Recipe{Name: "Water"},
+		},
+		{
+			name: "5",
+			r: &proto.Recipe{
+				Name:         "Toast",
+				Ingredients:  []string{"Bread"},
+				Description:  "Crunchy",
+				Instructions: []string{"Slice.", "Toast."},
+				Servings:     1,
+				PrepMinutes:  1,
+				CookMinutes:  3,
+				Source:       "me",
+			},
+			want: persistence.Recipe{
+				Name:         "Toast",
+				Ingredients:  []persistence.Ingredient{{Name: "Bread"}},
+				Description:  "Crunchy",
+				Instructions: []string{"Slice.", "Toast."},
+				Servings:     1,
+				PrepMinutes:  1,
+				CookMinutes:  3,
+				Source:       "me",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := recipeToDB(tt.r); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("recipeToDB() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_recipeFromDB(t *testing.T) {
+	tests := []struct {
+		name string
+		r    persistence.Recipe
+		want *proto.Recipe
+	}{
+		{
+			name: "1",
+			r:    persistence.Recipe{Name: "BLT", Ingredients: []persistence.Ingredient{{Name: "Tomato", Quantity: 1, Note: "sliced"}, {Name: "Bacon", Quantity: 4, Unit: "rashers"}}},
+			want: &proto.Recipe{
+				Name:                  "BLT",
+				Ingredients:           []string{"Tomato", "Bacon"},
+				StructuredIngredients: []*proto.Ingredient{{Name: "Tomato", Quantity: 1, Note: "sliced"}, {Name: "Bacon", Quantity: 4, Unit: "rashers"}},
+			},
+		},
+		{
+			name: "2",
+			r:    persistence.Recipe{Name: "Water"},
+			want: &proto.Recipe{Name: "Water"},
+		},
+		{
+			name: "3",
+			r:    persistence.Recipe{Name: "Toast", Ingredients: []persistence.Ingredient{{Name: "Bread"}}, Instructions: []string{"Toast."}, Servings: 1, CookMinutes: 3, Source: "me"},
+			want: &proto.Recipe{
+				Name:                  "Toast",
+				Ingredients:           []string{"Bread"},
+				StructuredIngredients: []*proto.Ingredient{{Name: "Bread"}},
+				Instructions:          []string{"Toast."},
+				Servings:              1,
+				CookMinutes:           3,
+				Source:                "me",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := recipeFromDB(tt.r); !pb.Equal(got, tt.want) {
+				t.Errorf("recipeFromDB() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
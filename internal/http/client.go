@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -65,45 +66,300 @@ func (c *HttpClient) AddRecipe(recipe Recipe) error {
 
 // GetRecipe calls the `GET /recipe/{name}` endpoint
 func (c *HttpClient) GetRecipe(name string) (*Recipe, error) {
+	recipe, _, err := c.LookupRecipe(name, false)
+
+	return recipe, err
+}
+
+// LookupRecipe calls the `GET /recipe/{name}?fuzzy={fuzzy}` endpoint. If there is no such recipe, it returns
+// a nil recipe and the names of similar recipes, closest first.
+func (c *HttpClient) LookupRecipe(name string, fuzzy bool) (*Recipe, []string, error) {
+	params := url.Values{}
+	if fuzzy {
+		params.Set("fuzzy", "true")
+	}
+
+	return c.lookupRecipe(name, params)
+}
+
+// ExpandRecipe calls the `GET /recipe/{name}?expand=true` endpoint, which replaces the components of the
+// recipe by their ingredients. It returns a nil recipe if there is no such recipe.
+func (c *HttpClient) ExpandRecipe(name string) (*Recipe, error) {
+	recipe, _, err := c.lookupRecipe(name, url.Values{"expand": {"true"}})
+
+	return recipe, err
+}
+
+// ScaleRecipe calls the `GET /recipe/{name}?servings={servings}&units={units}` endpoint, which scales the
+// quantities of the recipe to the servings (unless 0) and converts them to "metric" or "imperial" units (unless
+// empty). It returns a nil recipe if there is no such recipe.
+func (c *HttpClient) ScaleRecipe(name string, servings int, units string) (*Recipe, error) {
+	params := url.Values{}
+	if servings != 0 {
+		params.Set("servings", strconv.Itoa(servings))
+	}
+	if units != "" {
+		params.Set("units", units)
+	}
+	recipe, _, err := c.lookupRecipe(name, params)
+
+	return recipe, err
+}
+
+// lookupRecipe calls the `GET /recipe/{name}` endpoint with the query parameters
+func (c *HttpClient) lookupRecipe(name string, params url.Values) (*Recipe, []string, error) {
 	var recipe *Recipe
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/recipe/%s", c.address, url.QueryEscape(name)), nil)
+	address := fmt.Sprintf("%s/recipe/%s", c.address, url.QueryEscape(name))
+	if len(params) > 0 {
+		address += "?" + params.Encode()
+	}
+	req, err := http.NewRequest("GET", address, nil)
 	if err != nil {
-		return nil, fmt.Errorf("creating http request: %w", err)
+		return nil, nil, fmt.Errorf("creating http request: %w", err)
 	}
 	req.Header.Add("X-Api-Key", c.apiKey)
 
 	res, err := c.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("calling http endpoint: %w", err)
+		return nil, nil, fmt.Errorf("calling http endpoint: %w", err)
 	}
 	defer res.Body.Close()
 
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
-		return nil, fmt.Errorf("reading response: %w", err)
+		return nil, nil, fmt.Errorf("reading response: %w", err)
 	}
 
 	if res.StatusCode == http.StatusNotFound {
-		return nil, nil
+		return nil, notFoundSuggestions(body), nil
 	}
 
 	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(res.Status)
+		return nil, nil, fmt.Errorf(res.Status)
 	}
 
 	err = json.Unmarshal(body, &recipe)
 	if err != nil {
-		return nil, fmt.Errorf("unmarshalling response: %v", err)
+		return nil, nil, fmt.Errorf("unmarshalling response: %v", err)
+	}
+
+	return recipe, nil, nil
+}
+
+// notFoundSuggestions reads the suggestions from the body of a 404 response, which is a NotFound from
+// the HTTP server or a status with a Suggestion in its details from the gRPC gateway. A body without
+// suggestions has none.
+func notFoundSuggestions(body []byte) []string {
+	var rsp struct {
+		Suggestions []string     `json:"suggestions"`
+		Details     []Suggestion `json:"details"`
+	}
+	if err := json.Unmarshal(body, &rsp); err != nil {
+		return nil
+	}
+	for _, detail := range rsp.Details {
+		rsp.Suggestions = append(rsp.Suggestions, detail.Suggestions...)
+	}
+
+	return rsp.Suggestions
+}
+
+// DeleteRecipe calls the `DELETE /recipe/{name}` endpoint, returning false if no such recipe exists
+func (c *HttpClient) DeleteRecipe(name string) (bool, error) {
+	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/recipe/%s", c.address, url.QueryEscape(name)), nil)
+	if err != nil {
+		return false, fmt.Errorf("creating http request: %w", err)
+	}
+	req.Header.Add("X-Api-Key", c.apiKey)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("calling http endpoint: %w", err)
+	}
+	defer res.Body.Close()
+
+	_, err = io.ReadAll(res.Body)
+	if err != nil {
+		return false, fmt.Errorf("reading response: %w", err)
+	}
+
+	if res.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return false, fmt.Errorf(res.Status)
 	}
 
-	return recipe, nil
+	return true, nil
 }
 
 // SearchByIngredients calls the `GET /recipes?ingredients={list of ingredients}` endpoint
 func (c *HttpClient) SearchByIngredients(ingredients []string) ([]Recipe, error) {
+	recipes, err := c.SearchRecipes(Search{Ingredients: ingredients})
+
+	return recipes.Recipes, err
+}
+
+// SearchRecipes calls the `GET /recipes?ingredients={list of ingredients}&exclude={list of ingredients}&q={query}&mode={mode}&max_missing={max missing}&fuzzy={fuzzy}&expand={expand}`
+// endpoint, returning the recipes found, how each of them matched and suggestions for any unknown ingredients
+func (c *HttpClient) SearchRecipes(search Search) (Recipes, error) {
 	var recipes Recipes
-	list := strings.Join(ingredients, ",")
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/recipes?ingredients=%s", c.address, url.QueryEscape(list)), nil)
+	mode := search.Mode
+	if mode == "" {
+		mode = MatchAll
+	}
+	params := url.Values{}
+	params.Set("ingredients", strings.Join(search.Ingredients, ","))
+	if len(search.Exclude) > 0 {
+		params.Set("exclude", strings.Join(search.Exclude, ","))
+	}
+	if search.Query != "" {
+		params.Set("q", search.Query)
+	}
+	if search.Fuzzy {
+		params.Set("fuzzy", "true")
+	}
+	if search.Expand {
+		params.Set("expand", "true")
+	}
+	params.Set("mode", string(mode))
+	params.Set("max_missing", strconv.Itoa(search.MaxMissing))
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/recipes?%s", c.address, params.Encode()), nil)
+	if err != nil {
+		return Recipes{}, fmt.Errorf("creating http request: %w", err)
+	}
+	req.Header.Add("X-Api-Key", c.apiKey)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return Recipes{}, fmt.Errorf("calling http endpoint: %w", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return Recipes{}, fmt.Errorf("reading response: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return Recipes{}, fmt.Errorf(res.Status)
+	}
+
+	err = json.Unmarshal(body, &recipes)
+	if err != nil {
+		return Recipes{}, fmt.Errorf("unmarshalling response: %v", err)
+	}
+
+	return recipes, nil
+}
+
+// ListRecipes calls the `GET /recipes?page_size={page size}&page_token={page token}` endpoint, returning
+// a page of recipes and the token of the next page, which is empty on the last page
+func (c *HttpClient) ListRecipes(pageSize int, pageToken string) ([]Recipe, string, error) {
+	var page RecipePage
+	params := url.Values{}
+	params.Set("page_size", strconv.Itoa(pageSize))
+	if pageToken != "" {
+		params.Set("page_token", pageToken)
+	}
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/recipes?%s", c.address, params.Encode()), nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("creating http request: %w", err)
+	}
+	req.Header.Add("X-Api-Key", c.apiKey)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("calling http endpoint: %w", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("reading response: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf(res.Status)
+	}
+
+	err = json.Unmarshal(body, &page)
+	if err != nil {
+		return nil, "", fmt.Errorf("unmarshalling response: %v", err)
+	}
+
+	return page.Recipes, page.NextPageToken, nil
+}
+
+// SearchRecipesByName calls the `GET /recipes:search?name={name}&match={match}&page_size={page size}&page_token={page token}`
+// endpoint, returning a page of the recipes whose names start with or contain name and the token of the next page,
+// which is empty on the last page
+func (c *HttpClient) SearchRecipesByName(name string, match NameMatch, pageSize int, pageToken string) ([]Recipe, string, error) {
+	var page RecipePage
+	if match == "" {
+		match = NamePrefix
+	}
+	params := url.Values{}
+	params.Set("name", name)
+	params.Set("match", string(match))
+	params.Set("page_size", strconv.Itoa(pageSize))
+	if pageToken != "" {
+		params.Set("page_token", pageToken)
+	}
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/recipes:search?%s", c.address, params.Encode()), nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("creating http request: %w", err)
+	}
+	req.Header.Add("X-Api-Key", c.apiKey)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("calling http endpoint: %w", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("reading response: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf(res.Status)
+	}
+
+	err = json.Unmarshal(body, &page)
+	if err != nil {
+		return nil, "", fmt.Errorf("unmarshalling response: %v", err)
+	}
+
+	return page.Recipes, page.NextPageToken, nil
+}
+
+// ListIngredients calls the `GET /ingredients` endpoint, returning all ingredients which recipes use with
+// the number of recipes using each, in name order
+func (c *HttpClient) ListIngredients() ([]IngredientUsage, error) {
+	return c.getIngredients(fmt.Sprintf("%s/ingredients", c.address))
+}
+
+// CompleteIngredient calls the `GET /ingredients:complete?prefix={prefix}&limit={limit}` endpoint, returning up to
+// limit ingredients whose names start with prefix, the most used first
+func (c *HttpClient) CompleteIngredient(prefix string, limit int) ([]IngredientUsage, error) {
+	params := url.Values{}
+	params.Set("prefix", prefix)
+	params.Set("limit", strconv.Itoa(limit))
+
+	return c.getIngredients(fmt.Sprintf("%s/ingredients:complete?%s", c.address, params.Encode()))
+}
+
+// GetSimilarRecipes calls the `GET /recipes:similar?name={name}&limit={limit}` endpoint, returning up to limit
+// recipes which share ingredients with the named recipe, the most similar first
+func (c *HttpClient) GetSimilarRecipes(name string, limit int) ([]SimilarRecipe, error) {
+	var similar SimilarRecipes
+	params := url.Values{}
+	params.Set("name", name)
+	params.Set("limit", strconv.Itoa(limit))
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/recipes:similar?%s", c.address, params.Encode()), nil)
 	if err != nil {
 		return nil, fmt.Errorf("creating http request: %w", err)
 	}
@@ -124,12 +380,76 @@ func (c *HttpClient) SearchByIngredients(ingredients []string) ([]Recipe, error)
 		return nil, fmt.Errorf(res.Status)
 	}
 
-	err = json.Unmarshal(body, &recipes)
+	err = json.Unmarshal(body, &similar)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshalling response: %v", err)
+	}
+
+	return similar.Recipes, nil
+}
+
+// GetStats calls the `GET /stats` endpoint, returning statistics about all recipes
+func (c *HttpClient) GetStats() (Stats, error) {
+	var stats Stats
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/stats", c.address), nil)
+	if err != nil {
+		return Stats{}, fmt.Errorf("creating http request: %w", err)
+	}
+	req.Header.Add("X-Api-Key", c.apiKey)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return Stats{}, fmt.Errorf("calling http endpoint: %w", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return Stats{}, fmt.Errorf("reading response: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return Stats{}, fmt.Errorf(res.Status)
+	}
+
+	err = json.Unmarshal(body, &stats)
+	if err != nil {
+		return Stats{}, fmt.Errorf("unmarshalling response: %v", err)
+	}
+
+	return stats, nil
+}
+
+// getIngredients calls an endpoint which returns Ingredients
+func (c *HttpClient) getIngredients(address string) ([]IngredientUsage, error) {
+	var ingredients Ingredients
+	req, err := http.NewRequest("GET", address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating http request: %w", err)
+	}
+	req.Header.Add("X-Api-Key", c.apiKey)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("calling http endpoint: %w", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf(res.Status)
+	}
+
+	err = json.Unmarshal(body, &ingredients)
 	if err != nil {
 		return nil, fmt.Errorf("unmarshalling response: %v", err)
 	}
 
-	return recipes.Recipes, nil
+	return ingredients.Ingredients, nil
 }
 
 func (c *HttpClient) Benchmarks(duration time.Duration) {
@@ -72,7 +72,7 @@ func TestHttpClient_AddRecipe(t *testing.T) {
 		{
 			name:    "1",
 			c:       &client,
-			recipe:  Recipe{Name: "one", Ingredients: []string{"one", "two", "three"}},
+			recipe:  Recipe{Name: "one", Ingredients: []Ingredient{{Name: "one"}, {Name: "two"}, {Name: "three"}}},
 			wantErr: false,
 		},
 	}
@@ -99,7 +99,7 @@ func TestHttpClient_GetRecipe(t *testing.T) {
 		case "found":
 			rsp, _ := json.Marshal(Recipe{
 				Name:        "found",
-				Ingredients: []string{"one", "two", "three"},
+				Ingredients: []Ingredient{{Name: "one"}, {Name: "two"}, {Name: "three"}},
 			})
 			w.WriteHeader(http.StatusOK)
 			w.Write(rsp)
@@ -140,7 +140,7 @@ func TestHttpClient_GetRecipe(t *testing.T) {
 			name:    "3",
 			c:       &client,
 			rname:   "found",
-			want:    &Recipe{Name: "found", Ingredients: []string{"one", "two", "three"}},
+			want:    &Recipe{Name: "found", Ingredients: []Ingredient{{Name: "one"}, {Name: "two"}, {Name: "three"}}},
 			wantErr: nil,
 		},
 	}
@@ -162,6 +162,311 @@ func TestHttpClient_GetRecipe(t *testing.T) {
 	}
 }
 
+func TestHttpClient_LookupRecipe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fuzzy := r.URL.Query().Get("fuzzy") == "true"
+		switch strings.TrimPrefix(r.URL.Path, "/recipe/") {
+		case "blt":
+			if fuzzy {
+				w.Write([]byte(`{"name":"BLT","ingredients":["Bacon","Lettuce","Tomato"]}`))
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"recipe (blt) not found","suggestions":["BLT"]}`))
+		case "Spagbol":
+			// As the gRPC gateway of the hybrid server reports it
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code":5,"message":"recipe (Spagbol) not found","details":[{"@type":"type.googleapis.com/recipesvc.Suggestion","name":"Spagbol","suggestions":["SpagBol","Spag Bol"]}]}`))
+		case "Pizza":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	client := HttpClient{
+		client:  &http.Client{},
+		address: server.URL,
+		apiKey:  "1234",
+	}
+
+	tests := []struct {
+		name            string
+		c               *HttpClient
+		rname           string
+		fuzzy           bool
+		want            *Recipe
+		wantSuggestions []string
+		wantErr         error
+	}{
+		{
+			name:            "1",
+			c:               &client,
+			rname:           "blt",
+			want:            nil,
+			wantSuggestions: []string{"BLT"},
+			wantErr:         nil,
+		},
+		{
+			name:            "2",
+			c:               &client,
+			rname:           "blt",
+			fuzzy:           true,
+			want:            &Recipe{Name: "BLT", Ingredients: []Ingredient{{Name: "Bacon"}, {Name: "Lettuce"}, {Name: "Tomato"}}},
+			wantSuggestions: nil,
+			wantErr:         nil,
+		},
+		{
+			name:            "3",
+			c:               &client,
+			rname:           "Spagbol",
+			want:            nil,
+			wantSuggestions: []string{"SpagBol", "Spag Bol"},
+			wantErr:         nil,
+		},
+		{
+			name:            "4",
+			c:               &client,
+			rname:           "Pizza",
+			want:            nil,
+			wantSuggestions: nil,
+			wantErr:         nil,
+		},
+		{
+			name:            "5",
+			c:               &client,
+			rname:           "badgateway",
+			want:            nil,
+			wantSuggestions: nil,
+			wantErr:         fmt.Errorf("502 Bad Gateway"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotSuggestions, err := tt.c.LookupRecipe(tt.rname, tt.fuzzy)
+			if (err == nil) != (tt.wantErr == nil) || (err != nil && err.Error() != tt.wantErr.Error()) {
+				t.Errorf("HttpClient.LookupRecipe() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) || !reflect.DeepEqual(gotSuggestions, tt.wantSuggestions) {
+				t.Errorf("HttpClient.LookupRecipe() = %v, %v, want %v, %v", got, gotSuggestions, tt.want, tt.wantSuggestions)
+			}
+		})
+	}
+}
+
+func TestHttpClient_ExpandRecipe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimPrefix(r.URL.Path, "/recipe/") {
+		case "Toast":
+			if r.URL.Query().Get("expand") != "true" {
+				w.Write([]byte(`{"name":"Toast","structuredIngredients":[{"name":"Bread"},{"name":"Caprese Salad","component":true}]}`))
+				return
+			}
+			w.Write([]byte(`{"name":"Toast","structuredIngredients":[{"name":"Bread"},{"name":"Mozzarella"},{"name":"Tomato"}]}`))
+		case "Pizza":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	client := HttpClient{
+		client:  &http.Client{},
+		address: server.URL,
+		apiKey:  "1234",
+	}
+
+	tests := []struct {
+		name    string
+		c       *HttpClient
+		rname   string
+		want    *Recipe
+		wantErr error
+	}{
+		{
+			name:    "1",
+			c:       &client,
+			rname:   "Toast",
+			want:    &Recipe{Name: "Toast", Ingredients: []Ingredient{{Name: "Bread"}, {Name: "Mozzarella"}, {Name: "Tomato"}}},
+			wantErr: nil,
+		},
+		{
+			name:    "2",
+			c:       &client,
+			rname:   "Pizza",
+			want:    nil,
+			wantErr: nil,
+		},
+		{
+			name:    "3",
+			c:       &client,
+			rname:   "badgateway",
+			want:    nil,
+			wantErr: fmt.Errorf("502 Bad Gateway"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.c.ExpandRecipe(tt.rname)
+			if (err == nil) != (tt.wantErr == nil) || (err != nil && err.Error() != tt.wantErr.Error()) {
+				t.Errorf("HttpClient.ExpandRecipe() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("HttpClient.ExpandRecipe() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHttpClient_ScaleRecipe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimPrefix(r.URL.Path, "/recipe/") {
+		case "Scones":
+			query := r.URL.Query()
+			if query.Get("servings") != "16" || query.Get("units") != "imperial" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(`{"name":"Scones","structuredIngredients":[{"name":"Flour","quantity":1,"unit":"lb"}],"servings":16}`))
+		case "Pizza":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	client := HttpClient{
+		client:  &http.Client{},
+		address: server.URL,
+		apiKey:  "1234",
+	}
+
+	tests := []struct {
+		name     string
+		c        *HttpClient
+		rname    string
+		servings int
+		units    string
+		want     *Recipe
+		wantErr  error
+	}{
+		{
+			name:     "1",
+			c:        &client,
+			rname:    "Scones",
+			servings: 16,
+			units:    "imperial",
+			want:     &Recipe{Name: "Scones", Ingredients: []Ingredient{{Name: "Flour", Quantity: 1, Unit: "lb"}}, Servings: 16},
+			wantErr:  nil,
+		},
+		{
+			name:     "2",
+			c:        &client,
+			rname:    "Pizza",
+			servings: 2,
+			want:     nil,
+			wantErr:  nil,
+		},
+		{
+			name:     "3",
+			c:        &client,
+			rname:    "Scones",
+			servings: 2,
+			want:     nil,
+			wantErr:  fmt.Errorf("400 Bad Request"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.c.ScaleRecipe(tt.rname, tt.servings, tt.units)
+			if (err == nil) != (tt.wantErr == nil) || (err != nil && err.Error() != tt.wantErr.Error()) {
+				t.Errorf("HttpClient.ScaleRecipe() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("HttpClient.ScaleRecipe() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHttpClient_DeleteRecipe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, err := url.QueryUnescape(strings.TrimPrefix(r.RequestURI, "/recipe/"))
+		if err != nil || r.Method != "DELETE" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		switch name {
+		case "notfound":
+			w.WriteHeader(http.StatusNotFound)
+		case "found":
+			w.WriteHeader(http.StatusOK)
+		case "badgateway":
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	client := HttpClient{
+		client:  &http.Client{},
+		address: server.URL,
+		apiKey:  "1234",
+	}
+
+	tests := []struct {
+		name    string
+		c       *HttpClient
+		rname   string
+		want    bool
+		wantErr error
+	}{
+		{
+			name:    "1",
+			c:       &client,
+			rname:   "notfound",
+			want:    false,
+			wantErr: nil,
+		},
+		{
+			name:    "2",
+			c:       &client,
+			rname:   "badgateway",
+			want:    false,
+			wantErr: fmt.Errorf("502 Bad Gateway"),
+		},
+		{
+			name:    "3",
+			c:       &client,
+			rname:   "found",
+			want:    true,
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.c.DeleteRecipe(tt.rname)
+			if (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("HttpClient.DeleteRecipe() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil && tt.wantErr != nil && (err.Error() != tt.wantErr.Error()) {
+				t.Errorf("HttpClient.DeleteRecipe() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("HttpClient.DeleteRecipe() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestHttpClient_SearchByIngredients(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		unescaped, err := url.QueryUnescape(strings.TrimPrefix(r.RequestURI, "/recipes"))
@@ -224,8 +529,8 @@ func TestHttpClient_SearchByIngredients(t *testing.T) {
 			c:           &client,
 			ingredients: []string{"Tomato", "Ground Beef"},
 			want: []Recipe{
-				{Name: "Meatballs", Ingredients: []string{"Ground Beef", "Tomato"}},
-				{Name: "SpagBol", Ingredients: []string{"Spaghetti", "Ground Beef", "Tomato"}},
+				{Name: "Meatballs", Ingredients: []Ingredient{{Name: "Ground Beef"}, {Name: "Tomato"}}},
+				{Name: "SpagBol", Ingredients: []Ingredient{{Name: "Spaghetti"}, {Name: "Ground Beef"}, {Name: "Tomato"}}},
 			},
 			wantErr: nil,
 		},
@@ -234,7 +539,7 @@ func TestHttpClient_SearchByIngredients(t *testing.T) {
 			c:           &client,
 			ingredients: []string{"Tomato", "Ground Beef", "Spaghetti"},
 			want: []Recipe{
-				{Name: "SpagBol", Ingredients: []string{"Spaghetti", "Ground Beef", "Tomato"}},
+				{Name: "SpagBol", Ingredients: []Ingredient{{Name: "Spaghetti"}, {Name: "Ground Beef"}, {Name: "Tomato"}}},
 			},
 			wantErr: nil,
 		},
@@ -271,6 +576,481 @@ func TestHttpClient_SearchByIngredients(t *testing.T) {
 	}
 }
 
+func TestHttpClient_SearchRecipes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.URL.Path != "/recipes" || query.Get("ingredients") != "Mozzarella,Macaroni" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if query.Get("fuzzy") == "true" {
+			w.Write([]byte(`{"recipes":[],"suggestions":[{"name":"Macaroni","suggestions":["Macaroon"]}]}`))
+			return
+		}
+
+		if query.Get("expand") == "true" {
+			w.Write([]byte(`{"recipes":[{"name":"Mac & Cheese","ingredients":["Mozzarella","Macaroni"]}],"matches":[{"recipe":"Mac & Cheese","missingIngredients":[]}]}`))
+			return
+		}
+
+		switch query.Get("mode") + " " + query.Get("max_missing") + " " + query.Get("exclude") + " " + query.Get("q") {
+		case "MATCH_SUBSET 1  ":
+			w.Write([]byte(`{"recipes":[{"name":"Caprese Salad","ingredients":["Mozzarella","Tomato"]}],"matches":[{"recipe":"Caprese Salad","missingIngredients":["Tomato"]}]}`))
+		case "MATCH_ANY 0  ", "MATCH_SUBSET 1 Tomato,Basil ", "MATCH_ALL 0  Mozzarella -(Tomato OR Basil)":
+			w.Write([]byte(`{"recipes":[],"matches":[]}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	client := HttpClient{
+		client:  &http.Client{},
+		address: server.URL,
+		apiKey:  "1234",
+	}
+
+	tests := []struct {
+		name            string
+		c               *HttpClient
+		search          Search
+		want            []Recipe
+		wantMatches     []Match
+		wantSuggestions []Suggestion
+		wantErr         error
+	}{
+		{
+			name:        "1",
+			c:           &client,
+			search:      Search{Ingredients: []string{"Mozzarella", "Macaroni"}, Mode: MatchSubset, MaxMissing: 1},
+			want:        []Recipe{{Name: "Caprese Salad", Ingredients: []Ingredient{{Name: "Mozzarella"}, {Name: "Tomato"}}}},
+			wantMatches: []Match{{Recipe: "Caprese Salad", MissingIngredients: []string{"Tomato"}}},
+			wantErr:     nil,
+		},
+		{
+			name:        "2",
+			c:           &client,
+			search:      Search{Ingredients: []string{"Mozzarella", "Macaroni"}, Mode: MatchAny, MaxMissing: 0},
+			want:        []Recipe{},
+			wantMatches: []Match{},
+			wantErr:     nil,
+		},
+		{
+			name:        "3",
+			c:           &client,
+			search:      Search{Ingredients: []string{"Mozzarella", "Macaroni"}, Exclude: []string{"Tomato", "Basil"}, Mode: MatchSubset, MaxMissing: 1},
+			want:        []Recipe{},
+			wantMatches: []Match{},
+			wantErr:     nil,
+		},
+		{
+			name:        "4",
+			c:           &client,
+			search:      Search{Ingredients: []string{"Mozzarella", "Macaroni"}, Query: "Mozzarella -(Tomato OR Basil)"},
+			want:        []Recipe{},
+			wantMatches: []Match{},
+			wantErr:     nil,
+		},
+		{
+			name:        "5",
+			c:           &client,
+			search:      Search{Ingredients: []string{"Mozzarella", "Macaroni"}},
+			want:        nil,
+			wantMatches: nil,
+			wantErr:     fmt.Errorf("400 Bad Request"),
+		},
+		{
+			name:            "6",
+			c:               &client,
+			search:          Search{Ingredients: []string{"Mozzarella", "Macaroni"}, Fuzzy: true},
+			want:            []Recipe{},
+			wantMatches:     nil,
+			wantSuggestions: []Suggestion{{Name: "Macaroni", Suggestions: []string{"Macaroon"}}},
+			wantErr:         nil,
+		},
+		{
+			name:        "7",
+			c:           &client,
+			search:      Search{Ingredients: []string{"Mozzarella", "Macaroni"}, Expand: true},
+			want:        []Recipe{{Name: "Mac & Cheese", Ingredients: []Ingredient{{Name: "Mozzarella"}, {Name: "Macaroni"}}}},
+			wantMatches: []Match{{Recipe: "Mac & Cheese", MissingIngredients: []string{}}},
+			wantErr:     nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.c.SearchRecipes(tt.search)
+			if (err == nil) != (tt.wantErr == nil) || (err != nil && err.Error() != tt.wantErr.Error()) {
+				t.Errorf("HttpClient.SearchRecipes() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got.Recipes, tt.want) || !reflect.DeepEqual(got.Matches, tt.wantMatches) || !reflect.DeepEqual(got.Suggestions, tt.wantSuggestions) {
+				t.Errorf("HttpClient.SearchRecipes() = %v, %v, %v, want %v, %v, %v", got.Recipes, got.Matches, got.Suggestions, tt.want, tt.wantMatches, tt.wantSuggestions)
+			}
+		})
+	}
+}
+
+func TestHttpClient_ListRecipes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/recipes" || r.URL.Query().Get("page_size") != "1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		switch r.URL.Query().Get("page_token") {
+		case "":
+			w.Write([]byte(`{"recipes":[{"name":"BLT","ingredients":["Tomato","Bacon","Lettuce"]}],"nextPageToken":"next"}`))
+		case "next":
+			w.Write([]byte(`{"recipes":[{"name":"Meatballs","ingredients":["Ground Beef","Tomato"]}]}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	client := HttpClient{
+		client:  &http.Client{},
+		address: server.URL,
+		apiKey:  "1234",
+	}
+
+	tests := []struct {
+		name      string
+		c         *HttpClient
+		pageSize  int
+		pageToken string
+		want      []Recipe
+		wantToken string
+		wantErr   error
+	}{
+		{
+			name:      "1",
+			c:         &client,
+			pageSize:  1,
+			pageToken: "",
+			want:      []Recipe{{Name: "BLT", Ingredients: []Ingredient{{Name: "Tomato"}, {Name: "Bacon"}, {Name: "Lettuce"}}}},
+			wantToken: "next",
+			wantErr:   nil,
+		},
+		{
+			name:      "2",
+			c:         &client,
+			pageSize:  1,
+			pageToken: "next",
+			want:      []Recipe{{Name: "Meatballs", Ingredients: []Ingredient{{Name: "Ground Beef"}, {Name: "Tomato"}}}},
+			wantToken: "",
+			wantErr:   nil,
+		},
+		{
+			name:      "3",
+			c:         &client,
+			pageSize:  1,
+			pageToken: "invalid",
+			want:      nil,
+			wantToken: "",
+			wantErr:   fmt.Errorf("400 Bad Request"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotToken, err := tt.c.ListRecipes(tt.pageSize, tt.pageToken)
+			if (err == nil) != (tt.wantErr == nil) || (err != nil && err.Error() != tt.wantErr.Error()) {
+				t.Errorf("HttpClient.ListRecipes() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) || gotToken != tt.wantToken {
+				t.Errorf("HttpClient.ListRecipes() = %v, %v, want %v, %v", got, gotToken, tt.want, tt.wantToken)
+			}
+		})
+	}
+}
+func TestHttpClient_SearchRecipesByName(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.URL.Path != "/recipes:search" || query.Get("name") != "salad" || query.Get("page_size") != "1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		switch query.Get("match") + " " + query.Get("page_token") {
+		case "NAME_SUBSTRING ":
+			w.Write([]byte(`{"recipes":[{"name":"Caprese Salad","ingredients":["Mozzarella","Tomato"]}],"nextPageToken":"next"}`))
+		case "NAME_SUBSTRING next":
+			w.Write([]byte(`{"recipes":[{"name":"Greek Salad","ingredients":["Feta","Tomato"]}]}`))
+		case "NAME_PREFIX ":
+			w.Write([]byte(`{"recipes":[]}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	client := HttpClient{
+		client:  &http.Client{},
+		address: server.URL,
+		apiKey:  "1234",
+	}
+
+	tests := []struct {
+		name      string
+		c         *HttpClient
+		search    string
+		match     NameMatch
+		pageToken string
+		want      []Recipe
+		wantToken string
+		wantErr   error
+	}{
+		{
+			name:      "1",
+			c:         &client,
+			search:    "salad",
+			match:     NameSubstring,
+			pageToken: "",
+			want:      []Recipe{{Name: "Caprese Salad", Ingredients: []Ingredient{{Name: "Mozzarella"}, {Name: "Tomato"}}}},
+			wantToken: "next",
+			wantErr:   nil,
+		},
+		{
+			name:      "2",
+			c:         &client,
+			search:    "salad",
+			match:     NameSubstring,
+			pageToken: "next",
+			want:      []Recipe{{Name: "Greek Salad", Ingredients: []Ingredient{{Name: "Feta"}, {Name: "Tomato"}}}},
+			wantToken: "",
+			wantErr:   nil,
+		},
+		{
+			name:      "3",
+			c:         &client,
+			search:    "salad",
+			match:     "",
+			pageToken: "",
+			want:      []Recipe{},
+			wantToken: "",
+			wantErr:   nil,
+		},
+		{
+			name:      "4",
+			c:         &client,
+			search:    "",
+			match:     NamePrefix,
+			pageToken: "",
+			want:      nil,
+			wantToken: "",
+			wantErr:   fmt.Errorf("400 Bad Request"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotToken, err := tt.c.SearchRecipesByName(tt.search, tt.match, 1, tt.pageToken)
+			if (err == nil) != (tt.wantErr == nil) || (err != nil && err.Error() != tt.wantErr.Error()) {
+				t.Errorf("HttpClient.SearchRecipesByName() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) || gotToken != tt.wantToken {
+				t.Errorf("HttpClient.SearchRecipesByName() = %v, %v, want %v, %v", got, gotToken, tt.want, tt.wantToken)
+			}
+		})
+	}
+}
+
+func TestHttpClient_ListIngredients(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ingredients" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"ingredients":[{"name":"Mozzarella","recipes":2},{"name":"Tomato","recipes":5}]}`))
+	}))
+	defer server.Close()
+
+	client := HttpClient{
+		client:  &http.Client{},
+		address: server.URL,
+		apiKey:  "1234",
+	}
+
+	got, err := client.ListIngredients()
+	want := []IngredientUsage{{Name: "Mozzarella", Recipes: 2}, {Name: "Tomato", Recipes: 5}}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("HttpClient.ListIngredients() = %v, %v, want %v", got, err, want)
+	}
+}
+
+func TestHttpClient_GetSimilarRecipes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.URL.Path != "/recipes:similar" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		switch query.Get("name") + " " + query.Get("limit") {
+		case "SpagBol 5":
+			w.Write([]byte(`{"recipes":[{"name":"Meatballs","similarity":0.6666666666666666,"sharedIngredients":["Ground Beef","Tomato"]}]}`))
+		case "Cheese Fondue 5":
+			w.Write([]byte(`{"recipes":[]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := HttpClient{
+		client:  &http.Client{},
+		address: server.URL,
+		apiKey:  "1234",
+	}
+
+	tests := []struct {
+		name    string
+		c       *HttpClient
+		recipe  string
+		want    []SimilarRecipe
+		wantErr bool
+	}{
+		{
+			name:    "1",
+			c:       &client,
+			recipe:  "SpagBol",
+			want:    []SimilarRecipe{{Name: "Meatballs", Similarity: 2.0 / 3, SharedIngredients: []string{"Ground Beef", "Tomato"}}},
+			wantErr: false,
+		},
+		{
+			name:    "2",
+			c:       &client,
+			recipe:  "Cheese Fondue",
+			want:    []SimilarRecipe{},
+			wantErr: false,
+		},
+		{
+			name:    "3",
+			c:       &client,
+			recipe:  "Pizza",
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.c.GetSimilarRecipes(tt.recipe, 5)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("HttpClient.GetSimilarRecipes() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("HttpClient.GetSimilarRecipes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHttpClient_GetStats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/stats" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"recipes":2,"ingredients":2,"mostUsed":[{"name":"Tomato","recipes":2}],"leastUsed":[{"name":"Basil","recipes":1}],"averageIngredients":1.5,"isolatedRecipes":[]}`))
+	}))
+	defer server.Close()
+
+	client := HttpClient{
+		client:  &http.Client{},
+		address: server.URL,
+		apiKey:  "1234",
+	}
+
+	got, err := client.GetStats()
+	want := Stats{
+		Recipes:            2,
+		Ingredients:        2,
+		MostUsed:           []IngredientUsage{{Name: "Tomato", Recipes: 2}},
+		LeastUsed:          []IngredientUsage{{Name: "Basil", Recipes: 1}},
+		AverageIngredients: 1.5,
+		IsolatedRecipes:    []string{},
+	}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("HttpClient.GetStats() = %v, %v, want %v", got, err, want)
+	}
+}
+
+func TestHttpClient_CompleteIngredient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.URL.Path != "/ingredients:complete" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		switch query.Get("prefix") + " " + query.Get("limit") {
+		case "ground b 10":
+			w.Write([]byte(`{"ingredients":[{"name":"Ground Beef","recipes":2}]}`))
+		case "x 10":
+			w.Write([]byte(`{"ingredients":[]}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	client := HttpClient{
+		client:  &http.Client{},
+		address: server.URL,
+		apiKey:  "1234",
+	}
+
+	tests := []struct {
+		name    string
+		c       *HttpClient
+		prefix  string
+		limit   int
+		want    []IngredientUsage
+		wantErr error
+	}{
+		{
+			name:    "1",
+			c:       &client,
+			prefix:  "ground b",
+			limit:   10,
+			want:    []IngredientUsage{{Name: "Ground Beef", Recipes: 2}},
+			wantErr: nil,
+		},
+		{
+			name:    "2",
+			c:       &client,
+			prefix:  "x",
+			limit:   10,
+			want:    []IngredientUsage{},
+			wantErr: nil,
+		},
+		{
+			name:    "3",
+			c:       &client,
+			prefix:  "x",
+			limit:   -1,
+			want:    nil,
+			wantErr: fmt.Errorf("400 Bad Request"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.c.CompleteIngredient(tt.prefix, tt.limit)
+			if (err == nil) != (tt.wantErr == nil) || (err != nil && err.Error() != tt.wantErr.Error()) {
+				t.Errorf("HttpClient.CompleteIngredient() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("HttpClient.CompleteIngredient() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestHttpClient_Benchmarks(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
 	defer server.Close()
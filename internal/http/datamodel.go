@@ -1,29 +1,377 @@
 package http
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"go-incubator/internal/units"
+	"math"
+	"strconv"
+	"strings"
+)
 
 type Recipe struct {
-	Name        string   `json:"name"`
-	Ingredients []string `json:"ingredients"`
+	Name         string
+	Description  string
+	Ingredients  []Ingredient
+	Instructions []string // the steps of the method, in order
+	Servings     int
+	PrepMinutes  int
+	CookMinutes  int
+	Source       string // where the recipe comes from, such as a book or a URL
+}
+
+// Ingredient is a single line of the ingredient list of a Recipe. Only Name is required,
+// and a Quantity of 0 means that none was specified.
+type Ingredient struct {
+	Name     string  `json:"name"`
+	Quantity float64 `json:"quantity,omitempty"`
+	Unit     string  `json:"unit,omitempty"`
+	Note     string  `json:"note,omitempty"`
+	// Component is set when Name is another recipe, such as "Pizza Dough"
+	Component bool `json:"component,omitempty"`
 }
 
 type Recipes struct {
-	Recipes []Recipe `json:"recipes"`
+	Recipes     []Recipe     `json:"recipes"`
+	Matches     []Match      `json:"matches,omitempty"`
+	Suggestions []Suggestion `json:"suggestions,omitempty"`
+}
+
+// Suggestion lists the names which are close to a name that was not found, closest first, using the
+// same field names as the gRPC gateway
+type Suggestion struct {
+	Name        string   `json:"name"`
+	Suggestions []string `json:"suggestions"`
+}
+
+// NotFound is the body of the response when there is no recipe with the requested name, which
+// suggests the names of similar recipes
+type NotFound struct {
+	Error       string   `json:"error"`
+	Suggestions []string `json:"suggestions"`
+}
+
+// Match explains a single result of a search, using the same field names as the gRPC gateway.
+// Score is the share of all the ingredients of the recipe and the search which both have in common.
+type Match struct {
+	Recipe             string   `json:"recipe"`
+	MissingIngredients []string `json:"missingIngredients"`
+	MatchedIngredients []string `json:"matchedIngredients"`
+	Matched            int      `json:"matched"`
+	Missing            int      `json:"missing"`
+	Extra              int      `json:"extra"`
+	Score              float64  `json:"score"`
+}
+
+// String explains why the recipe matched, e.g. "uses Tomato, Bacon; also needs Lettuce; score 0.67"
+func (m Match) String() string {
+	parts := []string{}
+	if len(m.MatchedIngredients) > 0 {
+		parts = append(parts, "uses "+strings.Join(m.MatchedIngredients, ", "))
+	}
+	if len(m.MissingIngredients) > 0 {
+		parts = append(parts, "also needs "+strings.Join(m.MissingIngredients, ", "))
+	}
+	if m.Extra > 0 {
+		parts = append(parts, fmt.Sprintf("%d of your ingredients unused", m.Extra))
+	}
+	parts = append(parts, "score "+strconv.FormatFloat(m.Score, 'f', 2, 64))
+
+	return strings.Join(parts, "; ")
+}
+
+// MatchMode selects which recipes a search finds. The values are the names the gRPC gateway uses.
+type MatchMode string
+
+const (
+	MatchAll    MatchMode = "MATCH_ALL"    // recipes which use all of the ingredients
+	MatchAny    MatchMode = "MATCH_ANY"    // recipes which use at least one of the ingredients
+	MatchSubset MatchMode = "MATCH_SUBSET" // recipes which can be made from the ingredients
+)
+
+// NameMatch selects how SearchRecipesByName matches the names of recipes. The values are the names the gRPC gateway uses.
+type NameMatch string
+
+const (
+	NamePrefix    NameMatch = "NAME_PREFIX"    // recipes whose names start with the text
+	NameSubstring NameMatch = "NAME_SUBSTRING" // recipes whose names contain the text anywhere
+)
+
+// Search describes which recipes SearchRecipes finds
+type Search struct {
+	Ingredients []string
+	Exclude     []string  // ingredients which none of the recipes found may use
+	Mode        MatchMode // defaults to MatchAll
+	MaxMissing  int       // MatchSubset only
+	Query       string    // boolean ingredient query such as `Tomato AND (Basil OR Oregano) -Garlic`
+	Fuzzy       bool      // search for the closest known ingredient instead of each unknown one
+	Expand      bool      // also match the kinds of each ingredient in the taxonomy, such as Mozzarella for Cheese
+}
+
+// RecipePage is a single page of the list of all recipes, using the same field names as the gRPC gateway
+type RecipePage struct {
+	Recipes       []Recipe `json:"recipes"`
+	NextPageToken string   `json:"nextPageToken,omitempty"`
+}
+
+// IngredientUsage is an ingredient with the number of recipes which use it, using the same field names
+// as the gRPC gateway
+type IngredientUsage struct {
+	Name    string `json:"name"`
+	Recipes int    `json:"recipes"`
 }
 
+// Ingredients is a list of ingredients, such as those which complete the start of a name
+type Ingredients struct {
+	Ingredients []IngredientUsage `json:"ingredients"`
+}
+
+// SimilarRecipe is a recipe which shares ingredients with another, using the same field names as the gRPC gateway.
+// Similarity is the share of all the ingredients of both recipes which they have in common.
+type SimilarRecipe struct {
+	Name              string   `json:"name"`
+	Similarity        float64  `json:"similarity"`
+	SharedIngredients []string `json:"sharedIngredients"`
+}
+
+// SimilarRecipes is a list of similar recipes, most similar first
+type SimilarRecipes struct {
+	Recipes []SimilarRecipe `json:"recipes"`
+}
+
+// Stats summarises all recipes, using the same field names as the gRPC gateway
+type Stats struct {
+	Recipes            int               `json:"recipes"`
+	Ingredients        int               `json:"ingredients"`
+	MostUsed           []IngredientUsage `json:"mostUsed"`
+	LeastUsed          []IngredientUsage `json:"leastUsed"`
+	AverageIngredients float64           `json:"averageIngredients"`
+	IsolatedRecipes    []string          `json:"isolatedRecipes"`
+}
+
+// Kind records that an ingredient is a kind of a broader one, its parent, using the same field names
+// as the gRPC gateway
+type Kind struct {
+	Ingredient string `json:"ingredient"`
+	Parent     string `json:"parent"`
+}
+
+// Taxonomy is the hierarchy of ingredients which expanded searches use, in order of ingredient
+type Taxonomy struct {
+	Kinds []Kind `json:"kinds"`
+}
+
+// recipeJSON is the wire format of a Recipe, which uses the same field names as the gRPC gateway.
+// Ingredients holds just the names, so that clients which predate structured ingredients keep working.
+type recipeJSON struct {
+	Name                  string       `json:"name"`
+	Ingredients           []string     `json:"ingredients"`
+	StructuredIngredients []Ingredient `json:"structuredIngredients,omitempty"`
+	Description           string       `json:"description,omitempty"`
+	Instructions          []string     `json:"instructions,omitempty"`
+	Servings              int          `json:"servings,omitempty"`
+	PrepMinutes           int          `json:"prepMinutes,omitempty"`
+	CookMinutes           int          `json:"cookMinutes,omitempty"`
+	Source                string       `json:"source,omitempty"`
+}
+
+func (r Recipe) MarshalJSON() ([]byte, error) {
+	return json.Marshal(recipeJSON{
+		Name:                  r.Name,
+		Ingredients:           r.IngredientNames(),
+		StructuredIngredients: r.Ingredients,
+		Description:           r.Description,
+		Instructions:          r.Instructions,
+		Servings:              r.Servings,
+		PrepMinutes:           r.PrepMinutes,
+		CookMinutes:           r.CookMinutes,
+		Source:                r.Source,
+	})
+}
+
+// UnmarshalJSON reads the structured ingredients if there are any, or else the ingredient names
+func (r *Recipe) UnmarshalJSON(data []byte) error {
+	var rj recipeJSON
+	if err := json.Unmarshal(data, &rj); err != nil {
+		return err
+	}
+
+	r.Name = rj.Name
+	r.Description = rj.Description
+	r.Instructions = rj.Instructions
+	r.Servings = rj.Servings
+	r.PrepMinutes = rj.PrepMinutes
+	r.CookMinutes = rj.CookMinutes
+	r.Source = rj.Source
+	r.Ingredients = rj.StructuredIngredients
+	if len(rj.StructuredIngredients) == 0 && rj.Ingredients != nil {
+		r.Ingredients = make([]Ingredient, 0, len(rj.Ingredients))
+		for _, name := range rj.Ingredients {
+			r.Ingredients = append(r.Ingredients, Ingredient{Name: name})
+		}
+	}
+
+	return nil
+}
+
+// String renders the Recipe as the name followed by the description, the servings and timings,
+// the ingredients, the numbered steps of the method and the source, leaving out anything not set
 func (r Recipe) String() string {
 	rsp := r.Name
+	if r.Description != "" {
+		rsp = fmt.Sprintf("%s\n%s", rsp, r.Description)
+	}
+
+	var details []string
+	if r.Servings > 0 {
+		details = append(details, fmt.Sprintf("serves %d", r.Servings))
+	}
+	if r.PrepMinutes > 0 {
+		details = append(details, fmt.Sprintf("prep %d min", r.PrepMinutes))
+	}
+	if r.CookMinutes > 0 {
+		details = append(details, fmt.Sprintf("cook %d min", r.CookMinutes))
+	}
+	if len(details) > 0 {
+		line := strings.Join(details, ", ")
+		rsp = fmt.Sprintf("%s\n%s%s", rsp, strings.ToUpper(line[:1]), line[1:])
+	}
+
 	for _, v := range r.Ingredients {
 		rsp = fmt.Sprintf("%s\n  - %s", rsp, v)
 	}
+	for i, v := range r.Instructions {
+		rsp = fmt.Sprintf("%s\n  %d. %s", rsp, i+1, v)
+	}
+	if r.Source != "" {
+		rsp = fmt.Sprintf("%s\nSource: %s", rsp, r.Source)
+	}
+
+	return rsp
+}
+
+// String renders the ingredient the way a recipe would list it, e.g. "2 cups flour, sifted", or
+// "1 Pizza Dough (recipe)" for a component
+func (i Ingredient) String() string {
+	var parts []string
+	if i.Quantity != 0 {
+		parts = append(parts, strconv.FormatFloat(i.Quantity, 'f', -1, 64))
+	}
+	if i.Unit != "" {
+		parts = append(parts, i.Unit)
+	}
+	parts = append(parts, i.Name)
+	if i.Component {
+		parts = append(parts, "(recipe)")
+	}
+
+	rsp := strings.Join(parts, " ")
+	if i.Note != "" {
+		rsp = fmt.Sprintf("%s, %s", rsp, i.Note)
+	}
 
 	return rsp
 }
 
+// unitWords are the units that ParseIngredient recognises after a quantity, besides the units of
+// measurement which units.Lookup knows
+var unitWords = map[string]bool{
+	"pinch": true, "can": true, "cans": true, "clove": true, "cloves": true,
+	"slice": true, "slices": true, "rasher": true, "rashers": true,
+}
+
+// ParseIngredient reads an ingredient line such as "2 cups flour, sifted", "1 1/2 tsp salt",
+// "3 eggs" or just "Tomato". The quantity and unit are optional, and everything after the
+// first comma is the note. A name starting with "@", as in "1 @Pizza Dough", is another recipe.
+func ParseIngredient(line string) Ingredient {
+	ingredient := Ingredient{}
+
+	if i := strings.Index(line, ","); i >= 0 {
+		ingredient.Note = strings.TrimSpace(line[i+1:])
+		line = line[:i]
+	}
+
+	words := strings.Fields(line)
+	for len(words) > 1 {
+		q, ok := parseQuantity(words[0])
+		if !ok {
+			break
+		}
+		ingredient.Quantity += q
+		words = words[1:]
+	}
+	if ingredient.Quantity != 0 && len(words) > 1 && isUnit(words[0]) {
+		ingredient.Unit = words[0]
+		words = words[1:]
+	}
+	ingredient.Name = strings.Join(words, " ")
+	if name := strings.TrimSpace(strings.TrimPrefix(ingredient.Name, "@")); name != ingredient.Name && name != "" {
+		ingredient.Name = name
+		ingredient.Component = true
+	}
+
+	return ingredient
+}
+
+// isUnit returns true if the word is a unit which ParseIngredient recognises
+func isUnit(word string) bool {
+	_, ok := units.Lookup(word)
+
+	return ok || unitWords[strings.ToLower(word)]
+}
+
+// parseQuantity reads a positive number, which may be a fraction such as "1/2"
+func parseQuantity(s string) (float64, bool) {
+	num, den, fraction := strings.Cut(s, "/")
+	q, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, false
+	}
+	if fraction {
+		d, err := strconv.ParseFloat(den, 64)
+		if err != nil || d <= 0 {
+			return 0, false
+		}
+		q /= d
+	}
+
+	if q <= 0 || math.IsInf(q, 0) || math.IsNaN(q) {
+		return 0, false
+	}
+
+	return q, true
+}
+
+// IngredientNames returns the names of the ingredients of the Recipe, in order
+func (r *Recipe) IngredientNames() []string {
+	if r.Ingredients == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(r.Ingredients))
+	for _, v := range r.Ingredients {
+		names = append(names, v.Name)
+	}
+
+	return names
+}
+
+// Components returns the names of the other recipes which the Recipe uses, in order
+func (r *Recipe) Components() []string {
+	var names []string
+	for _, v := range r.Ingredients {
+		if v.Component {
+			names = append(names, v.Name)
+		}
+	}
+
+	return names
+}
+
 // UsesIngredient returns true if the Recipe uses the specified ingredient
 func (r *Recipe) UsesIngredient(ingredient string) bool {
 	for _, v := range r.Ingredients {
-		if v == ingredient {
+		if v.Name == ingredient {
 			return true
 		}
 	}
@@ -42,9 +390,9 @@ func (r *Recipe) UsesIngredients(ingredients []string) bool {
 	return true
 }
 
-// AddIngredient adds an ingredient to the Recipe only if it isn't already listed
-func (r *Recipe) AddIngredient(ingredient string) {
-	if !r.UsesIngredient(ingredient) {
+// AddIngredient adds an ingredient to the Recipe only if no ingredient of the same name is already listed
+func (r *Recipe) AddIngredient(ingredient Ingredient) {
+	if !r.UsesIngredient(ingredient.Name) {
 		r.Ingredients = append(r.Ingredients, ingredient)
 	}
 }
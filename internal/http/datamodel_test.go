@@ -1,13 +1,15 @@
 package http
 
 import (
+	"encoding/json"
+	"reflect"
 	"testing"
 )
 
 func TestUsesIngredient(t *testing.T) {
 	recipe := Recipe{
 		Name:        "Test Name",
-		Ingredients: []string{"one", "two", "three"},
+		Ingredients: []Ingredient{{Name: "one"}, {Name: "two"}, {Name: "three"}},
 	}
 
 	tests := []struct {
@@ -32,7 +34,7 @@ func TestUsesIngredient(t *testing.T) {
 func TestUsesIngredients(t *testing.T) {
 	recipe := Recipe{
 		Name:        "Test Name",
-		Ingredients: []string{"one", "two", "three"},
+		Ingredients: []Ingredient{{Name: "one"}, {Name: "two"}, {Name: "three"}},
 	}
 
 	tests := []struct {
@@ -63,25 +65,25 @@ func TestRecipe_AddIngredient(t *testing.T) {
 	tests := []struct {
 		name       string
 		r          *Recipe
-		ingredient string
+		ingredient Ingredient
 		want       args
 	}{
 		{
 			name:       "1",
-			r:          &Recipe{Name: "Test1", Ingredients: []string{}},
-			ingredient: "one",
+			r:          &Recipe{Name: "Test1", Ingredients: []Ingredient{}},
+			ingredient: Ingredient{Name: "one"},
 			want:       args{len: 1, contains: true},
 		},
 		{
 			name:       "2",
-			r:          &Recipe{Name: "Test2", Ingredients: []string{"one"}},
-			ingredient: "one",
+			r:          &Recipe{Name: "Test2", Ingredients: []Ingredient{{Name: "one"}}},
+			ingredient: Ingredient{Name: "one"},
 			want:       args{len: 1, contains: true},
 		},
 		{
 			name:       "3",
-			r:          &Recipe{Name: "Test3", Ingredients: []string{"one"}},
-			ingredient: "two",
+			r:          &Recipe{Name: "Test3", Ingredients: []Ingredient{{Name: "one"}}},
+			ingredient: Ingredient{Name: "two", Quantity: 2, Unit: "cups"},
 			want:       args{len: 2, contains: true},
 		},
 	}
@@ -90,7 +92,7 @@ func TestRecipe_AddIngredient(t *testing.T) {
 			tt.r.AddIngredient(tt.ingredient)
 			got := args{
 				len:      len(tt.r.Ingredients),
-				contains: tt.r.UsesIngredient(tt.ingredient),
+				contains: tt.r.UsesIngredient(tt.ingredient.Name),
 			}
 			if got != tt.want {
 				t.Errorf("AddIngredient() = %v, want %v", got, tt.want)
@@ -107,12 +109,12 @@ func TestRecipe_String(t *testing.T) {
 	}{
 		{
 			name: "1",
-			r:    Recipe{Name: "Test Recipe 1", Ingredients: []string{"One", "Two"}},
+			r:    Recipe{Name: "Test Recipe 1", Ingredients: []Ingredient{{Name: "One"}, {Name: "Two"}}},
 			want: "Test Recipe 1\n  - One\n  - Two",
 		},
 		{
 			name: "2",
-			r:    Recipe{Ingredients: []string{"One", "Two"}},
+			r:    Recipe{Ingredients: []Ingredient{{Name: "One"}, {Name: "Two"}}},
 			want: "\n  - One\n  - Two",
 		},
 		{
@@ -125,6 +127,35 @@ func TestRecipe_String(t *testing.T) {
 			r:    Recipe{},
 			want: "",
 		},
+		{
+			name: "5",
+			r:    Recipe{Name: "Pancakes", Ingredients: []Ingredient{{Name: "flour", Quantity: 1.5, Unit: "cups", Note: "sifted"}, {Name: "eggs", Quantity: 2}, {Name: "salt", Note: "a pinch"}}},
+			want: "Pancakes\n  - 1.5 cups flour, sifted\n  - 2 eggs\n  - salt, a pinch",
+		},
+		{
+			name: "6",
+			r: Recipe{
+				Name:         "Pancakes",
+				Description:  "Thin and crispy",
+				Ingredients:  []Ingredient{{Name: "flour"}, {Name: "eggs", Quantity: 2}},
+				Instructions: []string{"Whisk.", "Fry."},
+				Servings:     4,
+				PrepMinutes:  5,
+				CookMinutes:  20,
+				Source:       "Grandma",
+			},
+			want: "Pancakes\nThin and crispy\nServes 4, prep 5 min, cook 20 min\n  - flour\n  - 2 eggs\n  1. Whisk.\n  2. Fry.\nSource: Grandma",
+		},
+		{
+			name: "7",
+			r:    Recipe{Name: "Toast", CookMinutes: 3},
+			want: "Toast\nCook 3 min",
+		},
+		{
+			name: "8",
+			r:    Recipe{Name: "Pizza", Ingredients: []Ingredient{{Name: "Pizza Dough", Quantity: 1, Component: true}, {Name: "Mozzarella"}}},
+			want: "Pizza\n  - 1 Pizza Dough (recipe)\n  - Mozzarella",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -134,3 +165,144 @@ func TestRecipe_String(t *testing.T) {
 		})
 	}
 }
+
+func TestMatch_String(t *testing.T) {
+	tests := []struct {
+		name string
+		m    Match
+		want string
+	}{
+		{
+			name: "1",
+			m:    Match{Recipe: "BLT", MatchedIngredients: []string{"Tomato", "Bacon"}, MissingIngredients: []string{"Lettuce"}, Matched: 2, Missing: 1, Score: 2.0 / 3},
+			want: "uses Tomato, Bacon; also needs Lettuce; score 0.67",
+		},
+		{
+			name: "2",
+			m:    Match{Recipe: "Caprese Salad", MatchedIngredients: []string{"Mozzarella", "Tomato"}, MissingIngredients: []string{}, Matched: 2, Extra: 1, Score: 2.0 / 3},
+			want: "uses Mozzarella, Tomato; 1 of your ingredients unused; score 0.67",
+		},
+		{
+			name: "3",
+			m:    Match{Recipe: "Water"},
+			want: "score 0.00",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.m.String(); got != tt.want {
+				t.Errorf("Match.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseIngredient(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want Ingredient
+	}{
+		{name: "1", line: "Tomato", want: Ingredient{Name: "Tomato"}},
+		{name: "2", line: "Ground Beef", want: Ingredient{Name: "Ground Beef"}},
+		{name: "3", line: "2 cups flour, sifted", want: Ingredient{Name: "flour", Quantity: 2, Unit: "cups", Note: "sifted"}},
+		{name: "4", line: "1 1/2 tsp salt", want: Ingredient{Name: "salt", Quantity: 1.5, Unit: "tsp"}},
+		{name: "5", line: "3 eggs", want: Ingredient{Name: "eggs", Quantity: 3}},
+		{name: "6", line: " 400 g Ground Beef , lean ", want: Ingredient{Name: "Ground Beef", Quantity: 400, Unit: "g", Note: "lean"}},
+		{name: "7", line: "cups", want: Ingredient{Name: "cups"}},
+		{name: "8", line: "2", want: Ingredient{Name: "2"}},
+		{name: "9", line: "1/0 cups flour", want: Ingredient{Name: "1/0 cups flour"}},
+		{name: "10", line: "salt, to taste", want: Ingredient{Name: "salt", Note: "to taste"}},
+		{name: "11", line: "1 @Pizza Dough, rolled thin", want: Ingredient{Name: "Pizza Dough", Quantity: 1, Note: "rolled thin", Component: true}},
+		{name: "12", line: "@", want: Ingredient{Name: "@"}},
+		{name: "13", line: "2 Tablespoons sugar", want: Ingredient{Name: "sugar", Quantity: 2, Unit: "Tablespoons"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseIngredient(tt.line); got != tt.want {
+				t.Errorf("ParseIngredient() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecipe_JSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		r        Recipe
+		wantJSON string
+	}{
+		{
+			name:     "1",
+			r:        Recipe{Name: "BLT", Ingredients: []Ingredient{{Name: "Tomato"}, {Name: "Bacon", Quantity: 4, Unit: "rashers"}}},
+			wantJSON: `{"name":"BLT","ingredients":["Tomato","Bacon"],"structuredIngredients":[{"name":"Tomato"},{"name":"Bacon","quantity":4,"unit":"rashers"}]}`,
+		},
+		{
+			name:     "2",
+			r:        Recipe{Name: "Water"},
+			wantJSON: `{"name":"Water","ingredients":null}`,
+		},
+		{
+			name:     "3",
+			r:        Recipe{Name: "Toast", Ingredients: []Ingredient{{Name: "Bread"}}, Instructions: []string{"Toast it."}, Servings: 1, CookMinutes: 3, Source: "me"},
+			wantJSON: `{"name":"Toast","ingredients":["Bread"],"structuredIngredients":[{"name":"Bread"}],"instructions":["Toast it."],"servings":1,"cookMinutes":3,"source":"me"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.r)
+			if err != nil || string(got) != tt.wantJSON {
+				t.Errorf("json.Marshal() = %s, %v, want %s", got, err, tt.wantJSON)
+				return
+			}
+
+			var back Recipe
+			if err := json.Unmarshal(got, &back); err != nil || !reflect.DeepEqual(back, tt.r) {
+				t.Errorf("json.Unmarshal() = %+v, %v, want %+v", back, err, tt.r)
+			}
+		})
+	}
+}
+
+func TestRecipe_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    Recipe
+		wantErr bool
+	}{
+		{
+			name: "1",
+			data: `{"name":"BLT","ingredients":["Tomato","Bacon"]}`,
+			want: Recipe{Name: "BLT", Ingredients: []Ingredient{{Name: "Tomato"}, {Name: "Bacon"}}},
+		},
+		{
+			name: "2",
+			data: `{"name":"BLT","ingredients":["Tomato"],"structuredIngredients":[{"name":"Tomato","quantity":1,"note":"sliced"}]}`,
+			want: Recipe{Name: "BLT", Ingredients: []Ingredient{{Name: "Tomato", Quantity: 1, Note: "sliced"}}},
+		},
+		{
+			name: "3",
+			data: `{"name":"BLT","structuredIngredients":[{"name":"Bacon","quantity":4,"unit":"rashers"}]}`,
+			want: Recipe{Name: "BLT", Ingredients: []Ingredient{{Name: "Bacon", Quantity: 4, Unit: "rashers"}}},
+		},
+		{
+			name:    "4",
+			data:    `{"name":"BLT","ingredients":"Tomato"}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Recipe
+			err := json.Unmarshal([]byte(tt.data), &got)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Recipe.UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Recipe.UnmarshalJSON() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
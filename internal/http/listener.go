@@ -3,29 +3,35 @@ package http
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"go-incubator/internal/persistence"
+	"go-incubator/internal/units"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 )
 
 type HttpServer struct {
-	server *http.Server
-	port   int
-	apiKey string
-	db     persistence.Persistence
+	server   *http.Server
+	port     int
+	apiKey   string
+	adminKey string
+	db       persistence.Persistence
 }
 
-// NewHttpServer creates and returns a new HttpServer with a listener on the specified port
-func NewHttpServer(port int, apiKey string, persistence persistence.Persistence) (HttpServer, error) {
+// NewHttpServer creates and returns a new HttpServer with a listener on the specified port,
+// and an admin key which PUT /taxonomy/ requires, if it is not empty
+func NewHttpServer(port int, apiKey string, adminKey string, persistence persistence.Persistence) (HttpServer, error) {
 	s := HttpServer{server: &http.Server{Addr: fmt.Sprintf(":%d", port)},
-		port:   port,
-		apiKey: apiKey,
-		db:     persistence,
+		port:     port,
+		apiKey:   apiKey,
+		adminKey: adminKey,
+		db:       persistence,
 	}
 
 	mux := http.NewServeMux()
@@ -71,7 +77,51 @@ func (s *HttpServer) router(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if r.Method == "DELETE" && strings.HasPrefix(r.RequestURI, "/recipe/") {
+		s.deleteRecipe(w, r)
+		return
+	}
+
+	if r.Method == "GET" && r.URL.Path == "/recipes:search" {
+		s.searchRecipesByName(w, r)
+		return
+	}
+
+	if r.Method == "GET" && r.URL.Path == "/recipes:similar" {
+		s.similarRecipes(w, r)
+		return
+	}
+
+	if r.Method == "GET" && r.URL.Path == "/ingredients" {
+		s.listIngredients(w, r)
+		return
+	}
+
+	if r.Method == "GET" && r.URL.Path == "/ingredients:complete" {
+		s.completeIngredient(w, r)
+		return
+	}
+
+	if r.Method == "GET" && r.URL.Path == "/stats" {
+		s.getStats(w, r)
+		return
+	}
+
+	if r.Method == "GET" && r.URL.Path == "/taxonomy" {
+		s.getTaxonomy(w, r)
+		return
+	}
+
+	if r.Method == "PUT" && strings.HasPrefix(r.URL.Path, "/taxonomy/") {
+		s.setIngredientKind(w, r)
+		return
+	}
+
 	if r.Method == "GET" && strings.HasPrefix(r.RequestURI, "/recipes") {
+		if query := r.URL.Query(); !query.Has("ingredients") && !query.Has("exclude") && !query.Has("q") {
+			s.listRecipes(w, r)
+			return
+		}
 		s.findRecipes(w, r)
 		return
 	}
@@ -104,33 +154,129 @@ func (s *HttpServer) addRecipe(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = s.db.AddRecipe(persistence.Recipe(recipe))
+	for i, ingredient := range recipe.Ingredients {
+		if ingredient.Name == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(fmt.Sprintf("no name specified for ingredient %d", i+1)))
+			return
+		}
+	}
+
+	if recipe.Servings < 0 || recipe.PrepMinutes < 0 || recipe.CookMinutes < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("servings and times must not be negative"))
+		return
+	}
+
+	err = s.db.AddRecipe(r.Context(), toPersistence(recipe))
+	if errors.Is(err, persistence.ErrRecipeCycle) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf("recipe (%s) would be a component of itself", recipe.Name)))
+		return
+	}
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		w.Write([]byte("error writing recipe to database"))
 	}
 }
 
-// getRecipe is the Handler for retrieving a recipe by name
+// getRecipe is the Handler for retrieving a recipe by name, or with fuzzy=true the recipe with the closest name.
+// With expand=true the components of the recipe are replaced by their ingredients, and with servings or units its
+// quantities are scaled to that many servings or converted to "metric" or "imperial" units.
 func (s *HttpServer) getRecipe(w http.ResponseWriter, r *http.Request) {
-	name, err := url.QueryUnescape(strings.TrimPrefix(r.RequestURI, "/recipe/"))
+	path, _, _ := strings.Cut(strings.TrimPrefix(r.RequestURI, "/recipe/"), "?")
+	name, err := url.QueryUnescape(path)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
-	recipe, err := s.db.GetRecipe(name)
-	if err == persistence.ErrNoResults {
-		w.WriteHeader(http.StatusNotFound)
+	fuzzy := false
+	if v := r.URL.Query().Get("fuzzy"); v != "" {
+		fuzzy, err = strconv.ParseBool(v)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(fmt.Sprintf("invalid fuzzy (%s)", v)))
+			return
+		}
+	}
+
+	expand := false
+	if v := r.URL.Query().Get("expand"); v != "" {
+		expand, err = strconv.ParseBool(v)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(fmt.Sprintf("invalid expand (%s)", v)))
+			return
+		}
+	}
+
+	servings := 0
+	if v := r.URL.Query().Get("servings"); v != "" {
+		servings, err = strconv.Atoi(v)
+		if err != nil || servings < 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(fmt.Sprintf("invalid servings (%s)", v)))
+			return
+		}
+	}
+
+	system, err := units.ParseSystem(r.URL.Query().Get("units"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
 		return
 	}
+
+	recipe, err := s.db.GetRecipe(r.Context(), name)
+	if err == persistence.ErrNoResults {
+		suggestions, serr := persistence.SuggestRecipes(r.Context(), s.db, name)
+		if serr != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("error reading recipes from database"))
+			return
+		}
+		if fuzzy && len(suggestions) > 0 {
+			recipe, err = s.db.GetRecipe(r.Context(), suggestions[0])
+		}
+		if err == persistence.ErrNoResults {
+			rsp, _ := json.Marshal(NotFound{Error: fmt.Sprintf("recipe (%s) not found", name), Suggestions: suggestions})
+			w.WriteHeader(http.StatusNotFound)
+			w.Write(rsp)
+			return
+		}
+	}
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		w.Write([]byte("error reading recipe from database"))
 		return
 	}
 
-	rsp, err := json.Marshal(Recipe(recipe))
+	if expand {
+		recipe, err = persistence.ExpandRecipe(r.Context(), s.db, recipe)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("error reading recipe from database"))
+			return
+		}
+	}
+
+	if servings != 0 || system != "" {
+		scaled, err := persistence.ScaleRecipe(recipe, servings, system)
+		if errors.Is(err, persistence.ErrUnknownServings) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(fmt.Sprintf("recipe (%s) does not say how many servings it makes", recipe.Name)))
+			return
+		}
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("error scaling recipe"))
+			return
+		}
+		recipe = scaled
+	}
+
+	rsp, err := json.Marshal(fromPersistence(recipe))
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		w.Write([]byte("error marshalling recipe into json"))
@@ -140,7 +286,28 @@ func (s *HttpServer) getRecipe(w http.ResponseWriter, r *http.Request) {
 	w.Write(rsp)
 }
 
-// findRecipes is the Handler for listing recipes by ingredients
+// deleteRecipe is the Handler for removing a recipe by name
+func (s *HttpServer) deleteRecipe(w http.ResponseWriter, r *http.Request) {
+	name, err := url.QueryUnescape(strings.TrimPrefix(r.RequestURI, "/recipe/"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = s.db.DeleteRecipe(r.Context(), name)
+	if err == persistence.ErrNoResults {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("error deleting recipe from database"))
+		return
+	}
+}
+
+// findRecipes is the Handler for listing recipes by ingredients or an ingredient query, optionally leaving out
+// those with excluded ingredients, and with expand=true matching the kinds of each ingredient too
 func (s *HttpServer) findRecipes(w http.ResponseWriter, r *http.Request) {
 	unescaped, err := url.QueryUnescape(strings.TrimPrefix(r.RequestURI, "/recipes"))
 	if err != nil {
@@ -159,33 +326,415 @@ func (s *HttpServer) findRecipes(w http.ResponseWriter, r *http.Request) {
 		params = strings.Split(elems[1], "&")
 	}
 
-	var ingredients []string
+	query := persistence.Query{}
+	fuzzy := false
 	for _, v := range params {
-		if strings.HasPrefix(v, "ingredients=") {
-			ingredients = strings.Split(strings.TrimPrefix(v, "ingredients="), ",")
+		switch {
+		case strings.HasPrefix(v, "ingredients="):
+			query.Ingredients = strings.Split(strings.TrimPrefix(v, "ingredients="), ",")
+		case strings.HasPrefix(v, "exclude="):
+			query.Exclude = strings.Split(strings.TrimPrefix(v, "exclude="), ",")
+		case strings.HasPrefix(v, "q="):
+			query.Expr, err = persistence.ParseExpr(strings.TrimPrefix(v, "q="))
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(err.Error()))
+				return
+			}
+		case strings.HasPrefix(v, "mode="):
+			query.Mode, err = parseMatchMode(strings.TrimPrefix(v, "mode="))
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(err.Error()))
+				return
+			}
+		case strings.HasPrefix(v, "fuzzy="):
+			fuzzy, err = strconv.ParseBool(strings.TrimPrefix(v, "fuzzy="))
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(fmt.Sprintf("invalid fuzzy (%s)", strings.TrimPrefix(v, "fuzzy="))))
+				return
+			}
+		case strings.HasPrefix(v, "expand="):
+			query.Expand, err = strconv.ParseBool(strings.TrimPrefix(v, "expand="))
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(fmt.Sprintf("invalid expand (%s)", strings.TrimPrefix(v, "expand="))))
+				return
+			}
+		case strings.HasPrefix(v, "max_missing="):
+			query.MaxMissing, err = strconv.Atoi(strings.TrimPrefix(v, "max_missing="))
+			if err != nil || query.MaxMissing < 0 {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(fmt.Sprintf("invalid max missing (%s)", strings.TrimPrefix(v, "max_missing="))))
+				return
+			}
 		}
 	}
 
-	if len(ingredients) == 0 {
+	if len(query.Ingredients) == 0 && query.Expr == nil {
 		w.WriteHeader(http.StatusBadRequest)
 		w.Write([]byte("no ingredients specified"))
 		return
 	}
 
-	dbrecipes, err := s.db.FindRecipes(ingredients)
+	// A fuzzy search looks for the closest ingredients to those which no recipe uses instead
+	var suggestions []persistence.Suggestion
+	if fuzzy {
+		suggestions, err = persistence.SuggestIngredients(r.Context(), s.db, query.Names())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("error reading ingredients from database"))
+			return
+		}
+		query = query.Corrected(suggestions)
+	}
+
+	dbrecipes, err := s.db.SearchRecipes(r.Context(), query)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("error reading recipes from database"))
+		return
+	}
+
+	// Explain a search which found nothing, in case an ingredient was misspelt
+	if len(dbrecipes) == 0 && !fuzzy {
+		suggestions, err = persistence.SuggestIngredients(r.Context(), s.db, query.Names())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("error reading ingredients from database"))
+			return
+		}
+	}
+
+	// Convert []persistence.Recipe to Recipes, the best matches first
+	recipes := Recipes{Recipes: []Recipe{}, Matches: []Match{}}
+	for _, r := range query.Rank(dbrecipes) {
+		recipes.Recipes = append(recipes.Recipes, fromPersistence(r.Recipe))
+		recipes.Matches = append(recipes.Matches, Match{
+			Recipe:             r.Recipe.Name,
+			MissingIngredients: r.Score.Missing,
+			MatchedIngredients: r.Score.Matched,
+			Matched:            len(r.Score.Matched),
+			Missing:            len(r.Score.Missing),
+			Extra:              r.Score.Extra,
+			Score:              r.Score.Value(),
+		})
+	}
+	for _, suggestion := range suggestions {
+		recipes.Suggestions = append(recipes.Suggestions, Suggestion(suggestion))
+	}
+
+	rsp, err := json.Marshal(recipes)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("error marshalling recipes into json"))
+		return
+	}
+
+	w.Write(rsp)
+}
+
+// listRecipes is the Handler for listing all recipes a page at a time
+func (s *HttpServer) listRecipes(w http.ResponseWriter, r *http.Request) {
+	size, cursor, err := parsePage(r.URL.Query())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	// Ask for one more recipe than fits on the page, to find out whether there is a next page
+	dbrecipes, err := s.db.ListRecipes(r.Context(), cursor, size+1)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("error reading recipes from database"))
+		return
+	}
+
+	writePage(w, dbrecipes, size)
+}
+
+// searchRecipesByName is the Handler for searching recipes by name a page at a time
+func (s *HttpServer) searchRecipesByName(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+
+	query := persistence.NameQuery{Text: values.Get("name")}
+	if strings.TrimSpace(query.Text) == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("no name specified"))
+		return
+	}
+
+	if v := values.Get("match"); v != "" {
+		match, err := parseNameMatch(v)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		query.Match = match
+	}
+
+	size, cursor, err := parsePage(values)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	// Ask for one more recipe than fits on the page, to find out whether there is a next page
+	dbrecipes, err := s.db.SearchRecipesByName(r.Context(), query, cursor, size+1)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("error searching recipes in database"))
+		return
+	}
+
+	writePage(w, dbrecipes, size)
+}
+
+// listIngredients is the Handler for listing all ingredients which recipes use, with the number of recipes using each
+func (s *HttpServer) listIngredients(w http.ResponseWriter, r *http.Request) {
+	dbingredients, err := s.db.ListIngredients(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("error reading ingredients from database"))
+		return
+	}
+
+	writeIngredients(w, dbingredients)
+}
+
+// completeIngredient is the Handler for completing the start of an ingredient name, most used ingredients first
+func (s *HttpServer) completeIngredient(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+
+	requested := 0
+	if v := values.Get("limit"); v != "" {
+		var err error
+		requested, err = strconv.Atoi(v)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(fmt.Sprintf("invalid limit (%s)", v)))
+			return
+		}
+	}
+	limit, err := persistence.CompletionLimit(requested)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	dbingredients, err := s.db.CompleteIngredient(r.Context(), values.Get("prefix"), limit)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("error reading ingredients from database"))
+		return
+	}
+
+	writeIngredients(w, dbingredients)
+}
+
+// similarRecipes is the Handler for finding the recipes which share the most ingredients with a recipe
+func (s *HttpServer) similarRecipes(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+
+	name := values.Get("name")
+	if name == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("no name specified"))
+		return
+	}
+
+	requested := 0
+	if v := values.Get("limit"); v != "" {
+		var err error
+		requested, err = strconv.Atoi(v)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(fmt.Sprintf("invalid limit (%s)", v)))
+			return
+		}
+	}
+	limit, err := persistence.SimilarLimit(requested)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	dbsimilar, err := s.db.SimilarRecipes(r.Context(), name, limit)
+	if err == persistence.ErrNoResults {
+		suggestions, serr := persistence.SuggestRecipes(r.Context(), s.db, name)
+		if serr != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("error reading recipes from database"))
+			return
+		}
+		rsp, _ := json.Marshal(NotFound{Error: fmt.Sprintf("recipe (%s) not found", name), Suggestions: suggestions})
+		w.WriteHeader(http.StatusNotFound)
+		w.Write(rsp)
+		return
+	}
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		w.Write([]byte("error reading recipes from database"))
 		return
 	}
 
+	similar := SimilarRecipes{Recipes: []SimilarRecipe{}}
+	for _, v := range dbsimilar {
+		similar.Recipes = append(similar.Recipes, SimilarRecipe{Name: v.Name, Similarity: v.Similarity, SharedIngredients: v.Shared})
+	}
+
+	rsp, err := json.Marshal(similar)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("error marshalling recipes into json"))
+		return
+	}
+
+	w.Write(rsp)
+}
+
+// getStats is the Handler for retrieving statistics about all recipes
+func (s *HttpServer) getStats(w http.ResponseWriter, r *http.Request) {
+	dbstats, err := s.db.CatalogueStats(r.Context(), persistence.StatsTop)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("error reading stats from database"))
+		return
+	}
+
+	stats := Stats{
+		Recipes:            dbstats.Recipes,
+		Ingredients:        dbstats.Ingredients,
+		MostUsed:           []IngredientUsage{},
+		LeastUsed:          []IngredientUsage{},
+		AverageIngredients: dbstats.AverageIngredients,
+		IsolatedRecipes:    append([]string{}, dbstats.IsolatedRecipes...),
+	}
+	for _, v := range dbstats.MostUsed {
+		stats.MostUsed = append(stats.MostUsed, IngredientUsage(v))
+	}
+	for _, v := range dbstats.LeastUsed {
+		stats.LeastUsed = append(stats.LeastUsed, IngredientUsage(v))
+	}
+
+	rsp, err := json.Marshal(stats)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("error marshalling stats into json"))
+		return
+	}
+
+	w.Write(rsp)
+}
+
+// getTaxonomy is the Handler for retrieving the hierarchy of ingredients which expanded searches use
+func (s *HttpServer) getTaxonomy(w http.ResponseWriter, r *http.Request) {
+	taxonomy := Taxonomy{Kinds: []Kind{}}
+	for _, kind := range persistence.Taxonomy() {
+		taxonomy.Kinds = append(taxonomy.Kinds, Kind(kind))
+	}
+
+	rsp, err := json.Marshal(taxonomy)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("error marshalling taxonomy"))
+		return
+	}
+
+	w.Write(rsp)
+}
+
+// setIngredientKind is the Handler for making an ingredient a kind of the parent in the body, or
+// removing it from the taxonomy with an empty parent
+func (s *HttpServer) setIngredientKind(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	// Every client has the API key, so changing the taxonomy also requires the admin key
+	if s.adminKey == "" {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("taxonomy changes are disabled"))
+		return
+	}
+	if r.Header.Get("X-Admin-Key") != s.adminKey {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("admin authentication failed"))
+		return
+	}
+
+	body, _ := ioutil.ReadAll(r.Body)
+
+	kind := Kind{}
+	if err := json.Unmarshal(body, &kind); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("error unmarshalling kind"))
+		return
+	}
+
+	kind.Ingredient = strings.TrimPrefix(r.URL.Path, "/taxonomy/")
+	if strings.TrimSpace(kind.Ingredient) == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("no ingredient specified"))
+		return
+	}
+
+	err := persistence.SetKind(kind.Ingredient, kind.Parent)
+	if errors.Is(err, persistence.ErrTaxonomyCycle) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("error saving taxonomy"))
+		return
+	}
+}
+
+// parsePage reads the page_size and page_token parameters of a paged request, returning
+// the page size to use and the cursor of the page
+func parsePage(values url.Values) (int, string, error) {
+	requested := 0
+	if v := values.Get("page_size"); v != "" {
+		var err error
+		requested, err = strconv.Atoi(v)
+		if err != nil {
+			return 0, "", fmt.Errorf("invalid page size (%s)", v)
+		}
+	}
+	size, err := persistence.PageSize(requested)
+	if err != nil {
+		return 0, "", err
+	}
+
+	cursor, err := persistence.DecodePageToken(values.Get("page_token"))
+	if err != nil {
+		return 0, "", err
+	}
+
+	return size, cursor, nil
+}
+
+// writePage writes up to size of the recipes as a RecipePage, whose next page token is set
+// if there are more recipes than that
+func writePage(w http.ResponseWriter, dbrecipes []persistence.Recipe, size int) {
+	page := RecipePage{Recipes: []Recipe{}}
+	if len(dbrecipes) > size {
+		dbrecipes = dbrecipes[:size]
+		page.NextPageToken = persistence.EncodePageToken(dbrecipes[size-1].Name)
+	}
+
 	// Convert []persistence.Recipe to []Recipe
-	recipes := []Recipe{}
 	for _, r := range dbrecipes {
-		recipes = append(recipes, Recipe(r))
+		page.Recipes = append(page.Recipes, fromPersistence(r))
 	}
 
-	rsp, err := json.Marshal(Recipes{Recipes: recipes})
+	rsp, err := json.Marshal(page)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		w.Write([]byte("error marshalling recipes into json"))
@@ -195,6 +744,93 @@ func (s *HttpServer) findRecipes(w http.ResponseWriter, r *http.Request) {
 	w.Write(rsp)
 }
 
+// writeIngredients writes the ingredients as Ingredients
+func writeIngredients(w http.ResponseWriter, dbingredients []persistence.IngredientUsage) {
+	ingredients := Ingredients{Ingredients: []IngredientUsage{}}
+	for _, v := range dbingredients {
+		ingredients.Ingredients = append(ingredients.Ingredients, IngredientUsage(v))
+	}
+
+	rsp, err := json.Marshal(ingredients)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("error marshalling ingredients into json"))
+		return
+	}
+
+	w.Write(rsp)
+}
+
+// parseMatchMode reads the match mode of a search, which is either the name the gRPC gateway
+// uses, such as MATCH_SUBSET, or just the last part of it, such as subset
+func parseMatchMode(s string) (persistence.MatchMode, error) {
+	switch strings.TrimPrefix(strings.ToUpper(s), "MATCH_") {
+	case "ALL":
+		return persistence.MatchAll, nil
+	case "ANY":
+		return persistence.MatchAny, nil
+	case "SUBSET":
+		return persistence.MatchSubset, nil
+	}
+
+	return persistence.MatchAll, fmt.Errorf("unknown match mode (%s)", s)
+}
+
+// parseNameMatch reads how a name search matches names, which is either the name the gRPC gateway
+// uses, such as NAME_SUBSTRING, or just the last part of it, such as substring
+func parseNameMatch(s string) (persistence.NameMatch, error) {
+	switch strings.TrimPrefix(strings.ToUpper(s), "NAME_") {
+	case "PREFIX":
+		return persistence.NamePrefix, nil
+	case "SUBSTRING":
+		return persistence.NameSubstring, nil
+	}
+
+	return persistence.NamePrefix, fmt.Errorf("unknown name match (%s)", s)
+}
+
+// toPersistence converts a Recipe to a persistence.Recipe
+func toPersistence(r Recipe) persistence.Recipe {
+	recipe := persistence.Recipe{
+		Name:         r.Name,
+		Description:  r.Description,
+		Instructions: r.Instructions,
+		Servings:     r.Servings,
+		PrepMinutes:  r.PrepMinutes,
+		CookMinutes:  r.CookMinutes,
+		Source:       r.Source,
+	}
+	if r.Ingredients != nil {
+		recipe.Ingredients = make([]persistence.Ingredient, 0, len(r.Ingredients))
+		for _, ingredient := range r.Ingredients {
+			recipe.Ingredients = append(recipe.Ingredients, persistence.Ingredient(ingredient))
+		}
+	}
+
+	return recipe
+}
+
+// fromPersistence converts a persistence.Recipe to a Recipe
+func fromPersistence(r persistence.Recipe) Recipe {
+	recipe := Recipe{
+		Name:         r.Name,
+		Description:  r.Description,
+		Instructions: r.Instructions,
+		Servings:     r.Servings,
+		PrepMinutes:  r.PrepMinutes,
+		CookMinutes:  r.CookMinutes,
+		Source:       r.Source,
+	}
+	if r.Ingredients != nil {
+		recipe.Ingredients = make([]Ingredient, 0, len(r.Ingredients))
+		for _, ingredient := range r.Ingredients {
+			recipe.Ingredients = append(recipe.Ingredients, Ingredient(ingredient))
+		}
+	}
+
+	return recipe
+}
+
 // tracer measures the time it took for each API call to be processed
 func (s *HttpServer) tracer(originalHandler http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
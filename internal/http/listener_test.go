@@ -2,6 +2,7 @@ package http
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"go-incubator/internal/persistence"
 	"io"
@@ -25,25 +26,28 @@ type mockdb struct {
 func NewMockDB() *mockdb {
 	mdb := &mockdb{}
 	mdb.recipes = make(map[string]persistence.Recipe)
-	mdb.recipes["Cheese Fondue"] = persistence.Recipe{Name: "Cheese Fondue", Ingredients: []string{"Gruyere", "Emmental"}}
-	mdb.recipes["Mac & Cheese"] = persistence.Recipe{Name: "Mac & Cheese", Ingredients: []string{"Mozzarella", "Macaroni"}}
-	mdb.recipes["SpagBol"] = persistence.Recipe{Name: "SpagBol", Ingredients: []string{"Spaghetti", "Ground Beef", "Tomato"}}
-	mdb.recipes["BLT"] = persistence.Recipe{Name: "BLT", Ingredients: []string{"Tomato", "Bacon", "Lettuce"}}
-	mdb.recipes["Greek Salad"] = persistence.Recipe{Name: "Greek Salad", Ingredients: []string{"Feta", "Tomato", "Cucumber"}}
-	mdb.recipes["Caprese Salad"] = persistence.Recipe{Name: "Caprese Salad", Ingredients: []string{"Mozzarella", "Tomato"}}
-	mdb.recipes["Meatballs"] = persistence.Recipe{Name: "Meatballs", Ingredients: []string{"Ground Beef", "Tomato"}}
+	mdb.recipes["Cheese Fondue"] = persistence.Recipe{Name: "Cheese Fondue", Ingredients: persistence.NamedIngredients([]string{"Gruyere", "Emmental"})}
+	mdb.recipes["Mac & Cheese"] = persistence.Recipe{Name: "Mac & Cheese", Ingredients: persistence.NamedIngredients([]string{"Mozzarella", "Macaroni"})}
+	mdb.recipes["SpagBol"] = persistence.Recipe{Name: "SpagBol", Ingredients: persistence.NamedIngredients([]string{"Spaghetti", "Ground Beef", "Tomato"})}
+	mdb.recipes["BLT"] = persistence.Recipe{Name: "BLT", Ingredients: persistence.NamedIngredients([]string{"Tomato", "Bacon", "Lettuce"})}
+	mdb.recipes["Greek Salad"] = persistence.Recipe{Name: "Greek Salad", Ingredients: persistence.NamedIngredients([]string{"Feta", "Tomato", "Cucumber"})}
+	mdb.recipes["Caprese Salad"] = persistence.Recipe{Name: "Caprese Salad", Ingredients: persistence.NamedIngredients([]string{"Mozzarella", "Tomato"})}
+	mdb.recipes["Meatballs"] = persistence.Recipe{Name: "Meatballs", Ingredients: persistence.NamedIngredients([]string{"Ground Beef", "Tomato"})}
 
 	return mdb
 }
 
-func (db *mockdb) AddRecipe(recipe persistence.Recipe) error {
+func (db *mockdb) AddRecipe(ctx context.Context, recipe persistence.Recipe) error {
 	if recipe.Name == "DB Error" {
 		return fmt.Errorf("Database Error")
 	}
-	return nil
+	return persistence.CheckComponents(recipe, func(name string) ([]string, error) {
+		component := db.recipes[name]
+		return component.Components(), nil
+	})
 }
 
-func (db *mockdb) GetRecipe(name string) (persistence.Recipe, error) {
+func (db *mockdb) GetRecipe(ctx context.Context, name string) (persistence.Recipe, error) {
 	if name == "DBError" {
 		return persistence.Recipe{}, fmt.Errorf("Database Error")
 	}
@@ -55,8 +59,23 @@ func (db *mockdb) GetRecipe(name string) (persistence.Recipe, error) {
 	return r, nil
 }
 
-func (db *mockdb) FindRecipes(ingredients []string) ([]persistence.Recipe, error) {
-	if strings.Join(ingredients, "") == "DBError" {
+func (db *mockdb) DeleteRecipe(ctx context.Context, name string) error {
+	if name == "DBError" {
+		return fmt.Errorf("Database Error")
+	}
+	if _, ok := db.recipes[name]; !ok {
+		return persistence.ErrNoResults
+	}
+
+	return nil
+}
+
+func (db *mockdb) FindRecipes(ctx context.Context, ingredients []string) ([]persistence.Recipe, error) {
+	return db.SearchRecipes(ctx, persistence.Query{Ingredients: ingredients})
+}
+
+func (db *mockdb) SearchRecipes(ctx context.Context, query persistence.Query) ([]persistence.Recipe, error) {
+	if strings.Join(query.Ingredients, "") == "DBError" {
 		return nil, fmt.Errorf("Database Error")
 	}
 
@@ -69,7 +88,7 @@ func (db *mockdb) FindRecipes(ingredients []string) ([]persistence.Recipe, error
 	recipes := make([]persistence.Recipe, 0)
 	for _, k := range keys {
 		recipe := db.recipes[k]
-		if UsesIngredients(recipe, ingredients) {
+		if query.Matches(&recipe) {
 			recipes = append(recipes, recipe)
 		}
 	}
@@ -77,24 +96,190 @@ func (db *mockdb) FindRecipes(ingredients []string) ([]persistence.Recipe, error
 	return recipes, nil
 }
 
-func UsesIngredient(r persistence.Recipe, ingredient string) bool {
-	for _, v := range r.Ingredients {
-		if v == ingredient {
-			return true
+func (db *mockdb) ListRecipes(ctx context.Context, cursor string, limit int) ([]persistence.Recipe, error) {
+	if cursor == "DBError" {
+		return nil, fmt.Errorf("Database Error")
+	}
+	keys := make([]string, 0, len(db.recipes))
+	for k := range db.recipes {
+		if k > cursor {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	if len(keys) > limit {
+		keys = keys[:limit]
+	}
+
+	recipes := make([]persistence.Recipe, 0, len(keys))
+	for _, k := range keys {
+		recipes = append(recipes, db.recipes[k])
+	}
+
+	return recipes, nil
+}
+
+func (db *mockdb) SearchRecipesByName(ctx context.Context, query persistence.NameQuery, cursor string, limit int) ([]persistence.Recipe, error) {
+	if query.Text == "DBError" {
+		return nil, fmt.Errorf("Database Error")
+	}
+	keys := make([]string, 0, len(db.recipes))
+	for k := range db.recipes {
+		if k > cursor && query.Matches(k) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	if len(keys) > limit {
+		keys = keys[:limit]
+	}
+
+	recipes := make([]persistence.Recipe, 0, len(keys))
+	for _, k := range keys {
+		recipes = append(recipes, db.recipes[k])
+	}
+
+	return recipes, nil
+}
+
+func (db *mockdb) RecipeNames(ctx context.Context) ([]string, error) {
+	names := make([]string, 0, len(db.recipes))
+	for k := range db.recipes {
+		names = append(names, k)
+	}
+
+	return names, nil
+}
+
+func (db *mockdb) IngredientNames(ctx context.Context) ([]string, error) {
+	seen := make(map[string]bool)
+	names := []string{}
+	for _, recipe := range db.recipes {
+		for _, ingredient := range recipe.Ingredients {
+			if !seen[ingredient.Name] {
+				seen[ingredient.Name] = true
+				names = append(names, ingredient.Name)
+			}
+		}
+	}
+
+	return names, nil
+}
+
+func (db *mockdb) ListIngredients(ctx context.Context) ([]persistence.IngredientUsage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	ingredients := db.usage("")
+	sort.Slice(ingredients, func(i, j int) bool { return ingredients[i].Name < ingredients[j].Name })
+
+	return ingredients, nil
+}
+
+func (db *mockdb) CompleteIngredient(ctx context.Context, prefix string, limit int) ([]persistence.IngredientUsage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if prefix == "DBError" {
+		return nil, fmt.Errorf("Database Error")
+	}
+	ingredients := db.usage(prefix)
+	persistence.SortByUsage(ingredients)
+	if len(ingredients) > limit {
+		ingredients = ingredients[:limit]
+	}
+
+	return ingredients, nil
+}
+
+func (db *mockdb) CatalogueStats(ctx context.Context, top int) (persistence.CatalogueStats, error) {
+	if err := ctx.Err(); err != nil {
+		return persistence.CatalogueStats{}, err
+	}
+	counts := make(map[string]int)
+	lines := 0
+	for _, usage := range db.usage("") {
+		counts[usage.Name] = usage.Recipes
+	}
+
+	stats := persistence.CatalogueStats{Recipes: len(db.recipes), Ingredients: len(counts), IsolatedRecipes: []string{}}
+	for _, recipe := range db.recipes {
+		isolated := true
+		for _, ingredient := range recipe.Ingredients {
+			lines++
+			if counts[ingredient.Name] > 1 {
+				isolated = false
+			}
+		}
+		if isolated {
+			stats.IsolatedRecipes = append(stats.IsolatedRecipes, recipe.Name)
+		}
+	}
+	sort.Strings(stats.IsolatedRecipes)
+	stats.AverageIngredients = persistence.Average(lines, len(db.recipes))
+
+	ingredients := db.usage("")
+	persistence.SortByUsage(ingredients)
+	stats.MostUsed = append(stats.MostUsed, ingredients[:top]...)
+	sort.SliceStable(ingredients, func(i, j int) bool { return ingredients[i].Recipes < ingredients[j].Recipes })
+	stats.LeastUsed = append(stats.LeastUsed, ingredients[:top]...)
+
+	return stats, nil
+}
+
+func (db *mockdb) SimilarRecipes(ctx context.Context, name string, limit int) ([]persistence.SimilarRecipe, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if name == "DBError" {
+		return nil, fmt.Errorf("Database Error")
+	}
+	recipe, ok := db.recipes[name]
+	if !ok {
+		return nil, persistence.ErrNoResults
+	}
+
+	similar := []persistence.SimilarRecipe{}
+	for _, other := range db.recipes {
+		if other.Name == name {
+			continue
+		}
+		shared := []string{}
+		for _, ingredient := range recipe.Ingredients {
+			if other.UsesIngredient(ingredient.Name) {
+				shared = append(shared, ingredient.Name)
+			}
+		}
+		if len(shared) > 0 {
+			similar = append(similar, persistence.SimilarRecipe{Name: other.Name, Similarity: persistence.Jaccard(len(shared), len(recipe.Ingredients), len(other.Ingredients)), Shared: shared})
 		}
 	}
+	persistence.SortBySimilarity(similar)
+	if len(similar) > limit {
+		similar = similar[:limit]
+	}
 
-	return false
+	return similar, nil
 }
 
-func UsesIngredients(r persistence.Recipe, ingredients []string) bool {
-	for _, v := range ingredients {
-		if !UsesIngredient(r, v) {
-			return false
+// usage counts the recipes which use each ingredient that completes the prefix
+func (db *mockdb) usage(prefix string) []persistence.IngredientUsage {
+	prefixes := persistence.CompletionPrefixes(prefix)
+	counts := make(map[string]int)
+	for _, recipe := range db.recipes {
+		for _, ingredient := range recipe.Ingredients {
+			if persistence.CompletesTo(persistence.NormaliseIngredient(ingredient.Name), prefixes) {
+				counts[ingredient.Name]++
+			}
 		}
 	}
 
-	return true
+	ingredients := []persistence.IngredientUsage{}
+	for name, count := range counts {
+		ingredients = append(ingredients, persistence.IngredientUsage{Name: name, Recipes: count})
+	}
+
+	return ingredients
 }
 
 func captureOutput(f func()) string {
@@ -129,8 +314,9 @@ func captureOutput(f func()) string {
 
 func TestNewHttpServer(t *testing.T) {
 	type args struct {
-		port   int
-		apiKey string
+		port     int
+		apiKey   string
+		adminKey string
 	}
 	tests := []struct {
 		name    string
@@ -140,13 +326,14 @@ func TestNewHttpServer(t *testing.T) {
 	}{
 		{
 			name: "1",
-			args: args{port: 1234, apiKey: "1234"},
+			args: args{port: 1234, apiKey: "1234", adminKey: "5678"},
 			want: HttpServer{
 				server: &http.Server{
 					Addr: fmt.Sprintf(":%d", 1234),
 				},
-				apiKey: "1234",
-				db:     NewMockDB(),
+				apiKey:   "1234",
+				adminKey: "5678",
+				db:       NewMockDB(),
 			},
 		},
 		{
@@ -163,12 +350,12 @@ func TestNewHttpServer(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := NewHttpServer(tt.args.port, tt.args.apiKey, tt.want.db)
+			got, err := NewHttpServer(tt.args.port, tt.args.apiKey, tt.args.adminKey, tt.want.db)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("NewHttpServer() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if got.server.Addr != tt.want.server.Addr || got.apiKey != tt.want.apiKey || !reflect.DeepEqual(got.db, tt.want.db) {
+			if got.server.Addr != tt.want.server.Addr || got.apiKey != tt.want.apiKey || got.adminKey != tt.want.adminKey || !reflect.DeepEqual(got.db, tt.want.db) {
 				t.Errorf("NewHttpServer() = %v, want %v", got, tt.want)
 			}
 		})
@@ -176,7 +363,7 @@ func TestNewHttpServer(t *testing.T) {
 }
 
 func TestHttpServer_addRecipe(t *testing.T) {
-	server, _ := NewHttpServer(1234, "1234", NewMockDB())
+	server, _ := NewHttpServer(1234, "1234", "", NewMockDB())
 
 	type response struct {
 		code int
@@ -228,6 +415,38 @@ func TestHttpServer_addRecipe(t *testing.T) {
 				body: `error writing recipe to database`,
 			},
 		},
+		{
+			name: "6",
+			body: `{"name":"Pancakes","structuredIngredients":[{"name":"Flour","quantity":2,"unit":"cups","note":"sifted"},{"name":"Egg","quantity":1}]}`,
+			want: response{
+				code: http.StatusOK,
+				body: ``,
+			},
+		},
+		{
+			name: "7",
+			body: `{"name":"Pancakes","structuredIngredients":[{"name":"Flour"},{"quantity":1,"unit":"cup"}]}`,
+			want: response{
+				code: http.StatusBadRequest,
+				body: `no name specified for ingredient 2`,
+			},
+		},
+		{
+			name: "8",
+			body: `{"name":"Pancakes","ingredients":["Flour"],"servings":-1}`,
+			want: response{
+				code: http.StatusBadRequest,
+				body: `servings and times must not be negative`,
+			},
+		},
+		{
+			name: "9",
+			body: `{"name":"Pizza","structuredIngredients":[{"name":"Mozzarella"},{"name":"Pizza","component":true}]}`,
+			want: response{
+				code: http.StatusBadRequest,
+				body: `recipe (Pizza) would be a component of itself`,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -244,7 +463,9 @@ func TestHttpServer_addRecipe(t *testing.T) {
 }
 
 func TestHttpServer_getRecipe(t *testing.T) {
-	server, _ := NewHttpServer(1234, "1234", NewMockDB())
+	db := NewMockDB()
+	db.recipes["Tomato Toast"] = persistence.Recipe{Name: "Tomato Toast", Ingredients: []persistence.Ingredient{{Name: "Bread", Quantity: 2, Unit: "slices"}, {Name: "Caprese Salad", Component: true}, {Name: "Butter", Quantity: 10, Unit: "g"}}, Servings: 2}
+	server, _ := NewHttpServer(1234, "1234", "", db)
 
 	type response struct {
 		code int
@@ -262,7 +483,7 @@ func TestHttpServer_getRecipe(t *testing.T) {
 			path: "/recipe/Cheese%20Fondue",
 			want: response{
 				code: http.StatusOK,
-				body: `{"name":"Cheese Fondue","ingredients":["Gruyere","Emmental"]}`,
+				body: `{"name":"Cheese Fondue","ingredients":["Gruyere","Emmental"],"structuredIngredients":[{"name":"Gruyere"},{"name":"Emmental"}]}`,
 			},
 		},
 		{
@@ -270,7 +491,7 @@ func TestHttpServer_getRecipe(t *testing.T) {
 			path: "/recipe/SpagBol",
 			want: response{
 				code: http.StatusOK,
-				body: `{"name":"SpagBol","ingredients":["Spaghetti","Ground Beef","Tomato"]}`,
+				body: `{"name":"SpagBol","ingredients":["Spaghetti","Ground Beef","Tomato"],"structuredIngredients":[{"name":"Spaghetti"},{"name":"Ground Beef"},{"name":"Tomato"}]}`,
 			},
 		},
 		{
@@ -278,6 +499,7 @@ func TestHttpServer_getRecipe(t *testing.T) {
 			path: "/recipe/Pizza",
 			want: response{
 				code: http.StatusNotFound,
+				body: `{"error":"recipe (Pizza) not found","suggestions":[]}`,
 			},
 		},
 		{
@@ -288,88 +510,100 @@ func TestHttpServer_getRecipe(t *testing.T) {
 				body: "error reading recipe from database",
 			},
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			w := httptest.NewRecorder()
-			r := httptest.NewRequest("GET", tt.path, strings.NewReader(tt.body))
-			server.getRecipe(w, r)
-
-			if w.Code != tt.want.code || w.Body.String() != tt.want.body {
-				t.Errorf("getRecipe() = %v, want %v", response{code: w.Code, body: w.Body.String()}, tt.want)
-			}
-		})
-	}
-}
-
-func TestHttpServer_findRecipes(t *testing.T) {
-	server, _ := NewHttpServer(1234, "1234", NewMockDB())
-
-	type response struct {
-		code int
-		body string
-	}
-
-	tests := []struct {
-		name string
-		path string
-		body string
-		want response
-	}{
 		{
-			name: "1",
-			path: "/recipes?ingredients=Gruyere,Emmental",
+			name: "5",
+			path: "/recipe/Greek%20Salat",
+			want: response{
+				code: http.StatusNotFound,
+				body: `{"error":"recipe (Greek Salat) not found","suggestions":["Greek Salad"]}`,
+			},
+		},
+		{
+			name: "6",
+			path: "/recipe/greek%20salat?fuzzy=true",
 			want: response{
 				code: http.StatusOK,
-				body: `{"recipes":[{"name":"Cheese Fondue","ingredients":["Gruyere","Emmental"]}]}`,
+				body: `{"name":"Greek Salad","ingredients":["Feta","Tomato","Cucumber"],"structuredIngredients":[{"name":"Feta"},{"name":"Tomato"},{"name":"Cucumber"}]}`,
 			},
 		},
 		{
-			name: "2",
-			path: "/recipes?ingredients=Emmental,Gruyere",
+			name: "7",
+			path: "/recipe/Pizza?fuzzy=true",
+			want: response{
+				code: http.StatusNotFound,
+				body: `{"error":"recipe (Pizza) not found","suggestions":[]}`,
+			},
+		},
+		{
+			name: "8",
+			path: "/recipe/BLT?fuzzy=maybe",
+			want: response{
+				code: http.StatusBadRequest,
+				body: "invalid fuzzy (maybe)",
+			},
+		},
+		{
+			name: "9",
+			path: "/recipe/Tomato%20Toast",
 			want: response{
 				code: http.StatusOK,
-				body: `{"recipes":[{"name":"Cheese Fondue","ingredients":["Gruyere","Emmental"]}]}`,
+				body: `{"name":"Tomato Toast","ingredients":["Bread","Caprese Salad","Butter"],"structuredIngredients":[{"name":"Bread","quantity":2,"unit":"slices"},{"name":"Caprese Salad","component":true},{"name":"Butter","quantity":10,"unit":"g"}],"servings":2}`,
 			},
 		},
 		{
-			name: "3",
-			path: "/recipes?ingredients=Tomato",
+			name: "10",
+			path: "/recipe/Tomato%20Toast?expand=true",
 			want: response{
 				code: http.StatusOK,
-				body: `{"recipes":[{"name":"BLT","ingredients":["Tomato","Bacon","Lettuce"]},{"name":"Caprese Salad","ingredients":["Mozzarella","Tomato"]},{"name":"Greek Salad","ingredients":["Feta","Tomato","Cucumber"]},{"name":"Meatballs","ingredients":["Ground Beef","Tomato"]},{"name":"SpagBol","ingredients":["Spaghetti","Ground Beef","Tomato"]}]}`,
+				body: `{"name":"Tomato Toast","ingredients":["Bread","Mozzarella","Tomato","Butter"],"structuredIngredients":[{"name":"Bread","quantity":2,"unit":"slices"},{"name":"Mozzarella"},{"name":"Tomato"},{"name":"Butter","quantity":10,"unit":"g"}],"servings":2}`,
 			},
 		},
 		{
-			name: "4",
-			path: "/recipes?ingredients=Tomato,Onion",
+			name: "11",
+			path: "/recipe/BLT?expand=maybe",
+			want: response{
+				code: http.StatusBadRequest,
+				body: "invalid expand (maybe)",
+			},
+		},
+		{
+			name: "12",
+			path: "/recipe/Tomato%20Toast?servings=3",
 			want: response{
 				code: http.StatusOK,
-				body: `{"recipes":[]}`,
+				body: `{"name":"Tomato Toast","ingredients":["Bread","Caprese Salad","Butter"],"structuredIngredients":[{"name":"Bread","quantity":3,"unit":"slices"},{"name":"Caprese Salad","component":true},{"name":"Butter","quantity":15,"unit":"g"}],"servings":3}`,
 			},
 		},
 		{
-			name: "5",
-			path: "/recipes",
+			name: "13",
+			path: "/recipe/Tomato%20Toast?units=imperial",
+			want: response{
+				code: http.StatusOK,
+				body: `{"name":"Tomato Toast","ingredients":["Bread","Caprese Salad","Butter"],"structuredIngredients":[{"name":"Bread","quantity":2,"unit":"slices"},{"name":"Caprese Salad","component":true},{"name":"Butter","quantity":0.375,"unit":"oz"}],"servings":2}`,
+			},
+		},
+		{
+			name: "14",
+			path: "/recipe/BLT?servings=2",
 			want: response{
 				code: http.StatusBadRequest,
-				body: `no ingredients specified`,
+				body: "recipe (BLT) does not say how many servings it makes",
 			},
 		},
 		{
-			name: "6",
-			path: "/recipes?ingredients=Tomato?Ingredients=Onion",
+			name: "15",
+			path: "/recipe/BLT?servings=-1",
 			want: response{
 				code: http.StatusBadRequest,
+				body: "invalid servings (-1)",
 			},
 		},
 		{
-			name: "7",
-			path: "/recipes?ingredients=DBError",
+			name: "16",
+			path: "/recipe/BLT?units=nautical",
 			want: response{
-				code: http.StatusInternalServerError,
-				body: "error reading recipes from database",
+				code: http.StatusBadRequest,
+				body: "invalid units (nautical)",
 			},
 		},
 	}
@@ -378,60 +612,71 @@ func TestHttpServer_findRecipes(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest("GET", tt.path, strings.NewReader(tt.body))
-			server.findRecipes(w, r)
+			server.getRecipe(w, r)
 
 			if w.Code != tt.want.code || w.Body.String() != tt.want.body {
-				t.Errorf("findRecipes() = %v, want %v", response{code: w.Code, body: w.Body.String()}, tt.want)
+				t.Errorf("getRecipe() = %v, want %v", response{code: w.Code, body: w.Body.String()}, tt.want)
 			}
 		})
 	}
 }
 
-func TestHttpServer_tracer(t *testing.T) {
-	server, _ := NewHttpServer(1234, "1234", NewMockDB())
+func TestHttpServer_deleteRecipe(t *testing.T) {
+	server, _ := NewHttpServer(1234, "1234", "", NewMockDB())
 
-	type args struct {
-		originalHandler http.Handler
+	type response struct {
+		code int
+		body string
 	}
+
 	tests := []struct {
-		name      string
-		s         *HttpServer
-		args      args
-		wantRegex string
+		name string
+		path string
+		want response
 	}{
 		{
-			name:      "1",
-			s:         &server,
-			args:      args{originalHandler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})},
-			wantRegex: "GET /tracertest 0s",
+			name: "1",
+			path: "/recipe/Cheese%20Fondue",
+			want: response{
+				code: http.StatusOK,
+			},
 		},
 		{
-			name:      "2",
-			s:         &server,
-			args:      args{originalHandler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { time.Sleep(5 * time.Second) })},
-			wantRegex: `GET /tracertest 5(.\d+)?s`,
+			name: "2",
+			path: "/recipe/Pizza",
+			want: response{
+				code: http.StatusNotFound,
+			},
+		},
+		{
+			name: "3",
+			path: "/recipe/DBError",
+			want: response{
+				code: http.StatusInternalServerError,
+				body: "error deleting recipe from database",
+			},
 		},
 	}
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			w := httptest.NewRecorder()
-			r := httptest.NewRequest("GET", "/tracertest", nil)
+			r := httptest.NewRequest("DELETE", tt.path, nil)
+			server.deleteRecipe(w, r)
 
-			got := captureOutput(func() { server.tracer(tt.args.originalHandler).ServeHTTP(w, r) })
-
-			match, err := regexp.MatchString(tt.wantRegex, got)
-			if err != nil {
-				t.Errorf("HttpServer.tracer(): %v", err)
-			}
-			if !match {
-				t.Errorf("HttpServer.tracer() = %v, want %v", got, tt.wantRegex)
+			if w.Code != tt.want.code || w.Body.String() != tt.want.body {
+				t.Errorf("deleteRecipe() = %v, want %v", response{code: w.Code, body: w.Body.String()}, tt.want)
 			}
 		})
 	}
 }
 
-func TestHttpServer_auth(t *testing.T) {
-	server, _ := NewHttpServer(1234, "1234", NewMockDB())
+func TestHttpServer_findRecipes(t *testing.T) {
+	server, _ := NewHttpServer(1234, "1234", "", NewMockDB())
+	if err := persistence.SetTaxonomy([]persistence.Kind{{Ingredient: "Mozzarella", Parent: "Cheese"}, {Ingredient: "Feta", Parent: "Cheese"}}); err != nil {
+		t.Fatalf("SetTaxonomy() error = %v", err)
+	}
+	t.Cleanup(func() { persistence.SetTaxonomy(nil) })
 
 	type response struct {
 		code int
@@ -440,27 +685,789 @@ func TestHttpServer_auth(t *testing.T) {
 
 	tests := []struct {
 		name string
-		key  string
+		path string
+		body string
 		want response
 	}{
 		{
 			name: "1",
-			key:  "1234",
+			path: "/recipes?ingredients=Gruyere,Emmental",
 			want: response{
 				code: http.StatusOK,
+				body: `{"recipes":[{"name":"Cheese Fondue","ingredients":["Gruyere","Emmental"],"structuredIngredients":[{"name":"Gruyere"},{"name":"Emmental"}]}],"matches":[{"recipe":"Cheese Fondue","missingIngredients":[],"matchedIngredients":["Gruyere","Emmental"],"matched":2,"missing":0,"extra":0,"score":1}]}`,
 			},
 		},
 		{
 			name: "2",
+			path: "/recipes?ingredients=Emmental,Gruyere",
 			want: response{
-				code: http.StatusUnauthorized,
+				code: http.StatusOK,
+				body: `{"recipes":[{"name":"Cheese Fondue","ingredients":["Gruyere","Emmental"],"structuredIngredients":[{"name":"Gruyere"},{"name":"Emmental"}]}],"matches":[{"recipe":"Cheese Fondue","missingIngredients":[],"matchedIngredients":["Gruyere","Emmental"],"matched":2,"missing":0,"extra":0,"score":1}]}`,
 			},
 		},
 		{
 			name: "3",
-			key:  "4321",
+			path: "/recipes?ingredients=Tomato",
 			want: response{
-				code: http.StatusUnauthorized,
+				code: http.StatusOK,
+				body: `{"recipes":[{"name":"Caprese Salad","ingredients":["Mozzarella","Tomato"],"structuredIngredients":[{"name":"Mozzarella"},{"name":"Tomato"}]},{"name":"Meatballs","ingredients":["Ground Beef","Tomato"],"structuredIngredients":[{"name":"Ground Beef"},{"name":"Tomato"}]},{"name":"BLT","ingredients":["Tomato","Bacon","Lettuce"],"structuredIngredients":[{"name":"Tomato"},{"name":"Bacon"},{"name":"Lettuce"}]},{"name":"Greek Salad","ingredients":["Feta","Tomato","Cucumber"],"structuredIngredients":[{"name":"Feta"},{"name":"Tomato"},{"name":"Cucumber"}]},{"name":"SpagBol","ingredients":["Spaghetti","Ground Beef","Tomato"],"structuredIngredients":[{"name":"Spaghetti"},{"name":"Ground Beef"},{"name":"Tomato"}]}],"matches":[{"recipe":"Caprese Salad","missingIngredients":["Mozzarella"],"matchedIngredients":["Tomato"],"matched":1,"missing":1,"extra":0,"score":0.5},{"recipe":"Meatballs","missingIngredients":["Ground Beef"],"matchedIngredients":["Tomato"],"matched":1,"missing":1,"extra":0,"score":0.5},{"recipe":"BLT","missingIngredients":["Bacon","Lettuce"],"matchedIngredients":["Tomato"],"matched":1,"missing":2,"extra":0,"score":0.3333333333333333},{"recipe":"Greek Salad","missingIngredients":["Feta","Cucumber"],"matchedIngredients":["Tomato"],"matched":1,"missing":2,"extra":0,"score":0.3333333333333333},{"recipe":"SpagBol","missingIngredients":["Spaghetti","Ground Beef"],"matchedIngredients":["Tomato"],"matched":1,"missing":2,"extra":0,"score":0.3333333333333333}]}`,
+			},
+		},
+		{
+			name: "4",
+			path: "/recipes?ingredients=Tomato,Onion",
+			want: response{
+				code: http.StatusOK,
+				body: `{"recipes":[]}`,
+			},
+		},
+		{
+			name: "5",
+			path: "/recipes",
+			want: response{
+				code: http.StatusBadRequest,
+				body: `no ingredients specified`,
+			},
+		},
+		{
+			name: "6",
+			path: "/recipes?ingredients=Tomato?Ingredients=Onion",
+			want: response{
+				code: http.StatusBadRequest,
+			},
+		},
+		{
+			name: "7",
+			path: "/recipes?ingredients=DBError",
+			want: response{
+				code: http.StatusInternalServerError,
+				body: "error reading recipes from database",
+			},
+		},
+		{
+			name: "8",
+			path: "/recipes?ingredients=Tomato,Mozzarella,Ground%20Beef&mode=subset",
+			want: response{
+				code: http.StatusOK,
+				body: `{"recipes":[{"name":"Caprese Salad","ingredients":["Mozzarella","Tomato"],"structuredIngredients":[{"name":"Mozzarella"},{"name":"Tomato"}]},{"name":"Meatballs","ingredients":["Ground Beef","Tomato"],"structuredIngredients":[{"name":"Ground Beef"},{"name":"Tomato"}]}],"matches":[{"recipe":"Caprese Salad","missingIngredients":[],"matchedIngredients":["Mozzarella","Tomato"],"matched":2,"missing":0,"extra":1,"score":0.6666666666666666},{"recipe":"Meatballs","missingIngredients":[],"matchedIngredients":["Ground Beef","Tomato"],"matched":2,"missing":0,"extra":1,"score":0.6666666666666666}]}`,
+			},
+		},
+		{
+			name: "9",
+			path: "/recipes?ingredients=Mozzarella,Macaroni&mode=MATCH_SUBSET&max_missing=1",
+			want: response{
+				code: http.StatusOK,
+				body: `{"recipes":[{"name":"Mac \u0026 Cheese","ingredients":["Mozzarella","Macaroni"],"structuredIngredients":[{"name":"Mozzarella"},{"name":"Macaroni"}]},{"name":"Caprese Salad","ingredients":["Mozzarella","Tomato"],"structuredIngredients":[{"name":"Mozzarella"},{"name":"Tomato"}]}],"matches":[{"recipe":"Mac \u0026 Cheese","missingIngredients":[],"matchedIngredients":["Mozzarella","Macaroni"],"matched":2,"missing":0,"extra":0,"score":1},{"recipe":"Caprese Salad","missingIngredients":["Tomato"],"matchedIngredients":["Mozzarella"],"matched":1,"missing":1,"extra":1,"score":0.3333333333333333}]}`,
+			},
+		},
+		{
+			name: "10",
+			path: "/recipes?ingredients=Gruyere,Macaroni&mode=any",
+			want: response{
+				code: http.StatusOK,
+				body: `{"recipes":[{"name":"Cheese Fondue","ingredients":["Gruyere","Emmental"],"structuredIngredients":[{"name":"Gruyere"},{"name":"Emmental"}]},{"name":"Mac \u0026 Cheese","ingredients":["Mozzarella","Macaroni"],"structuredIngredients":[{"name":"Mozzarella"},{"name":"Macaroni"}]}],"matches":[{"recipe":"Cheese Fondue","missingIngredients":["Emmental"],"matchedIngredients":["Gruyere"],"matched":1,"missing":1,"extra":1,"score":0.3333333333333333},{"recipe":"Mac \u0026 Cheese","missingIngredients":["Mozzarella"],"matchedIngredients":["Macaroni"],"matched":1,"missing":1,"extra":1,"score":0.3333333333333333}]}`,
+			},
+		},
+		{
+			name: "11",
+			path: "/recipes?ingredients=Tomato&mode=some",
+			want: response{
+				code: http.StatusBadRequest,
+				body: "unknown match mode (some)",
+			},
+		},
+		{
+			name: "12",
+			path: "/recipes?ingredients=Tomato&mode=subset&max_missing=-1",
+			want: response{
+				code: http.StatusBadRequest,
+				body: "invalid max missing (-1)",
+			},
+		},
+		{
+			name: "13",
+			path: "/recipes?ingredients=Tomato&exclude=Ground%20Beef,Bacon",
+			want: response{
+				code: http.StatusOK,
+				body: `{"recipes":[{"name":"Caprese Salad","ingredients":["Mozzarella","Tomato"],"structuredIngredients":[{"name":"Mozzarella"},{"name":"Tomato"}]},{"name":"Greek Salad","ingredients":["Feta","Tomato","Cucumber"],"structuredIngredients":[{"name":"Feta"},{"name":"Tomato"},{"name":"Cucumber"}]}],"matches":[{"recipe":"Caprese Salad","missingIngredients":["Mozzarella"],"matchedIngredients":["Tomato"],"matched":1,"missing":1,"extra":0,"score":0.5},{"recipe":"Greek Salad","missingIngredients":["Feta","Cucumber"],"matchedIngredients":["Tomato"],"matched":1,"missing":2,"extra":0,"score":0.3333333333333333}]}`,
+			},
+		},
+		{
+			name: "14",
+			path: "/recipes?exclude=Tomato&ingredients=Mozzarella,Gruyere&mode=any",
+			want: response{
+				code: http.StatusOK,
+				body: `{"recipes":[{"name":"Cheese Fondue","ingredients":["Gruyere","Emmental"],"structuredIngredients":[{"name":"Gruyere"},{"name":"Emmental"}]},{"name":"Mac \u0026 Cheese","ingredients":["Mozzarella","Macaroni"],"structuredIngredients":[{"name":"Mozzarella"},{"name":"Macaroni"}]}],"matches":[{"recipe":"Cheese Fondue","missingIngredients":["Emmental"],"matchedIngredients":["Gruyere"],"matched":1,"missing":1,"extra":1,"score":0.3333333333333333},{"recipe":"Mac \u0026 Cheese","missingIngredients":["Macaroni"],"matchedIngredients":["Mozzarella"],"matched":1,"missing":1,"extra":1,"score":0.3333333333333333}]}`,
+			},
+		},
+		{
+			name: "15",
+			path: "/recipes?q=Tomato%20AND%20(Mozzarella%20OR%20Feta)%20-Bacon",
+			want: response{
+				code: http.StatusOK,
+				body: `{"recipes":[{"name":"Caprese Salad","ingredients":["Mozzarella","Tomato"],"structuredIngredients":[{"name":"Mozzarella"},{"name":"Tomato"}]},{"name":"Greek Salad","ingredients":["Feta","Tomato","Cucumber"],"structuredIngredients":[{"name":"Feta"},{"name":"Tomato"},{"name":"Cucumber"}]}],"matches":[{"recipe":"Caprese Salad","missingIngredients":[],"matchedIngredients":["Mozzarella","Tomato"],"matched":2,"missing":0,"extra":1,"score":0.6666666666666666},{"recipe":"Greek Salad","missingIngredients":["Cucumber"],"matchedIngredients":["Feta","Tomato"],"matched":2,"missing":1,"extra":1,"score":0.5}]}`,
+			},
+		},
+		{
+			name: "16",
+			path: "/recipes?ingredients=Tomato&q=NOT+Ground+Beef",
+			want: response{
+				code: http.StatusOK,
+				body: `{"recipes":[{"name":"Caprese Salad","ingredients":["Mozzarella","Tomato"],"structuredIngredients":[{"name":"Mozzarella"},{"name":"Tomato"}]},{"name":"BLT","ingredients":["Tomato","Bacon","Lettuce"],"structuredIngredients":[{"name":"Tomato"},{"name":"Bacon"},{"name":"Lettuce"}]},{"name":"Greek Salad","ingredients":["Feta","Tomato","Cucumber"],"structuredIngredients":[{"name":"Feta"},{"name":"Tomato"},{"name":"Cucumber"}]}],"matches":[{"recipe":"Caprese Salad","missingIngredients":["Mozzarella"],"matchedIngredients":["Tomato"],"matched":1,"missing":1,"extra":0,"score":0.5},{"recipe":"BLT","missingIngredients":["Bacon","Lettuce"],"matchedIngredients":["Tomato"],"matched":1,"missing":2,"extra":0,"score":0.3333333333333333},{"recipe":"Greek Salad","missingIngredients":["Feta","Cucumber"],"matchedIngredients":["Tomato"],"matched":1,"missing":2,"extra":0,"score":0.3333333333333333}]}`,
+			},
+		},
+		{
+			name: "17",
+			path: "/recipes?ingredients=Tomato&q=Mozzarella%20OR",
+			want: response{
+				code: http.StatusBadRequest,
+				body: "invalid query at position 14: expected an ingredient, found end of query",
+			},
+		},
+		{
+			name: "18",
+			path: "/recipes?ingredients=mozzarella,%20TOMATOES%20",
+			want: response{
+				code: http.StatusOK,
+				body: `{"recipes":[{"name":"Caprese Salad","ingredients":["Mozzarella","Tomato"],"structuredIngredients":[{"name":"Mozzarella"},{"name":"Tomato"}]}],"matches":[{"recipe":"Caprese Salad","missingIngredients":[],"matchedIngredients":["Mozzarella","Tomato"],"matched":2,"missing":0,"extra":0,"score":1}]}`,
+			},
+		},
+		{
+			name: "19",
+			path: "/recipes?ingredients=Tomatoe,Mozarella",
+			want: response{
+				code: http.StatusOK,
+				body: `{"recipes":[],"suggestions":[{"name":"Tomatoe","suggestions":["Tomato"]},{"name":"Mozarella","suggestions":["Mozzarella"]}]}`,
+			},
+		},
+		{
+			name: "20",
+			path: "/recipes?ingredients=Tomatoe,Mozarella&fuzzy=true",
+			want: response{
+				code: http.StatusOK,
+				body: `{"recipes":[{"name":"Caprese Salad","ingredients":["Mozzarella","Tomato"],"structuredIngredients":[{"name":"Mozzarella"},{"name":"Tomato"}]}],"matches":[{"recipe":"Caprese Salad","missingIngredients":[],"matchedIngredients":["Mozzarella","Tomato"],"matched":2,"missing":0,"extra":0,"score":1}],"suggestions":[{"name":"Tomatoe","suggestions":["Tomato"]},{"name":"Mozarella","suggestions":["Mozzarella"]}]}`,
+			},
+		},
+		{
+			name: "21",
+			path: "/recipes?ingredients=Tomato&q=-Bacn&fuzzy=true&mode=any",
+			want: response{
+				code: http.StatusOK,
+				body: `{"recipes":[{"name":"Caprese Salad","ingredients":["Mozzarella","Tomato"],"structuredIngredients":[{"name":"Mozzarella"},{"name":"Tomato"}]},{"name":"Meatballs","ingredients":["Ground Beef","Tomato"],"structuredIngredients":[{"name":"Ground Beef"},{"name":"Tomato"}]},{"name":"Greek Salad","ingredients":["Feta","Tomato","Cucumber"],"structuredIngredients":[{"name":"Feta"},{"name":"Tomato"},{"name":"Cucumber"}]},{"name":"SpagBol","ingredients":["Spaghetti","Ground Beef","Tomato"],"structuredIngredients":[{"name":"Spaghetti"},{"name":"Ground Beef"},{"name":"Tomato"}]}],"matches":[{"recipe":"Caprese Salad","missingIngredients":["Mozzarella"],"matchedIngredients":["Tomato"],"matched":1,"missing":1,"extra":0,"score":0.5},{"recipe":"Meatballs","missingIngredients":["Ground Beef"],"matchedIngredients":["Tomato"],"matched":1,"missing":1,"extra":0,"score":0.5},{"recipe":"Greek Salad","missingIngredients":["Feta","Cucumber"],"matchedIngredients":["Tomato"],"matched":1,"missing":2,"extra":0,"score":0.3333333333333333},{"recipe":"SpagBol","missingIngredients":["Spaghetti","Ground Beef"],"matchedIngredients":["Tomato"],"matched":1,"missing":2,"extra":0,"score":0.3333333333333333}],"suggestions":[{"name":"Bacn","suggestions":["Bacon"]}]}`,
+			},
+		},
+		{
+			name: "22",
+			path: "/recipes?ingredients=Tomato&fuzzy=often",
+			want: response{
+				code: http.StatusBadRequest,
+				body: "invalid fuzzy (often)",
+			},
+		},
+		{
+			name: "23",
+			path: "/recipes?ingredients=Cheese,Tomato&expand=true",
+			want: response{
+				code: http.StatusOK,
+				body: `{"recipes":[{"name":"Caprese Salad","ingredients":["Mozzarella","Tomato"],"structuredIngredients":[{"name":"Mozzarella"},{"name":"Tomato"}]},{"name":"Greek Salad","ingredients":["Feta","Tomato","Cucumber"],"structuredIngredients":[{"name":"Feta"},{"name":"Tomato"},{"name":"Cucumber"}]}],"matches":[{"recipe":"Caprese Salad","missingIngredients":[],"matchedIngredients":["Mozzarella","Tomato"],"matched":2,"missing":0,"extra":0,"score":1},{"recipe":"Greek Salad","missingIngredients":["Cucumber"],"matchedIngredients":["Feta","Tomato"],"matched":2,"missing":1,"extra":0,"score":0.6666666666666666}]}`,
+			},
+		},
+		{
+			name: "24",
+			path: "/recipes?ingredients=Cheese,Tomato",
+			want: response{
+				code: http.StatusOK,
+				body: `{"recipes":[]}`,
+			},
+		},
+		{
+			name: "25",
+			path: "/recipes?ingredients=Cheese&expand=lots",
+			want: response{
+				code: http.StatusBadRequest,
+				body: "invalid expand (lots)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", tt.path, strings.NewReader(tt.body))
+			server.findRecipes(w, r)
+
+			if w.Code != tt.want.code || w.Body.String() != tt.want.body {
+				t.Errorf("findRecipes() = %v, want %v", response{code: w.Code, body: w.Body.String()}, tt.want)
+			}
+		})
+	}
+}
+
+func TestHttpServer_listRecipes(t *testing.T) {
+	server, _ := NewHttpServer(1234, "1234", "", NewMockDB())
+
+	type response struct {
+		code int
+		body string
+	}
+
+	tests := []struct {
+		name string
+		path string
+		want response
+	}{
+		{
+			name: "1",
+			path: "/recipes?page_size=2",
+			want: response{
+				code: http.StatusOK,
+				body: `{"recipes":[{"name":"BLT","ingredients":["Tomato","Bacon","Lettuce"],"structuredIngredients":[{"name":"Tomato"},{"name":"Bacon"},{"name":"Lettuce"}]},{"name":"Caprese Salad","ingredients":["Mozzarella","Tomato"],"structuredIngredients":[{"name":"Mozzarella"},{"name":"Tomato"}]}],"nextPageToken":"` + persistence.EncodePageToken("Caprese Salad") + `"}`,
+			},
+		},
+		{
+			name: "2",
+			path: "/recipes?page_size=2&page_token=" + persistence.EncodePageToken("Meatballs"),
+			want: response{
+				code: http.StatusOK,
+				body: `{"recipes":[{"name":"SpagBol","ingredients":["Spaghetti","Ground Beef","Tomato"],"structuredIngredients":[{"name":"Spaghetti"},{"name":"Ground Beef"},{"name":"Tomato"}]}]}`,
+			},
+		},
+		{
+			name: "3",
+			path: "/recipes?page_token=" + persistence.EncodePageToken("SpagBol"),
+			want: response{
+				code: http.StatusOK,
+				body: `{"recipes":[]}`,
+			},
+		},
+		{
+			name: "4",
+			path: "/recipes?page_size=abc",
+			want: response{
+				code: http.StatusBadRequest,
+				body: "invalid page size (abc)",
+			},
+		},
+		{
+			name: "5",
+			path: "/recipes?page_size=-1",
+			want: response{
+				code: http.StatusBadRequest,
+				body: "invalid page size (-1)",
+			},
+		},
+		{
+			name: "6",
+			path: "/recipes?page_token=abc!",
+			want: response{
+				code: http.StatusBadRequest,
+				body: "invalid page token (abc!)",
+			},
+		},
+		{
+			name: "7",
+			path: "/recipes?page_token=" + persistence.EncodePageToken("DBError"),
+			want: response{
+				code: http.StatusInternalServerError,
+				body: "error reading recipes from database",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", tt.path, nil)
+			server.listRecipes(w, r)
+
+			if w.Code != tt.want.code || w.Body.String() != tt.want.body {
+				t.Errorf("listRecipes() = %v, want %v", response{code: w.Code, body: w.Body.String()}, tt.want)
+			}
+		})
+	}
+}
+func TestHttpServer_searchRecipesByName(t *testing.T) {
+	server, _ := NewHttpServer(1234, "1234", "", NewMockDB())
+
+	type response struct {
+		code int
+		body string
+	}
+
+	tests := []struct {
+		name string
+		path string
+		want response
+	}{
+		{
+			name: "1",
+			path: "/recipes:search?name=salad&match=substring",
+			want: response{
+				code: http.StatusOK,
+				body: `{"recipes":[{"name":"Caprese Salad","ingredients":["Mozzarella","Tomato"],"structuredIngredients":[{"name":"Mozzarella"},{"name":"Tomato"}]},{"name":"Greek Salad","ingredients":["Feta","Tomato","Cucumber"],"structuredIngredients":[{"name":"Feta"},{"name":"Tomato"},{"name":"Cucumber"}]}]}`,
+			},
+		},
+		{
+			name: "2",
+			path: "/recipes:search?name=SALAD&match=NAME_SUBSTRING&page_size=1",
+			want: response{
+				code: http.StatusOK,
+				body: `{"recipes":[{"name":"Caprese Salad","ingredients":["Mozzarella","Tomato"],"structuredIngredients":[{"name":"Mozzarella"},{"name":"Tomato"}]}],"nextPageToken":"` + persistence.EncodePageToken("Caprese Salad") + `"}`,
+			},
+		},
+		{
+			name: "3",
+			path: "/recipes:search?name=salad&match=substring&page_token=" + persistence.EncodePageToken("Caprese Salad"),
+			want: response{
+				code: http.StatusOK,
+				body: `{"recipes":[{"name":"Greek Salad","ingredients":["Feta","Tomato","Cucumber"],"structuredIngredients":[{"name":"Feta"},{"name":"Tomato"},{"name":"Cucumber"}]}]}`,
+			},
+		},
+		{
+			name: "4",
+			path: "/recipes:search?name=mac",
+			want: response{
+				code: http.StatusOK,
+				body: `{"recipes":[{"name":"Mac \u0026 Cheese","ingredients":["Mozzarella","Macaroni"],"structuredIngredients":[{"name":"Mozzarella"},{"name":"Macaroni"}]}]}`,
+			},
+		},
+		{
+			name: "5",
+			path: "/recipes:search?name=salad&match=prefix",
+			want: response{
+				code: http.StatusOK,
+				body: `{"recipes":[]}`,
+			},
+		},
+		{
+			name: "6",
+			path: "/recipes:search?name=+",
+			want: response{
+				code: http.StatusBadRequest,
+				body: "no name specified",
+			},
+		},
+		{
+			name: "7",
+			path: "/recipes:search?name=salad&match=soundex",
+			want: response{
+				code: http.StatusBadRequest,
+				body: "unknown name match (soundex)",
+			},
+		},
+		{
+			name: "8",
+			path: "/recipes:search?name=salad&page_size=abc",
+			want: response{
+				code: http.StatusBadRequest,
+				body: "invalid page size (abc)",
+			},
+		},
+		{
+			name: "9",
+			path: "/recipes:search?name=salad&page_token=abc!",
+			want: response{
+				code: http.StatusBadRequest,
+				body: "invalid page token (abc!)",
+			},
+		},
+		{
+			name: "10",
+			path: "/recipes:search?name=DBError",
+			want: response{
+				code: http.StatusInternalServerError,
+				body: "error searching recipes in database",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", tt.path, nil)
+			server.searchRecipesByName(w, r)
+
+			if w.Code != tt.want.code || w.Body.String() != tt.want.body {
+				t.Errorf("searchRecipesByName() = %v, want %v", response{code: w.Code, body: w.Body.String()}, tt.want)
+			}
+		})
+	}
+}
+
+func TestHttpServer_listIngredients(t *testing.T) {
+	server, _ := NewHttpServer(1234, "1234", "", NewMockDB())
+
+	w := httptest.NewRecorder()
+	server.listIngredients(w, httptest.NewRequest("GET", "/ingredients", nil))
+
+	want := `{"ingredients":[{"name":"Bacon","recipes":1},{"name":"Cucumber","recipes":1},{"name":"Emmental","recipes":1},{"name":"Feta","recipes":1},{"name":"Ground Beef","recipes":2},{"name":"Gruyere","recipes":1},{"name":"Lettuce","recipes":1},{"name":"Macaroni","recipes":1},{"name":"Mozzarella","recipes":2},{"name":"Spaghetti","recipes":1},{"name":"Tomato","recipes":5}]}`
+	if w.Code != http.StatusOK || w.Body.String() != want {
+		t.Errorf("listIngredients() = %v, %v, want %v, %v", w.Code, w.Body.String(), http.StatusOK, want)
+	}
+}
+
+func TestHttpServer_getStats(t *testing.T) {
+	server, _ := NewHttpServer(1234, "1234", "", NewMockDB())
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	type response struct {
+		code int
+		body string
+	}
+
+	tests := []struct {
+		name string
+		r    *http.Request
+		want response
+	}{
+		{
+			name: "1",
+			r:    httptest.NewRequest("GET", "/stats", nil),
+			want: response{code: http.StatusOK, body: `{"recipes":7,"ingredients":11,"mostUsed":[{"name":"Tomato","recipes":5},{"name":"Ground Beef","recipes":2},{"name":"Mozzarella","recipes":2},{"name":"Bacon","recipes":1},{"name":"Cucumber","recipes":1}],"leastUsed":[{"name":"Bacon","recipes":1},{"name":"Cucumber","recipes":1},{"name":"Emmental","recipes":1},{"name":"Feta","recipes":1},{"name":"Gruyere","recipes":1}],"averageIngredients":2.4285714285714284,"isolatedRecipes":["Cheese Fondue"]}`},
+		},
+		{
+			name: "2",
+			r:    httptest.NewRequest("GET", "/stats", nil).WithContext(cancelled),
+			want: response{code: http.StatusInternalServerError, body: "error reading stats from database"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			server.getStats(w, tt.r)
+			if w.Code != tt.want.code || w.Body.String() != tt.want.body {
+				t.Errorf("getStats() = %v, %v, want %v, %v", w.Code, w.Body.String(), tt.want.code, tt.want.body)
+			}
+		})
+	}
+}
+
+func TestHttpServer_completeIngredient(t *testing.T) {
+	server, _ := NewHttpServer(1234, "1234", "", NewMockDB())
+
+	type response struct {
+		code int
+		body string
+	}
+
+	tests := []struct {
+		name string
+		path string
+		want response
+	}{
+		{
+			name: "1",
+			path: "/ingredients:complete?prefix=m",
+			want: response{code: http.StatusOK, body: `{"ingredients":[{"name":"Mozzarella","recipes":2},{"name":"Macaroni","recipes":1}]}`},
+		},
+		{
+			name: "2",
+			path: "/ingredients:complete?limit=2",
+			want: response{code: http.StatusOK, body: `{"ingredients":[{"name":"Tomato","recipes":5},{"name":"Ground Beef","recipes":2}]}`},
+		},
+		{
+			name: "3",
+			path: "/ingredients:complete?prefix=Ground%20B",
+			want: response{code: http.StatusOK, body: `{"ingredients":[{"name":"Ground Beef","recipes":2}]}`},
+		},
+		{
+			name: "4",
+			path: "/ingredients:complete?prefix=x",
+			want: response{code: http.StatusOK, body: `{"ingredients":[]}`},
+		},
+		{
+			name: "5",
+			path: "/ingredients:complete?prefix=m&limit=lots",
+			want: response{code: http.StatusBadRequest, body: "invalid limit (lots)"},
+		},
+		{
+			name: "6",
+			path: "/ingredients:complete?prefix=m&limit=-1",
+			want: response{code: http.StatusBadRequest, body: "invalid limit (-1)"},
+		},
+		{
+			name: "7",
+			path: "/ingredients:complete?prefix=DBError",
+			want: response{code: http.StatusInternalServerError, body: "error reading ingredients from database"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			server.completeIngredient(w, httptest.NewRequest("GET", tt.path, nil))
+
+			if w.Code != tt.want.code || w.Body.String() != tt.want.body {
+				t.Errorf("completeIngredient() = %v, want %v", response{code: w.Code, body: w.Body.String()}, tt.want)
+			}
+		})
+	}
+}
+
+func TestHttpServer_similarRecipes(t *testing.T) {
+	server, _ := NewHttpServer(1234, "1234", "", NewMockDB())
+
+	type response struct {
+		code int
+		body string
+	}
+
+	tests := []struct {
+		name string
+		path string
+		want response
+	}{
+		{
+			name: "1",
+			path: "/recipes:similar?name=SpagBol",
+			want: response{code: http.StatusOK, body: `{"recipes":[{"name":"Meatballs","similarity":0.6666666666666666,"sharedIngredients":["Ground Beef","Tomato"]},{"name":"Caprese Salad","similarity":0.25,"sharedIngredients":["Tomato"]},{"name":"BLT","similarity":0.2,"sharedIngredients":["Tomato"]},{"name":"Greek Salad","similarity":0.2,"sharedIngredients":["Tomato"]}]}`},
+		},
+		{
+			name: "2",
+			path: "/recipes:similar?name=Mac%20%26%20Cheese&limit=1",
+			want: response{code: http.StatusOK, body: `{"recipes":[{"name":"Caprese Salad","similarity":0.3333333333333333,"sharedIngredients":["Mozzarella"]}]}`},
+		},
+		{
+			name: "3",
+			path: "/recipes:similar?name=Cheese%20Fondue",
+			want: response{code: http.StatusOK, body: `{"recipes":[]}`},
+		},
+		{
+			name: "4",
+			path: "/recipes:similar",
+			want: response{code: http.StatusBadRequest, body: "no name specified"},
+		},
+		{
+			name: "5",
+			path: "/recipes:similar?name=SpagBol&limit=x",
+			want: response{code: http.StatusBadRequest, body: "invalid limit (x)"},
+		},
+		{
+			name: "6",
+			path: "/recipes:similar?name=SpagBol&limit=-1",
+			want: response{code: http.StatusBadRequest, body: "invalid limit (-1)"},
+		},
+		{
+			name: "7",
+			path: "/recipes:similar?name=Spag%20Bol",
+			want: response{code: http.StatusNotFound, body: `{"error":"recipe (Spag Bol) not found","suggestions":["SpagBol"]}`},
+		},
+		{
+			name: "8",
+			path: "/recipes:similar?name=DBError",
+			want: response{code: http.StatusInternalServerError, body: "error reading recipes from database"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			server.similarRecipes(w, httptest.NewRequest("GET", tt.path, nil))
+
+			if w.Code != tt.want.code || w.Body.String() != tt.want.body {
+				t.Errorf("similarRecipes() = %v, want %v", response{code: w.Code, body: w.Body.String()}, tt.want)
+			}
+		})
+	}
+}
+
+func TestHttpServer_getTaxonomy(t *testing.T) {
+	server, _ := NewHttpServer(1234, "1234", "", NewMockDB())
+	if err := persistence.SetTaxonomy([]persistence.Kind{{Ingredient: "Mozzarella", Parent: "Cheese"}, {Ingredient: "Cheese", Parent: "Dairy"}}); err != nil {
+		t.Fatalf("SetTaxonomy() error = %v", err)
+	}
+	t.Cleanup(func() { persistence.SetTaxonomy(nil) })
+
+	w := httptest.NewRecorder()
+	server.getTaxonomy(w, httptest.NewRequest("GET", "/taxonomy", nil))
+
+	want := `{"kinds":[{"ingredient":"Cheese","parent":"Dairy"},{"ingredient":"Mozzarella","parent":"Cheese"}]}`
+	if w.Code != http.StatusOK || w.Body.String() != want {
+		t.Errorf("getTaxonomy() = %v, %v, want %v, %v", w.Code, w.Body.String(), http.StatusOK, want)
+	}
+}
+
+func TestHttpServer_setIngredientKind(t *testing.T) {
+	server, _ := NewHttpServer(1234, "1234", "5678", NewMockDB())
+	disabled, _ := NewHttpServer(1234, "1234", "", NewMockDB())
+	if err := persistence.SetTaxonomy([]persistence.Kind{{Ingredient: "Mozzarella", Parent: "Cheese"}}); err != nil {
+		t.Fatalf("SetTaxonomy() error = %v", err)
+	}
+	t.Cleanup(func() { persistence.SetTaxonomy(nil) })
+
+	type response struct {
+		code int
+		body string
+	}
+
+	tests := []struct {
+		name         string
+		s            *HttpServer
+		path         string
+		body         string
+		adminKey     string
+		want         response
+		wantTaxonomy []persistence.Kind
+	}{
+		{
+			name:         "1",
+			s:            &server,
+			path:         "/taxonomy/Cheese",
+			body:         `{"parent":"Dairy"}`,
+			adminKey:     "5678",
+			want:         response{code: http.StatusOK},
+			wantTaxonomy: []persistence.Kind{{Ingredient: "Cheese", Parent: "Dairy"}, {Ingredient: "Mozzarella", Parent: "Cheese"}},
+		},
+		{
+			name:         "2",
+			s:            &server,
+			path:         "/taxonomy/Dairy",
+			body:         `{"parent":"Mozzarella"}`,
+			adminKey:     "5678",
+			want:         response{code: http.StatusBadRequest, body: "taxonomy: an ingredient cannot be a kind of itself (Mozzarella is a Dairy)"},
+			wantTaxonomy: []persistence.Kind{{Ingredient: "Cheese", Parent: "Dairy"}, {Ingredient: "Mozzarella", Parent: "Cheese"}},
+		},
+		{
+			name:         "3",
+			s:            &server,
+			path:         "/taxonomy/Cheese",
+			body:         `{"parent":`,
+			adminKey:     "5678",
+			want:         response{code: http.StatusBadRequest, body: "error unmarshalling kind"},
+			wantTaxonomy: []persistence.Kind{{Ingredient: "Cheese", Parent: "Dairy"}, {Ingredient: "Mozzarella", Parent: "Cheese"}},
+		},
+		{
+			name:         "4",
+			s:            &server,
+			path:         "/taxonomy/",
+			body:         `{"parent":"Dairy"}`,
+			adminKey:     "5678",
+			want:         response{code: http.StatusBadRequest, body: "no ingredient specified"},
+			wantTaxonomy: []persistence.Kind{{Ingredient: "Cheese", Parent: "Dairy"}, {Ingredient: "Mozzarella", Parent: "Cheese"}},
+		},
+		{
+			name:         "5",
+			s:            &server,
+			path:         "/taxonomy/Mozzarella",
+			body:         `{}`,
+			adminKey:     "5678",
+			want:         response{code: http.StatusOK},
+			wantTaxonomy: []persistence.Kind{{Ingredient: "Cheese", Parent: "Dairy"}},
+		},
+		{
+			name:         "6",
+			s:            &server,
+			path:         "/taxonomy/Cheese",
+			body:         `{}`,
+			adminKey:     "1234",
+			want:         response{code: http.StatusForbidden, body: "admin authentication failed"},
+			wantTaxonomy: []persistence.Kind{{Ingredient: "Cheese", Parent: "Dairy"}},
+		},
+		{
+			name:         "7",
+			s:            &disabled,
+			path:         "/taxonomy/Cheese",
+			body:         `{}`,
+			want:         response{code: http.StatusForbidden, body: "taxonomy changes are disabled"},
+			wantTaxonomy: []persistence.Kind{{Ingredient: "Cheese", Parent: "Dairy"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("PUT", tt.path, strings.NewReader(tt.body))
+			if tt.adminKey != "" {
+				r.Header.Set("X-Admin-Key", tt.adminKey)
+			}
+			tt.s.setIngredientKind(w, r)
+
+			if w.Code != tt.want.code || w.Body.String() != tt.want.body {
+				t.Errorf("setIngredientKind() = %v, want %v", response{code: w.Code, body: w.Body.String()}, tt.want)
+			}
+			if got := persistence.Taxonomy(); !reflect.DeepEqual(got, tt.wantTaxonomy) {
+				t.Errorf("persistence.Taxonomy() = %v, want %v", got, tt.wantTaxonomy)
+			}
+		})
+	}
+}
+
+func TestHttpServer_tracer(t *testing.T) {
+	server, _ := NewHttpServer(1234, "1234", "", NewMockDB())
+
+	type args struct {
+		originalHandler http.Handler
+	}
+	tests := []struct {
+		name      string
+		s         *HttpServer
+		args      args
+		wantRegex string
+	}{
+		{
+			name:      "1",
+			s:         &server,
+			args:      args{originalHandler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})},
+			wantRegex: "GET /tracertest 0s",
+		},
+		{
+			name:      "2",
+			s:         &server,
+			args:      args{originalHandler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { time.Sleep(5 * time.Second) })},
+			wantRegex: `GET /tracertest 5(.\d+)?s`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/tracertest", nil)
+
+			got := captureOutput(func() { server.tracer(tt.args.originalHandler).ServeHTTP(w, r) })
+
+			match, err := regexp.MatchString(tt.wantRegex, got)
+			if err != nil {
+				t.Errorf("HttpServer.tracer(): %v", err)
+			}
+			if !match {
+				t.Errorf("HttpServer.tracer() = %v, want %v", got, tt.wantRegex)
+			}
+		})
+	}
+}
+
+func TestHttpServer_auth(t *testing.T) {
+	server, _ := NewHttpServer(1234, "1234", "", NewMockDB())
+
+	type response struct {
+		code int
+		body string
+	}
+
+	tests := []struct {
+		name string
+		key  string
+		want response
+	}{
+		{
+			name: "1",
+			key:  "1234",
+			want: response{
+				code: http.StatusOK,
+			},
+		},
+		{
+			name: "2",
+			want: response{
+				code: http.StatusUnauthorized,
+			},
+		},
+		{
+			name: "3",
+			key:  "4321",
+			want: response{
+				code: http.StatusUnauthorized,
 			},
 		},
 	}
@@ -482,7 +1489,7 @@ func TestHttpServer_auth(t *testing.T) {
 }
 
 func TestHttpServer_stdHeaders(t *testing.T) {
-	server, _ := NewHttpServer(1234, "1234", NewMockDB())
+	server, _ := NewHttpServer(1234, "1234", "", NewMockDB())
 
 	tests := []struct {
 		name string
@@ -509,7 +1516,11 @@ func TestHttpServer_stdHeaders(t *testing.T) {
 }
 
 func TestHttpServer_router(t *testing.T) {
-	server, _ := NewHttpServer(1234, "1234", NewMockDB())
+	server, _ := NewHttpServer(1234, "1234", "5678", NewMockDB())
+	t.Cleanup(func() { persistence.SetTaxonomy(nil) })
+
+	setKind := httptest.NewRequest("PUT", "/taxonomy/Feta", strings.NewReader(`{"parent":"Cheese"}`))
+	setKind.Header.Set("X-Admin-Key", "5678")
 
 	type args struct {
 		r *http.Request
@@ -533,20 +1544,86 @@ func TestHttpServer_router(t *testing.T) {
 		{
 			name: "2",
 			s:    &server,
-			args: args{r: httptest.NewRequest("POST", "/recipe", strings.NewReader(`{"name":"BLT","ingredients":["Tomato","Bacon","Lettuce"]}`))},
+			args: args{r: httptest.NewRequest("POST", "/recipe", strings.NewReader(`{"name":"BLT","ingredients":["Tomato","Bacon","Lettuce"],"structuredIngredients":[{"name":"Tomato"},{"name":"Bacon"},{"name":"Lettuce"}]}`))},
 			want: response{code: http.StatusOK},
 		},
 		{
 			name: "3",
 			s:    &server,
 			args: args{r: httptest.NewRequest("GET", "/recipe/BLT", nil)},
-			want: response{code: http.StatusOK, body: `{"name":"BLT","ingredients":["Tomato","Bacon","Lettuce"]}`},
+			want: response{code: http.StatusOK, body: `{"name":"BLT","ingredients":["Tomato","Bacon","Lettuce"],"structuredIngredients":[{"name":"Tomato"},{"name":"Bacon"},{"name":"Lettuce"}]}`},
 		},
 		{
 			name: "4",
 			s:    &server,
 			args: args{r: httptest.NewRequest("GET", "/recipes?ingredients=Tomato,Bacon", nil)},
-			want: response{code: http.StatusOK, body: `{"recipes":[{"name":"BLT","ingredients":["Tomato","Bacon","Lettuce"]}]}`},
+			want: response{code: http.StatusOK, body: `{"recipes":[{"name":"BLT","ingredients":["Tomato","Bacon","Lettuce"],"structuredIngredients":[{"name":"Tomato"},{"name":"Bacon"},{"name":"Lettuce"}]}],"matches":[{"recipe":"BLT","missingIngredients":["Lettuce"],"matchedIngredients":["Tomato","Bacon"],"matched":2,"missing":1,"extra":0,"score":0.6666666666666666}]}`},
+		},
+		{
+			name: "5",
+			s:    &server,
+			args: args{r: httptest.NewRequest("DELETE", "/recipe/BLT", nil)},
+			want: response{code: http.StatusOK},
+		},
+		{
+			name: "6",
+			s:    &server,
+			args: args{r: httptest.NewRequest("GET", "/recipes?page_size=1&page_token="+persistence.EncodePageToken("Mac & Cheese"), nil)},
+			want: response{code: http.StatusOK, body: `{"recipes":[{"name":"Meatballs","ingredients":["Ground Beef","Tomato"],"structuredIngredients":[{"name":"Ground Beef"},{"name":"Tomato"}]}],"nextPageToken":"` + persistence.EncodePageToken("Meatballs") + `"}`},
+		},
+		{
+			name: "7",
+			s:    &server,
+			args: args{r: httptest.NewRequest("GET", "/recipes?q=Feta", nil)},
+			want: response{code: http.StatusOK, body: `{"recipes":[{"name":"Greek Salad","ingredients":["Feta","Tomato","Cucumber"],"structuredIngredients":[{"name":"Feta"},{"name":"Tomato"},{"name":"Cucumber"}]}],"matches":[{"recipe":"Greek Salad","missingIngredients":["Tomato","Cucumber"],"matchedIngredients":["Feta"],"matched":1,"missing":2,"extra":0,"score":0.3333333333333333}]}`},
+		},
+		{
+			name: "8",
+			s:    &server,
+			args: args{r: httptest.NewRequest("GET", "/recipes?exclude=Feta", nil)},
+			want: response{code: http.StatusBadRequest, body: "no ingredients specified"},
+		},
+		{
+			name: "9",
+			s:    &server,
+			args: args{r: httptest.NewRequest("GET", "/recipes:search?name=greek", nil)},
+			want: response{code: http.StatusOK, body: `{"recipes":[{"name":"Greek Salad","ingredients":["Feta","Tomato","Cucumber"],"structuredIngredients":[{"name":"Feta"},{"name":"Tomato"},{"name":"Cucumber"}]}]}`},
+		},
+		{
+			name: "10",
+			s:    &server,
+			args: args{r: setKind},
+			want: response{code: http.StatusOK},
+		},
+		{
+			name: "11",
+			s:    &server,
+			args: args{r: httptest.NewRequest("GET", "/taxonomy", nil)},
+			want: response{code: http.StatusOK, body: `{"kinds":[{"ingredient":"Feta","parent":"Cheese"}]}`},
+		},
+		{
+			name: "12",
+			s:    &server,
+			args: args{r: httptest.NewRequest("GET", "/ingredients:complete?prefix=fe", nil)},
+			want: response{code: http.StatusOK, body: `{"ingredients":[{"name":"Feta","recipes":1}]}`},
+		},
+		{
+			name: "13",
+			s:    &server,
+			args: args{r: httptest.NewRequest("GET", "/ingredients", nil)},
+			want: response{code: http.StatusOK, body: `{"ingredients":[{"name":"Bacon","recipes":1},{"name":"Cucumber","recipes":1},{"name":"Emmental","recipes":1},{"name":"Feta","recipes":1},{"name":"Ground Beef","recipes":2},{"name":"Gruyere","recipes":1},{"name":"Lettuce","recipes":1},{"name":"Macaroni","recipes":1},{"name":"Mozzarella","recipes":2},{"name":"Spaghetti","recipes":1},{"name":"Tomato","recipes":5}]}`},
+		},
+		{
+			name: "14",
+			s:    &server,
+			args: args{r: httptest.NewRequest("GET", "/stats", nil)},
+			want: response{code: http.StatusOK, body: `{"recipes":7,"ingredients":11,"mostUsed":[{"name":"Tomato","recipes":5},{"name":"Ground Beef","recipes":2},{"name":"Mozzarella","recipes":2},{"name":"Bacon","recipes":1},{"name":"Cucumber","recipes":1}],"leastUsed":[{"name":"Bacon","recipes":1},{"name":"Cucumber","recipes":1},{"name":"Emmental","recipes":1},{"name":"Feta","recipes":1},{"name":"Gruyere","recipes":1}],"averageIngredients":2.4285714285714284,"isolatedRecipes":["Cheese Fondue"]}`},
+		},
+		{
+			name: "15",
+			s:    &server,
+			args: args{r: httptest.NewRequest("GET", "/recipes:similar?name=Meatballs&limit=1", nil)},
+			want: response{code: http.StatusOK, body: `{"recipes":[{"name":"SpagBol","similarity":0.6666666666666666,"sharedIngredients":["Ground Beef","Tomato"]}]}`},
 		},
 	}
 	for _, tt := range tests {
@@ -0,0 +1,432 @@
+package cachedb
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"go-incubator/internal/persistence"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheDB is an implementation of persistence.Persistence which caches the results of GetRecipe and
+// FindRecipes from another implementation. The least recently used results are evicted once the cache
+// is full, and results expire after a time to live. Writes through the CacheDB invalidate exactly the
+// results they change, but changes made to the backend by anyone else are only seen once results expire.
+// As searches find recipes through their components, writing a recipe which has components or is one
+// invalidates all search results. The cache remembers the components of the recipes it has seen, written
+// or read, and only asks the backend whether a written recipe is a component when it has not seen a recipe
+// using it and some cached search results would otherwise survive the write.
+type CacheDB struct {
+	backend persistence.Persistence
+	size    int
+	ttl     time.Duration
+	now     func() time.Time
+	state   *state
+}
+
+// Stats holds the counters of a CacheDB
+type Stats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Entries   int
+}
+
+// state is the part of a CacheDB which is shared between its copies
+type state struct {
+	mu sync.Mutex
+	// lru holds the entries, most recently used first
+	lru     *list.List
+	entries map[string]*list.Element
+	// generation changes with every write, so that a result read from the backend
+	// while a write was in progress is not cached
+	generation uint64
+	stats      Stats
+	// components holds the names of the recipes which the recipes seen by the cache use as components
+	components map[string]struct{}
+}
+
+// entry is a single cached result of either GetRecipe or FindRecipes
+type entry struct {
+	key     string
+	expires time.Time
+	// recipe and err hold the result of GetRecipe
+	recipe persistence.Recipe
+	err    error
+	// ingredients and recipes hold the query (as ingredient keys) and result of FindRecipes
+	ingredients []string
+	recipes     []persistence.Recipe
+	find        bool
+}
+
+// NewCacheDB creates and returns a new CacheDB which keeps up to size results from backend for ttl
+// (or until they are evicted or invalidated if ttl is 0)
+func NewCacheDB(backend persistence.Persistence, size int, ttl time.Duration) (CacheDB, error) {
+	db := CacheDB{
+		backend: backend,
+		size:    size,
+		ttl:     ttl,
+		now:     time.Now,
+		state: &state{
+			lru:        list.New(),
+			entries:    make(map[string]*list.Element),
+			components: make(map[string]struct{}),
+		},
+	}
+
+	if backend == nil {
+		return db, fmt.Errorf("no backend specified")
+	}
+	if size < 1 {
+		return db, fmt.Errorf("invalid cache size (%d)", size)
+	}
+	if ttl < 0 {
+		return db, fmt.Errorf("invalid time to live (%v)", ttl)
+	}
+
+	return db, nil
+}
+
+// Stats returns the current counters of the cache
+func (db *CacheDB) Stats() Stats {
+	db.state.mu.Lock()
+	defer db.state.mu.Unlock()
+
+	stats := db.state.stats
+	stats.Entries = db.state.lru.Len()
+
+	return stats
+}
+
+func (db *CacheDB) AddRecipe(ctx context.Context, recipe persistence.Recipe) error {
+	// Invalidate both before and after the write, so that nobody caches what they read in between
+	db.remember(recipe)
+	all := len(recipe.Components()) > 0 || db.isComponent(ctx, recipe.Name, recipe.IngredientNames())
+	db.invalidate(recipe.Name, recipe.IngredientNames(), all)
+	defer db.invalidate(recipe.Name, recipe.IngredientNames(), all)
+
+	return db.backend.AddRecipe(ctx, recipe)
+}
+
+func (db *CacheDB) GetRecipe(ctx context.Context, name string) (persistence.Recipe, error) {
+	if err := ctx.Err(); err != nil {
+		return persistence.Recipe{}, err
+	}
+
+	key := "get\x00" + name
+	if e, ok := db.lookup(key); ok {
+		return copyRecipe(e.recipe), e.err
+	}
+
+	generation := db.generation()
+	recipe, err := db.backend.GetRecipe(ctx, name)
+	db.remember(recipe)
+
+	// Cache recipes and the fact that a recipe does not exist, but no other errors
+	if err == nil || errors.Is(err, persistence.ErrNoResults) {
+		db.store(generation, &entry{key: key, recipe: copyRecipe(recipe), err: err})
+	}
+
+	return recipe, err
+}
+
+func (db *CacheDB) DeleteRecipe(ctx context.Context, name string) error {
+	all := db.isComponent(ctx, name, nil)
+	db.invalidate(name, nil, all)
+	defer db.invalidate(name, nil, all)
+
+	return db.backend.DeleteRecipe(ctx, name)
+}
+
+func (db *CacheDB) FindRecipes(ctx context.Context, ingredients []string) ([]persistence.Recipe, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// The order, spelling and repeats of the ingredients do not change the result, so share one entry
+	query := distinctSorted(ingredients)
+	key := "find\x00" + strings.Join(query, "\x00")
+	if e, ok := db.lookup(key); ok {
+		return copyRecipes(e.recipes), nil
+	}
+
+	generation := db.generation()
+	recipes, err := db.backend.FindRecipes(ctx, ingredients)
+	if err != nil {
+		return recipes, err
+	}
+	db.remember(recipes...)
+	db.store(generation, &entry{key: key, ingredients: query, recipes: copyRecipes(recipes), find: true})
+
+	return recipes, nil
+}
+
+// SearchRecipes shares the cached results of FindRecipes for MatchAll without exclusions, expression or expansion.
+// Other searches are not cached, as a write could change the result of any of them.
+func (db *CacheDB) SearchRecipes(ctx context.Context, query persistence.Query) ([]persistence.Recipe, error) {
+	if query.Mode == persistence.MatchAll && len(query.Exclude) == 0 && query.Expr == nil && !query.Expand {
+		return db.FindRecipes(ctx, query.Ingredients)
+	}
+
+	return db.backend.SearchRecipes(ctx, query)
+}
+
+// ListRecipes is not cached, as paging through the whole catalogue would only evict the results worth keeping
+func (db *CacheDB) ListRecipes(ctx context.Context, cursor string, limit int) ([]persistence.Recipe, error) {
+	return db.backend.ListRecipes(ctx, cursor, limit)
+}
+
+// SearchRecipesByName is not cached, as the pages of a name search are rarely asked for twice
+func (db *CacheDB) SearchRecipesByName(ctx context.Context, query persistence.NameQuery, cursor string, limit int) ([]persistence.Recipe, error) {
+	return db.backend.SearchRecipesByName(ctx, query, cursor, limit)
+}
+
+// RecipeNames is not cached, as it is only needed when a recipe is not found
+func (db *CacheDB) RecipeNames(ctx context.Context) ([]string, error) {
+	return db.backend.RecipeNames(ctx)
+}
+
+// IngredientNames is not cached, as it is only needed when a search goes wrong
+func (db *CacheDB) IngredientNames(ctx context.Context) ([]string, error) {
+	return db.backend.IngredientNames(ctx)
+}
+
+// ListIngredients is not cached, as every change to a recipe can change the counts
+func (db *CacheDB) ListIngredients(ctx context.Context) ([]persistence.IngredientUsage, error) {
+	return db.backend.ListIngredients(ctx)
+}
+
+// CompleteIngredient is not cached, as every change to a recipe can change the counts
+func (db *CacheDB) CompleteIngredient(ctx context.Context, prefix string, limit int) ([]persistence.IngredientUsage, error) {
+	return db.backend.CompleteIngredient(ctx, prefix, limit)
+}
+
+// CatalogueStats is not cached, as every change to a recipe can change them
+func (db *CacheDB) CatalogueStats(ctx context.Context, top int) (persistence.CatalogueStats, error) {
+	return db.backend.CatalogueStats(ctx, top)
+}
+
+// SimilarRecipes is not cached either, as a change to any recipe sharing an ingredient can change them
+func (db *CacheDB) SimilarRecipes(ctx context.Context, name string, limit int) ([]persistence.SimilarRecipe, error) {
+	return db.backend.SimilarRecipes(ctx, name, limit)
+}
+
+// lookup returns the unexpired entry for key, and counts the hit or miss
+func (db *CacheDB) lookup(key string) (*entry, bool) {
+	s := db.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.entries[key]; ok {
+		e := el.Value.(*entry)
+		if db.ttl == 0 || db.now().Before(e.expires) {
+			s.lru.MoveToFront(el)
+			s.stats.Hits++
+			return e, true
+		}
+		s.remove(el)
+	}
+	s.stats.Misses++
+
+	return nil, false
+}
+
+// generation returns the current write generation, which must be passed to store
+func (db *CacheDB) generation() uint64 {
+	db.state.mu.Lock()
+	defer db.state.mu.Unlock()
+
+	return db.state.generation
+}
+
+// store caches e unless a write happened since generation was read, evicting
+// the least recently used entries if the cache is full
+func (db *CacheDB) store(generation uint64, e *entry) {
+	s := db.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.generation != generation {
+		return
+	}
+
+	e.expires = db.now().Add(db.ttl)
+	if el, ok := s.entries[e.key]; ok {
+		s.remove(el)
+	}
+	s.entries[e.key] = s.lru.PushFront(e)
+
+	for s.lru.Len() > db.size {
+		s.remove(s.lru.Back())
+		s.stats.Evictions++
+	}
+}
+
+// remember records the recipes which the recipes use as components
+func (db *CacheDB) remember(recipes ...persistence.Recipe) {
+	s := db.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, recipe := range recipes {
+		for _, component := range recipe.Components() {
+			s.components[component] = struct{}{}
+		}
+	}
+}
+
+// isComponent returns true if the named recipe may be a component of another recipe, so that writing it with
+// the ingredients may change the result of any search. It asks the backend unless a recipe which the cache has
+// seen uses it, or no cached search would survive the write anyway.
+func (db *CacheDB) isComponent(ctx context.Context, name string, ingredients []string) bool {
+	if db.knownComponent(name) {
+		return true
+	}
+	if !db.keepsSearches(name, ingredients) {
+		return false
+	}
+
+	// The backend finds the recipes which use the named recipe as a component by its name
+	recipes, err := db.backend.FindRecipes(ctx, []string{name})
+	if err != nil {
+		return true
+	}
+	db.remember(recipes...)
+
+	return db.knownComponent(name)
+}
+
+// knownComponent returns true if a recipe which the cache has seen uses the named recipe as a component
+func (db *CacheDB) knownComponent(name string) bool {
+	db.state.mu.Lock()
+	defer db.state.mu.Unlock()
+
+	_, ok := db.state.components[name]
+
+	return ok
+}
+
+// keepsSearches returns true if some cached search result would survive a write of the named recipe with
+// the ingredients, if that recipe were not a component
+func (db *CacheDB) keepsSearches(name string, ingredients []string) bool {
+	s := db.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	uses := ingredientKeys(ingredients)
+	for el := s.lru.Front(); el != nil; el = el.Next() {
+		if e := el.Value.(*entry); e.find && !returns(e, name) && !matches(e, uses) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// invalidate removes every entry which a write of the named recipe with the specified ingredients
+// may change: the recipe itself, searches that returned it before, and searches it would match now,
+// or all searches if all is true
+func (db *CacheDB) invalidate(name string, ingredients []string, all bool) {
+	s := db.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.generation++
+
+	if el, ok := s.entries["get\x00"+name]; ok {
+		s.remove(el)
+	}
+
+	uses := ingredientKeys(ingredients)
+	for el := s.lru.Front(); el != nil; {
+		next := el.Next()
+		if e := el.Value.(*entry); e.find && (all || returns(e, name) || matches(e, uses)) {
+			s.remove(el)
+		}
+		el = next
+	}
+}
+
+// remove deletes an entry. The caller must hold s.mu.
+func (s *state) remove(el *list.Element) {
+	s.lru.Remove(el)
+	delete(s.entries, el.Value.(*entry).key)
+}
+
+// returns is true if the cached search result contains the named recipe
+func returns(e *entry, name string) bool {
+	for _, recipe := range e.recipes {
+		if recipe.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matches is true if a recipe using the ingredients in uses is a result of the cached search
+func matches(e *entry, uses map[string]struct{}) bool {
+	for _, ingredient := range e.ingredients {
+		if _, ok := uses[ingredient]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ingredientKeys returns the set of the keys of the ingredients
+func ingredientKeys(ingredients []string) map[string]struct{} {
+	keys := make(map[string]struct{}, len(ingredients))
+	for _, ingredient := range ingredients {
+		keys[persistence.IngredientKey(ingredient)] = struct{}{}
+	}
+
+	return keys
+}
+
+// distinctSorted returns the keys of the distinct ingredients in alphabetical order
+func distinctSorted(ingredients []string) []string {
+	query := make([]string, 0, len(ingredients))
+	seen := make(map[string]struct{}, len(ingredients))
+	for _, ingredient := range ingredients {
+		key := persistence.IngredientKey(ingredient)
+		if _, ok := seen[key]; !ok {
+			seen[key] = struct{}{}
+			query = append(query, key)
+		}
+	}
+	sort.Strings(query)
+
+	return query
+}
+
+// copyRecipe returns a copy of recipe which shares no memory with the original
+func copyRecipe(recipe persistence.Recipe) persistence.Recipe {
+	if recipe.Ingredients != nil {
+		recipe.Ingredients = append([]persistence.Ingredient{}, recipe.Ingredients...)
+	}
+	if recipe.Instructions != nil {
+		recipe.Instructions = append([]string{}, recipe.Instructions...)
+	}
+
+	return recipe
+}
+
+// copyRecipes returns a copy of recipes which shares no memory with the original
+func copyRecipes(recipes []persistence.Recipe) []persistence.Recipe {
+	if recipes == nil {
+		return nil
+	}
+
+	copies := make([]persistence.Recipe, len(recipes))
+	for i, recipe := range recipes {
+		copies[i] = copyRecipe(recipe)
+	}
+
+	return copies
+}
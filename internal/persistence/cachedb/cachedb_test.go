@@ -0,0 +1,318 @@
+package cachedb
+
+import (
+	"context"
+	"go-incubator/internal/persistence"
+	"go-incubator/internal/persistence/memdb"
+	"go-incubator/internal/persistence/persistencetest"
+	"testing"
+	"time"
+)
+
+// countingDB counts the reads which reach the backend
+type countingDB struct {
+	persistence.Persistence
+	reads int
+}
+
+func (c *countingDB) GetRecipe(ctx context.Context, name string) (persistence.Recipe, error) {
+	c.reads++
+	return c.Persistence.GetRecipe(ctx, name)
+}
+
+func (c *countingDB) FindRecipes(ctx context.Context, ingredients []string) ([]persistence.Recipe, error) {
+	c.reads++
+	return c.Persistence.FindRecipes(ctx, ingredients)
+}
+
+func (c *countingDB) SearchRecipes(ctx context.Context, query persistence.Query) ([]persistence.Recipe, error) {
+	c.reads++
+	return c.Persistence.SearchRecipes(ctx, query)
+}
+
+func newTestDB(t *testing.T, size int, ttl time.Duration) (CacheDB, *countingDB) {
+	mdb, _ := memdb.NewMemDB()
+	backend := &countingDB{Persistence: &mdb}
+	for _, recipe := range persistencetest.Fixtures {
+		backend.AddRecipe(context.Background(), recipe)
+	}
+
+	db, err := NewCacheDB(backend, size, ttl)
+	if err != nil {
+		t.Fatalf("NewCacheDB() error = %v", err)
+	}
+
+	return db, backend
+}
+
+func TestNewCacheDB(t *testing.T) {
+	mdb, _ := memdb.NewMemDB()
+
+	tests := []struct {
+		name    string
+		backend persistence.Persistence
+		size    int
+		ttl     time.Duration
+		wantErr bool
+	}{
+		{name: "1", backend: &mdb, size: 10, ttl: time.Minute, wantErr: false},
+		{name: "2", backend: &mdb, size: 1, ttl: 0, wantErr: false},
+		{name: "3", backend: nil, size: 10, ttl: time.Minute, wantErr: true},
+		{name: "4", backend: &mdb, size: 0, ttl: time.Minute, wantErr: true},
+		{name: "5", backend: &mdb, size: 10, ttl: -time.Minute, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCacheDB(tt.backend, tt.size, tt.ttl)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewCacheDB() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCacheDB_Conformance(t *testing.T) {
+	persistencetest.Run(t, func(t *testing.T) persistence.Persistence {
+		mdb, _ := memdb.NewMemDB()
+		db, err := NewCacheDB(&mdb, 100, time.Minute)
+		if err != nil {
+			t.Fatalf("NewCacheDB() error = %v", err)
+		}
+		return &db
+	})
+}
+
+func TestCacheDB_Stats(t *testing.T) {
+	db, backend := newTestDB(t, 10, time.Minute)
+	ctx := context.Background()
+
+	db.GetRecipe(ctx, "BLT")
+	db.GetRecipe(ctx, "BLT")
+	db.GetRecipe(ctx, "Pizza")
+	db.GetRecipe(ctx, "Pizza")
+	db.FindRecipes(ctx, []string{"Tomato", "Bacon"})
+	db.FindRecipes(ctx, []string{"Bacon", "Tomato", "Bacon"})
+
+	want := Stats{Hits: 3, Misses: 3, Entries: 3}
+	if got := db.Stats(); got != want {
+		t.Errorf("CacheDB.Stats() = %+v, want %+v", got, want)
+	}
+	if backend.reads != 3 {
+		t.Errorf("backend reads = %d, want 3", backend.reads)
+	}
+}
+
+func TestCacheDB_SearchRecipes(t *testing.T) {
+	db, backend := newTestDB(t, 10, time.Minute)
+	ctx := context.Background()
+
+	db.FindRecipes(ctx, []string{"Tomato", "Bacon"})
+	db.SearchRecipes(ctx, persistence.Query{Ingredients: []string{"Bacon", "Tomato"}, Mode: persistence.MatchAll})
+	db.SearchRecipes(ctx, persistence.Query{Ingredients: []string{"Bacon", "Tomato"}, Mode: persistence.MatchAll, Expand: true})
+	db.SearchRecipes(ctx, persistence.Query{Ingredients: []string{"Bacon", "Lettuce", "Tomato"}, Mode: persistence.MatchSubset})
+	got, err := db.SearchRecipes(ctx, persistence.Query{Ingredients: []string{"Bacon", "Lettuce", "Tomato"}, Mode: persistence.MatchSubset})
+
+	if err != nil || len(got) != 1 || got[0].Name != "BLT" {
+		t.Errorf("CacheDB.SearchRecipes() = %v, %v, want [BLT]", got, err)
+	}
+	want := Stats{Hits: 1, Misses: 1, Entries: 1}
+	if got := db.Stats(); got != want {
+		t.Errorf("CacheDB.Stats() = %+v, want %+v", got, want)
+	}
+	if backend.reads != 4 {
+		t.Errorf("backend reads = %d, want 4", backend.reads)
+	}
+}
+
+func TestCacheDB_Invalidation(t *testing.T) {
+	tests := []struct {
+		name string
+		// write changes the database after the cache has been filled
+		write func(db *CacheDB) error
+		// stale are the cached reads which the write must invalidate, all others must stay cached
+		stale []string
+	}{
+		{
+			name: "1",
+			write: func(db *CacheDB) error {
+				return db.AddRecipe(context.Background(), persistence.Recipe{Name: "Pizza", Ingredients: persistence.NamedIngredients([]string{"Dough", "Tomato"})})
+			},
+			stale: []string{"get Pizza", "find Tomato", "find"},
+		},
+		{
+			name: "2",
+			write: func(db *CacheDB) error {
+				return db.AddRecipe(context.Background(), persistence.Recipe{Name: "BLT", Ingredients: persistence.NamedIngredients([]string{"Bacon", "Lettuce"})})
+			},
+			stale: []string{"get BLT", "find Tomato", "find Bacon", "find"},
+		},
+		{
+			name:  "3",
+			write: func(db *CacheDB) error { return db.DeleteRecipe(context.Background(), "Cheese Fondue") },
+			stale: []string{"get Cheese Fondue", "find Gruyere", "find"},
+		},
+		{
+			name:  "4",
+			write: func(db *CacheDB) error { return db.AddRecipe(context.Background(), persistence.Recipe{Name: "Water"}) },
+			stale: []string{"find"},
+		},
+		{
+			name: "5",
+			write: func(db *CacheDB) error {
+				return db.AddRecipe(context.Background(), persistence.Recipe{Name: "Pizza", Ingredients: []persistence.Ingredient{{Name: "Dough", Component: true}}})
+			},
+			stale: []string{"get Pizza", "find Tomato", "find Bacon", "find Gruyere", "find Mozzarella", "find"},
+		},
+		{
+			name: "6",
+			write: func(db *CacheDB) error {
+				// Pizza is added behind the back of the cache, which learns that Dough is a component when a search returns it
+				pizza := persistence.Recipe{Name: "Pizza", Ingredients: []persistence.Ingredient{{Name: "Dough", Component: true}}}
+				if err := db.backend.AddRecipe(context.Background(), pizza); err != nil {
+					return err
+				}
+				if _, err := db.FindRecipes(context.Background(), []string{"Dough"}); err != nil {
+					return err
+				}
+				return db.AddRecipe(context.Background(), persistence.Recipe{Name: "Dough", Ingredients: persistence.NamedIngredients([]string{"Flour"})})
+			},
+			stale: []string{"find Tomato", "find Bacon", "find Gruyere", "find Mozzarella", "find"},
+		},
+		{
+			name: "7",
+			write: func(db *CacheDB) error {
+				// The cache never sees Pizza, so it asks the backend whether Dough is a component
+				pizza := persistence.Recipe{Name: "Pizza", Ingredients: []persistence.Ingredient{{Name: "Dough", Component: true}}}
+				if err := db.backend.AddRecipe(context.Background(), pizza); err != nil {
+					return err
+				}
+				return db.AddRecipe(context.Background(), persistence.Recipe{Name: "Dough", Ingredients: persistence.NamedIngredients([]string{"Flour"})})
+			},
+			stale: []string{"find Tomato", "find Bacon", "find Gruyere", "find Mozzarella", "find"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, backend := newTestDB(t, 100, 0)
+			ctx := context.Background()
+
+			reads := map[string]func() (any, error){
+				"get BLT":           func() (any, error) { return db.GetRecipe(ctx, "BLT") },
+				"get Pizza":         func() (any, error) { return db.GetRecipe(ctx, "Pizza") },
+				"get Cheese Fondue": func() (any, error) { return db.GetRecipe(ctx, "Cheese Fondue") },
+				"find Tomato":       func() (any, error) { return db.FindRecipes(ctx, []string{"Tomato"}) },
+				"find Bacon":        func() (any, error) { return db.FindRecipes(ctx, []string{"Bacon"}) },
+				"find Gruyere":      func() (any, error) { return db.FindRecipes(ctx, []string{"Gruyere"}) },
+				"find Mozzarella":   func() (any, error) { return db.FindRecipes(ctx, []string{"Mozzarella"}) },
+				"find":              func() (any, error) { return db.FindRecipes(ctx, []string{}) },
+			}
+			for _, read := range reads {
+				read()
+			}
+
+			if err := tt.write(&db); err != nil {
+				t.Fatalf("write error = %v", err)
+			}
+
+			stale := make(map[string]bool)
+			for _, name := range tt.stale {
+				stale[name] = true
+			}
+			for name, read := range reads {
+				before := backend.reads
+				read()
+				if reached := backend.reads > before; reached != stale[name] {
+					t.Errorf("%s reached backend = %v, want %v", name, reached, stale[name])
+				}
+			}
+		})
+	}
+}
+
+func TestCacheDB_Components(t *testing.T) {
+	ctx := context.Background()
+
+	// The recipes are in the backend before the cache wraps it, as after a restart
+	mdb, _ := memdb.NewMemDB()
+	pizza := persistence.Recipe{Name: "Pizza", Ingredients: []persistence.Ingredient{{Name: "Cheese"}, {Name: "Pizza Dough", Component: true}}}
+	for _, recipe := range []persistence.Recipe{pizza, {Name: "Pizza Dough", Ingredients: persistence.NamedIngredients([]string{"Flour"})}} {
+		if err := mdb.AddRecipe(ctx, recipe); err != nil {
+			t.Fatalf("MemDB.AddRecipe() error = %v", err)
+		}
+	}
+	db, err := NewCacheDB(&mdb, 100, 0)
+	if err != nil {
+		t.Fatalf("NewCacheDB() error = %v", err)
+	}
+
+	if got, err := db.FindRecipes(ctx, []string{"Rye", "Cheese"}); err != nil || len(got) != 0 {
+		t.Fatalf("CacheDB.FindRecipes() = %v, %v, want none", got, err)
+	}
+	if err := db.AddRecipe(ctx, persistence.Recipe{Name: "Pizza Dough", Ingredients: persistence.NamedIngredients([]string{"Flour", "Rye"})}); err != nil {
+		t.Fatalf("CacheDB.AddRecipe() error = %v", err)
+	}
+	got, err := db.FindRecipes(ctx, []string{"Rye", "Cheese"})
+	if err != nil || len(got) != 1 || got[0].Name != "Pizza" {
+		t.Errorf("CacheDB.FindRecipes() = %v, %v, want [Pizza]", got, err)
+	}
+}
+
+func TestCacheDB_Eviction(t *testing.T) {
+	db, backend := newTestDB(t, 2, 0)
+	ctx := context.Background()
+
+	db.GetRecipe(ctx, "BLT")
+	db.GetRecipe(ctx, "SpagBol")
+	db.GetRecipe(ctx, "BLT")
+	db.GetRecipe(ctx, "Meatballs")
+
+	// SpagBol was the least recently used, so it must have made room for Meatballs
+	before := backend.reads
+	db.GetRecipe(ctx, "BLT")
+	db.GetRecipe(ctx, "Meatballs")
+	if backend.reads != before {
+		t.Errorf("recently used recipes reached backend %d times, want 0", backend.reads-before)
+	}
+	db.GetRecipe(ctx, "SpagBol")
+	if backend.reads != before+1 {
+		t.Errorf("evicted recipe reached backend %d times, want 1", backend.reads-before)
+	}
+
+	if got := db.Stats().Evictions; got != 2 {
+		t.Errorf("CacheDB.Stats().Evictions = %d, want 2", got)
+	}
+}
+
+func TestCacheDB_Expiry(t *testing.T) {
+	db, backend := newTestDB(t, 10, time.Minute)
+	ctx := context.Background()
+	now := time.Now()
+	db.now = func() time.Time { return now }
+
+	db.FindRecipes(ctx, []string{"Tomato"})
+
+	now = now.Add(59 * time.Second)
+	db.FindRecipes(ctx, []string{"Tomato"})
+	if backend.reads != 1 {
+		t.Errorf("backend reads before expiry = %d, want 1", backend.reads)
+	}
+
+	now = now.Add(time.Second)
+	db.FindRecipes(ctx, []string{"Tomato"})
+	if backend.reads != 2 {
+		t.Errorf("backend reads after expiry = %d, want 2", backend.reads)
+	}
+}
+
+func TestCacheDB_DefensiveCopies(t *testing.T) {
+	db, _ := newTestDB(t, 10, 0)
+	ctx := context.Background()
+
+	got, _ := db.FindRecipes(ctx, []string{"Bacon"})
+	got[0].Ingredients[0].Name = "Changed"
+
+	got, _ = db.FindRecipes(ctx, []string{"Bacon"})
+	if got[0].Ingredients[0].Name != "Tomato" {
+		t.Errorf("CacheDB.FindRecipes() = %v after changing a previous result", got)
+	}
+}
@@ -0,0 +1,133 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrRecipeCycle is returned when adding a recipe which would be a component of itself
+var ErrRecipeCycle = errors.New("datastore: recipe would be a component of itself")
+
+// Components returns the names of the recipes which the Recipe uses as components, in recipe order
+func (r *Recipe) Components() []string {
+	var names []string
+	for _, v := range r.Ingredients {
+		if v.Component {
+			names = append(names, v.Name)
+		}
+	}
+
+	return names
+}
+
+// CheckComponents returns ErrRecipeCycle if the recipe would be a component of itself, directly or through
+// the components of its components. components returns the names of the components of a stored recipe,
+// and none for a recipe which does not exist.
+func CheckComponents(recipe Recipe, components func(name string) ([]string, error)) error {
+	seen := make(map[string]bool)
+	pending := recipe.Components()
+	for len(pending) > 0 {
+		name := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if name == recipe.Name {
+			return ErrRecipeCycle
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		more, err := components(name)
+		if err != nil {
+			return err
+		}
+		pending = append(pending, more...)
+	}
+
+	return nil
+}
+
+// ExpandRecipe returns a copy of the recipe with each component replaced by the ingredients of the recipe it
+// names, all the way down, combined by CombineIngredients. A component with a quantity but no unit, such as
+// "2 Pizza Dough", is that many batches of the recipe, whose quantities it multiplies. A component which is
+// not the name of a recipe is kept as it is.
+func ExpandRecipe(ctx context.Context, db Persistence, recipe Recipe) (Recipe, error) {
+	ingredients, err := expandComponents(ctx, db, recipe.Ingredients, map[string]bool{recipe.Name: true})
+	if err != nil {
+		return Recipe{}, err
+	}
+
+	recipe.Ingredients = CombineIngredients(ingredients)
+	recipe.Instructions = append([]string(nil), recipe.Instructions...)
+
+	return recipe, nil
+}
+
+// expandComponents returns the ingredients with their components replaced by their ingredients. parents holds
+// the names of the recipes being expanded, which none of the components may be.
+func expandComponents(ctx context.Context, db Persistence, ingredients []Ingredient, parents map[string]bool) ([]Ingredient, error) {
+	expanded := []Ingredient{}
+	for _, ingredient := range ingredients {
+		if !ingredient.Component {
+			expanded = append(expanded, ingredient)
+			continue
+		}
+		if parents[ingredient.Name] {
+			return nil, ErrRecipeCycle
+		}
+
+		component, err := db.GetRecipe(ctx, ingredient.Name)
+		if errors.Is(err, ErrNoResults) {
+			expanded = append(expanded, ingredient)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		parents[ingredient.Name] = true
+		parts, err := expandComponents(ctx, db, component.Ingredients, parents)
+		delete(parents, ingredient.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		batches := 1.0
+		if ingredient.Quantity != 0 && ingredient.Unit == "" {
+			batches = ingredient.Quantity
+		}
+		for _, part := range parts {
+			part.Quantity *= batches
+			expanded = append(expanded, part)
+		}
+	}
+
+	return expanded, nil
+}
+
+// CombineIngredients returns the ingredients with the lines for the same ingredient in the same unit added up
+// into the first of them, which keeps its note. Lines for the same ingredient in different units stay apart,
+// and ingredients are the same if their names normalise to the same name.
+func CombineIngredients(ingredients []Ingredient) []Ingredient {
+	if ingredients == nil {
+		return nil
+	}
+
+	type line struct {
+		key  string
+		unit string
+	}
+	index := make(map[line]int, len(ingredients))
+	combined := make([]Ingredient, 0, len(ingredients))
+	for _, ingredient := range ingredients {
+		l := line{key: NormaliseIngredient(ingredient.Name), unit: ingredient.Unit}
+		if i, ok := index[l]; ok {
+			combined[i].Quantity += ingredient.Quantity
+			continue
+		}
+		index[l] = len(combined)
+		combined = append(combined, ingredient)
+	}
+
+	return combined
+}
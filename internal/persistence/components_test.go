@@ -0,0 +1,83 @@
+package persistence
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCheckComponents(t *testing.T) {
+	stored := map[string][]string{
+		"Pizza Dough": nil,
+		"Pizza":       {"Pizza Dough", "Tomato Sauce"},
+		"Calzone":     {"Pizza"},
+		"Broken":      nil,
+	}
+	components := func(name string) ([]string, error) {
+		if name == "Broken" {
+			return nil, errors.New("database error")
+		}
+		return stored[name], nil
+	}
+	component := func(name string) Ingredient { return Ingredient{Name: name, Component: true} }
+
+	tests := []struct {
+		name    string
+		recipe  Recipe
+		wantErr error
+	}{
+		{name: "1", recipe: Recipe{Name: "Pizza", Ingredients: []Ingredient{component("Pizza Dough"), {Name: "Mozzarella"}}}, wantErr: nil},
+		{name: "2", recipe: Recipe{Name: "Pizza Dough", Ingredients: []Ingredient{component("Pizza Dough")}}, wantErr: ErrRecipeCycle},
+		{name: "3", recipe: Recipe{Name: "Pizza Dough", Ingredients: []Ingredient{{Name: "Flour"}, component("Calzone")}}, wantErr: ErrRecipeCycle},
+		{name: "4", recipe: Recipe{Name: "Tomato Sauce", Ingredients: []Ingredient{component("Passata")}}, wantErr: nil},
+		{name: "5", recipe: Recipe{Name: "Pizza Dough", Ingredients: []Ingredient{{Name: "Calzone"}}}, wantErr: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckComponents(tt.recipe, components); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckComponents() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	err := CheckComponents(Recipe{Name: "Lasagne", Ingredients: []Ingredient{component("Broken")}}, components)
+	if err == nil || errors.Is(err, ErrRecipeCycle) {
+		t.Errorf("CheckComponents() error = %v, want the lookup error", err)
+	}
+}
+
+func TestCombineIngredients(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []Ingredient
+		want []Ingredient
+	}{
+		{
+			name: "1",
+			in:   []Ingredient{{Name: "Flour", Quantity: 200, Unit: "g"}, {Name: "Salt"}, {Name: "flour", Quantity: 50, Unit: "g", Note: "for dusting"}},
+			want: []Ingredient{{Name: "Flour", Quantity: 250, Unit: "g"}, {Name: "Salt"}},
+		},
+		{
+			name: "2",
+			in:   []Ingredient{{Name: "Milk", Quantity: 300, Unit: "ml"}, {Name: "Milk", Quantity: 1, Unit: "cup"}},
+			want: []Ingredient{{Name: "Milk", Quantity: 300, Unit: "ml"}, {Name: "Milk", Quantity: 1, Unit: "cup"}},
+		},
+		{
+			name: "3",
+			in:   []Ingredient{{Name: "Egg", Quantity: 2}, {Name: "Eggs", Quantity: 1}},
+			want: []Ingredient{{Name: "Egg", Quantity: 3}},
+		},
+		{
+			name: "4",
+			in:   nil,
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CombineIngredients(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CombineIngredients() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,396 @@
+package persistence
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Expr is a boolean expression over the ingredients of a recipe, as parsed by ParseExpr
+type Expr interface {
+	// Eval returns true if a recipe matches the expression, where uses reports whether the recipe uses an ingredient
+	Eval(uses func(ingredient string) bool) bool
+	String() string
+}
+
+// Term matches the recipes which use the ingredient
+type Term string
+
+// Not matches the recipes which do not match Expr
+type Not struct {
+	Expr Expr
+}
+
+// And matches the recipes which match all of its expressions
+type And []Expr
+
+// Or matches the recipes which match at least one of its expressions
+type Or []Expr
+
+func (t Term) Eval(uses func(string) bool) bool {
+	return uses(string(t))
+}
+
+func (n Not) Eval(uses func(string) bool) bool {
+	return !n.Expr.Eval(uses)
+}
+
+func (a And) Eval(uses func(string) bool) bool {
+	for _, e := range a {
+		if !e.Eval(uses) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (o Or) Eval(uses func(string) bool) bool {
+	for _, e := range o {
+		if e.Eval(uses) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String quotes the ingredient if it would not parse back as itself otherwise
+func (t Term) String() string {
+	s := string(t)
+	if s == "" || s != strings.Join(strings.Fields(s), " ") || strings.ContainsAny(s, `()"`) || strings.HasPrefix(s, "-") {
+		return `"` + s + `"`
+	}
+	for _, word := range strings.Fields(s) {
+		if isKeyword(word) {
+			return `"` + s + `"`
+		}
+	}
+
+	return s
+}
+
+func (n Not) String() string {
+	return "-" + group(n.Expr)
+}
+
+func (a And) String() string {
+	return join(a, " AND ")
+}
+
+func (o Or) String() string {
+	return join(o, " OR ")
+}
+
+// group returns the expression in parentheses unless it is a single term or negation
+func group(e Expr) string {
+	switch e.(type) {
+	case And, Or:
+		return "(" + e.String() + ")"
+	}
+
+	return e.String()
+}
+
+// join returns the grouped expressions separated by op
+func join(exprs []Expr, op string) string {
+	parts := make([]string, 0, len(exprs))
+	for _, e := range exprs {
+		parts = append(parts, group(e))
+	}
+
+	return strings.Join(parts, op)
+}
+
+// Terms returns the distinct ingredients the expression asks for, i.e. those which are not negated, in order
+func Terms(e Expr) []string {
+	var terms []string
+	var walk func(Expr)
+	walk = func(e Expr) {
+		switch e := e.(type) {
+		case Term:
+			if !contains(terms, string(e)) {
+				terms = append(terms, string(e))
+			}
+		case And:
+			for _, v := range e {
+				walk(v)
+			}
+		case Or:
+			for _, v := range e {
+				walk(v)
+			}
+		}
+	}
+	walk(e)
+
+	return terms
+}
+
+// RenameTerms returns a copy of the expression with each ingredient, negated or not, replaced by rename(ingredient)
+func RenameTerms(e Expr, rename func(string) string) Expr {
+	switch e := e.(type) {
+	case Term:
+		return Term(rename(string(e)))
+	case Not:
+		return Not{RenameTerms(e.Expr, rename)}
+	case And:
+		renamed := make(And, 0, len(e))
+		for _, v := range e {
+			renamed = append(renamed, RenameTerms(v, rename))
+		}
+		return renamed
+	case Or:
+		renamed := make(Or, 0, len(e))
+		for _, v := range e {
+			renamed = append(renamed, RenameTerms(v, rename))
+		}
+		return renamed
+	}
+
+	return e
+}
+
+// ExprError is returned by ParseExpr for an invalid query. Pos is the position of the problem
+// in characters, starting at 1.
+type ExprError struct {
+	Pos int
+	Msg string
+}
+
+func (e *ExprError) Error() string {
+	return fmt.Sprintf("invalid query at position %d: %s", e.Pos, e.Msg)
+}
+
+// maxExprDepth limits how deeply a query may nest, so that a malicious one cannot exhaust the stack
+const maxExprDepth = 32
+
+// ParseExpr parses an ingredient query such as `Tomato AND (Basil OR Oregano) -Garlic`.
+//
+// Queries combine ingredients with the operators AND, OR and NOT (which must be written in capitals)
+// or a leading "-" for NOT. NOT binds tightest and OR loosest, parentheses group, and expressions
+// next to each other are combined with AND. Consecutive words form a single ingredient name, so
+// `Ground Beef -Onion` finds the recipes using ground beef but not onion, and a name which contains
+// an operator or parenthesis can be written in double quotes.
+func ParseExpr(query string) (Expr, error) {
+	tokens, err := tokenize([]rune(query))
+	if err != nil {
+		return nil, err
+	}
+
+	p := parser{tokens: tokens}
+	e, err := p.or(0)
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEnd {
+		return nil, &ExprError{Pos: t.pos, Msg: fmt.Sprintf("unexpected %s", t)}
+	}
+
+	return e, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenWord
+	tokenQuoted
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenOpen
+	tokenClose
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEnd:
+		return "end of query"
+	case tokenWord, tokenQuoted:
+		return fmt.Sprintf("%q", t.text)
+	}
+
+	return t.text
+}
+
+// tokenize splits the query into tokens, ending with a tokenEnd
+func tokenize(query []rune) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(query); {
+		r := query[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenOpen, text: "(", pos: i + 1})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenClose, text: ")", pos: i + 1})
+			i++
+		case r == '-':
+			tokens = append(tokens, token{kind: tokenNot, text: "-", pos: i + 1})
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(query) && query[end] != '"' {
+				end++
+			}
+			if end == len(query) {
+				return nil, &ExprError{Pos: i + 1, Msg: "missing closing quote"}
+			}
+			text := strings.Join(strings.Fields(string(query[i+1:end])), " ")
+			if text == "" {
+				return nil, &ExprError{Pos: i + 1, Msg: "empty ingredient"}
+			}
+			tokens = append(tokens, token{kind: tokenQuoted, text: text, pos: i + 1})
+			i = end + 1
+		default:
+			end := i
+			for end < len(query) && !unicode.IsSpace(query[end]) && !strings.ContainsRune(`()"`, query[end]) {
+				end++
+			}
+			t := token{kind: tokenWord, text: string(query[i:end]), pos: i + 1}
+			switch t.text {
+			case "AND":
+				t.kind = tokenAnd
+			case "OR":
+				t.kind = tokenOr
+			case "NOT":
+				t.kind = tokenNot
+			}
+			tokens = append(tokens, t)
+			i = end
+		}
+	}
+
+	return append(tokens, token{kind: tokenEnd, pos: len(query) + 1}), nil
+}
+
+func isKeyword(s string) bool {
+	return s == "AND" || s == "OR" || s == "NOT"
+}
+
+// parser is a recursive descent parser of the tokens of a query
+type parser struct {
+	tokens []token
+	next   int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) take() token {
+	t := p.tokens[p.next]
+	if t.kind != tokenEnd {
+		p.next++
+	}
+
+	return t
+}
+
+// or parses: and { "OR" and }
+func (p *parser) or(depth int) (Expr, error) {
+	e, err := p.and(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	exprs := Or{e}
+	for p.peek().kind == tokenOr {
+		p.take()
+		e, err := p.and(depth)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, e)
+	}
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+
+	return exprs, nil
+}
+
+// and parses: not { ["AND"] not }
+func (p *parser) and(depth int) (Expr, error) {
+	e, err := p.not(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	exprs := And{e}
+	for {
+		switch p.peek().kind {
+		case tokenAnd:
+			p.take()
+		case tokenWord, tokenQuoted, tokenNot, tokenOpen:
+			// expressions next to each other are combined with AND
+		default:
+			if len(exprs) == 1 {
+				return exprs[0], nil
+			}
+			return exprs, nil
+		}
+
+		e, err := p.not(depth)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, e)
+	}
+}
+
+// not parses: { "NOT" | "-" } primary
+func (p *parser) not(depth int) (Expr, error) {
+	if p.peek().kind != tokenNot {
+		return p.primary(depth)
+	}
+
+	t := p.take()
+	if depth >= maxExprDepth {
+		return nil, &ExprError{Pos: t.pos, Msg: "query nests too deeply"}
+	}
+	e, err := p.not(depth + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return Not{Expr: e}, nil
+}
+
+// primary parses: "(" expression ")" | quoted | word { word }
+func (p *parser) primary(depth int) (Expr, error) {
+	t := p.take()
+	switch t.kind {
+	case tokenOpen:
+		if depth >= maxExprDepth {
+			return nil, &ExprError{Pos: t.pos, Msg: "query nests too deeply"}
+		}
+		e, err := p.or(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if end := p.take(); end.kind != tokenClose {
+			return nil, &ExprError{Pos: end.pos, Msg: fmt.Sprintf("expected ) to match ( at position %d, found %s", t.pos, end)}
+		}
+		return e, nil
+	case tokenQuoted:
+		return Term(t.text), nil
+	case tokenWord:
+		words := []string{t.text}
+		for p.peek().kind == tokenWord {
+			words = append(words, p.take().text)
+		}
+		return Term(strings.Join(words, " ")), nil
+	}
+
+	return nil, &ExprError{Pos: t.pos, Msg: fmt.Sprintf("expected an ingredient, found %s", t)}
+}
@@ -0,0 +1,77 @@
+package persistence
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseExpr(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    Expr
+		wantErr string
+	}{
+		{name: "1", query: "Tomato", want: Term("Tomato")},
+		{name: "2", query: "Tomato AND (Basil OR Oregano) -Garlic", want: And{Term("Tomato"), Or{Term("Basil"), Term("Oregano")}, Not{Term("Garlic")}}},
+		{name: "3", query: "  Ground   Beef  Onion OR NOT Bacon", want: Or{Term("Ground Beef Onion"), Not{Term("Bacon")}}},
+		{name: "4", query: `"Salt AND Pepper" (Bread)`, want: And{Term("Salt AND Pepper"), Term("Bread")}},
+		{name: "5", query: "A OR B AND C", want: Or{Term("A"), And{Term("B"), Term("C")}}},
+		{name: "6", query: "--semi-skimmed milk", want: Not{Not{Term("semi-skimmed milk")}}},
+		{name: "7", query: "Crème fraîche and dill", want: Term("Crème fraîche and dill")},
+		{name: "8", query: "", wantErr: "invalid query at position 1: expected an ingredient, found end of query"},
+		{name: "9", query: "Tomato AND", wantErr: "invalid query at position 11: expected an ingredient, found end of query"},
+		{name: "10", query: "Tomato OR OR Basil", wantErr: "invalid query at position 11: expected an ingredient, found OR"},
+		{name: "11", query: "(Tomato OR Basil", wantErr: "invalid query at position 17: expected ) to match ( at position 1, found end of query"},
+		{name: "12", query: "Tomato) Basil", wantErr: "invalid query at position 7: unexpected )"},
+		{name: "13", query: `Crème "fraîche`, wantErr: "invalid query at position 7: missing closing quote"},
+		{name: "14", query: `Tomato "  "`, wantErr: "invalid query at position 8: empty ingredient"},
+		{name: "15", query: "()", wantErr: "invalid query at position 2: expected an ingredient, found )"},
+		{name: "16", query: "((((((((((((((((((((((((((((((((((Tomato))))))))))))))))))))))))))))))))))", wantErr: "invalid query at position 33: query nests too deeply"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseExpr(tt.query)
+			if (err != nil) != (tt.wantErr != "") || (err != nil && err.Error() != tt.wantErr) {
+				t.Errorf("ParseExpr() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseExpr() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpr_String(t *testing.T) {
+	tests := []struct {
+		name string
+		e    Expr
+		want string
+	}{
+		{name: "1", e: And{Term("Tomato"), Or{Term("Basil"), Term("Oregano")}, Not{Term("Garlic")}}, want: "Tomato AND (Basil OR Oregano) AND -Garlic"},
+		{name: "2", e: Not{Or{Term("Ground Beef"), Term("Salt AND Pepper")}}, want: `-(Ground Beef OR "Salt AND Pepper")`},
+		{name: "3", e: Or{Term("-1"), Term("(a)"), Term("a  b")}, want: `"-1" OR "(a)" OR "a  b"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.e.String(); got != tt.want {
+				t.Errorf("Expr.String() = %v, want %v", got, tt.want)
+			}
+			// Everything but a name with repeated spaces parses back to the same expression
+			if tt.name != "3" {
+				if got, err := ParseExpr(tt.want); err != nil || !reflect.DeepEqual(got, tt.e) {
+					t.Errorf("ParseExpr(Expr.String()) = %#v, %v, want %#v", got, err, tt.e)
+				}
+			}
+		})
+	}
+}
+
+func TestTerms(t *testing.T) {
+	e := And{Term("Tomato"), Or{Term("Basil"), Not{Term("Garlic")}, Term("Tomato")}, Not{And{Term("Bacon")}}}
+	want := []string{"Tomato", "Basil"}
+	if got := Terms(e); !reflect.DeepEqual(got, want) {
+		t.Errorf("Terms() = %v, want %v", got, want)
+	}
+}
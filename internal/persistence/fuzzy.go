@@ -0,0 +1,223 @@
+package persistence
+
+import (
+	"context"
+	"sort"
+)
+
+// MaxSuggestions is the most names which SuggestRecipes and SuggestIngredients suggest for a single name
+const MaxSuggestions = 5
+
+// Suggestion lists the names which are close to a name that was not found, closest first
+type Suggestion struct {
+	Name        string
+	Suggestions []string
+}
+
+// Distance returns the number of single rune insertions, deletions and substitutions and swaps
+// of adjacent runes needed to turn a into b, so that "Tomatoe" and "Tomato" are 1 apart and
+// "Tomtao" and "Tomato" are as well
+func Distance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+
+	// Keep the last three rows of the table of distances between the prefixes of s and t
+	prev2 := make([]int, len(t)+1)
+	prev := make([]int, len(t)+1)
+	row := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		row[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			row[j] = prev[j-1] + cost
+			if d := prev[j] + 1; d < row[j] {
+				row[j] = d
+			}
+			if d := row[j-1] + 1; d < row[j] {
+				row[j] = d
+			}
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] && prev2[j-2]+1 < row[j] {
+				row[j] = prev2[j-2] + 1
+			}
+		}
+		prev2, prev, row = prev, row, prev2
+	}
+
+	return prev[len(t)]
+}
+
+// SuggestRecipes returns the names of up to MaxSuggestions recipes in db whose names are close to name,
+// closest first. Case and whitespace do not count, so the exact name of a recipe which was asked for
+// with the wrong case comes first.
+func SuggestRecipes(ctx context.Context, db Persistence, name string) ([]string, error) {
+	names, err := db.RecipeNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return suggest(name, names, FoldName), nil
+}
+
+// SuggestIngredients returns a Suggestion for each of the ingredients which no recipe in db uses and which
+// is close to at least one that some recipe does, in the order of ingredients. Ingredients are compared
+// after normalisation, so only real misspellings are suggested for. Kinds in the taxonomy, such as Cheese,
+// are not misspellings either, even when no recipe uses them by that name.
+func SuggestIngredients(ctx context.Context, db Persistence, ingredients []string) ([]Suggestion, error) {
+	names, err := db.IngredientNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		known[IngredientKey(name)] = struct{}{}
+	}
+	for _, kind := range Taxonomy() {
+		known[IngredientKey(kind.Ingredient)] = struct{}{}
+		known[IngredientKey(kind.Parent)] = struct{}{}
+	}
+
+	suggestions := []Suggestion{}
+	seen := make(map[string]struct{}, len(ingredients))
+	for _, ingredient := range ingredients {
+		key := IngredientKey(ingredient)
+		if _, ok := known[key]; ok {
+			continue
+		}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		if near := suggest(ingredient, names, NormaliseIngredient); len(near) > 0 {
+			suggestions = append(suggestions, Suggestion{Name: ingredient, Suggestions: near})
+		}
+	}
+
+	return suggestions, nil
+}
+
+// Names returns the distinct ingredients which the query names anywhere, including those which
+// it excludes or negates, in order
+func (q *Query) Names() []string {
+	var names []string
+	add := func(name string) string {
+		if !contains(names, name) {
+			names = append(names, name)
+		}
+		return name
+	}
+
+	for _, ingredient := range q.Ingredients {
+		add(ingredient)
+	}
+	for _, ingredient := range q.Exclude {
+		add(ingredient)
+	}
+	if q.Expr != nil {
+		RenameTerms(q.Expr, add)
+	}
+
+	return names
+}
+
+// Corrected returns a copy of the query which searches for the first suggestion of each of the
+// suggestions instead of the ingredient it was made for, wherever the query names it
+func (q Query) Corrected(suggestions []Suggestion) Query {
+	if len(suggestions) == 0 {
+		return q
+	}
+
+	correct := func(name string) string {
+		for _, s := range suggestions {
+			if s.Name == name && len(s.Suggestions) > 0 {
+				return s.Suggestions[0]
+			}
+		}
+		return name
+	}
+	rename := func(names []string) []string {
+		if names == nil {
+			return nil
+		}
+		renamed := make([]string, 0, len(names))
+		for _, name := range names {
+			renamed = append(renamed, correct(name))
+		}
+		return renamed
+	}
+
+	q.Ingredients = rename(q.Ingredients)
+	q.Exclude = rename(q.Exclude)
+	if q.Expr != nil {
+		q.Expr = RenameTerms(q.Expr, correct)
+	}
+
+	return q
+}
+
+// maxDistance returns how far apart a name of the specified number of runes and a name which is
+// suggested for it may be, which is 1 for the shortest names and at most 3
+func maxDistance(runes int) int {
+	if runes >= 8 {
+		return 3
+	}
+
+	return 1 + runes/4
+}
+
+// suggest returns up to MaxSuggestions of the candidates which are close to name once both are turned
+// into keys, closest first. Of several candidates with the same key, only the first in byte order is kept.
+func suggest(name string, candidates []string, key func(string) string) []string {
+	type candidate struct {
+		name     string
+		key      string
+		distance int
+	}
+
+	target := key(name)
+	limit := maxDistance(len([]rune(target)))
+	best := make(map[string]candidate)
+	for _, c := range candidates {
+		k := key(c)
+		if existing, ok := best[k]; ok {
+			if c < existing.name {
+				existing.name = c
+				best[k] = existing
+			}
+			continue
+		}
+		if diff := len([]rune(k)) - len([]rune(target)); diff > limit || -diff > limit {
+			continue
+		}
+		if d := Distance(target, k); d <= limit {
+			best[k] = candidate{name: c, key: k, distance: d}
+		}
+	}
+
+	found := make([]candidate, 0, len(best))
+	for _, c := range best {
+		found = append(found, c)
+	}
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].distance != found[j].distance {
+			return found[i].distance < found[j].distance
+		}
+		return found[i].key < found[j].key
+	})
+	if len(found) > MaxSuggestions {
+		found = found[:MaxSuggestions]
+	}
+
+	names := make([]string, 0, len(found))
+	for _, c := range found {
+		names = append(names, c.name)
+	}
+
+	return names
+}
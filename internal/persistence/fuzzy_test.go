@@ -0,0 +1,110 @@
+package persistence
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want int
+	}{
+		{name: "1", a: "tomato", b: "tomato", want: 0},
+		{name: "2", a: "tomatoe", b: "tomato", want: 1},
+		{name: "3", a: "tomtao", b: "tomato", want: 1},
+		{name: "4", a: "mozarela", b: "mozzarella", want: 2},
+		{name: "5", a: "", b: "feta", want: 4},
+		{name: "6", a: "crème", b: "creme", want: 1},
+		{name: "7", a: "bacon", b: "lettuce", want: 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Distance(tt.a, tt.b); got != tt.want {
+				t.Errorf("Distance() = %v, want %v", got, tt.want)
+			}
+			if got := Distance(tt.b, tt.a); got != tt.want {
+				t.Errorf("Distance() reversed = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_suggest(t *testing.T) {
+	recipes := []string{"BLT", "Caprese Salad", "Cheese Fondue", "Greek Salad", "Mac & Cheese", "Meatballs", "SpagBol"}
+	ingredients := []string{"Tomato", "tomatoes", "Mozzarella", "Macaroni", "Feta", "Bacon", "Ground Beef", "Cucumber"}
+
+	tests := []struct {
+		name       string
+		in         string
+		candidates []string
+		key        func(string) string
+		want       []string
+	}{
+		{name: "1", in: "blt", candidates: recipes, key: FoldName, want: []string{"BLT"}},
+		{name: "2", in: "Greek  Salat", candidates: recipes, key: FoldName, want: []string{"Greek Salad"}},
+		{name: "3", in: "Mac and Cheese", candidates: recipes, key: FoldName, want: []string{"Mac & Cheese"}},
+		{name: "4", in: "Pizza", candidates: recipes, key: FoldName, want: []string{}},
+		{name: "5", in: "Tomatoe", candidates: ingredients, key: NormaliseIngredient, want: []string{"Tomato"}},
+		{name: "6", in: "mozarela", candidates: ingredients, key: NormaliseIngredient, want: []string{"Mozzarella"}},
+		{name: "7", in: "Fet", candidates: ingredients, key: NormaliseIngredient, want: []string{"Feta"}},
+		{name: "8", in: "Macaron", candidates: ingredients, key: NormaliseIngredient, want: []string{"Macaroni"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := suggest(tt.in, tt.candidates, tt.key); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("suggest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuery_Names(t *testing.T) {
+	q := Query{
+		Ingredients: []string{"Tomato", "Basil"},
+		Exclude:     []string{"Garlic", "Tomato"},
+		Expr:        And{Term("Oregano"), Not{Term("Onion")}, Or{Term("Basil"), Term("Mozzarella")}},
+	}
+	want := []string{"Tomato", "Basil", "Garlic", "Oregano", "Onion", "Mozzarella"}
+	if got := q.Names(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Query.Names() = %v, want %v", got, want)
+	}
+}
+
+func TestQuery_Corrected(t *testing.T) {
+	suggestions := []Suggestion{
+		{Name: "Tomatoe", Suggestions: []string{"Tomato", "Potato"}},
+		{Name: "Garlik", Suggestions: []string{"Garlic"}},
+	}
+
+	tests := []struct {
+		name  string
+		query Query
+		want  Query
+	}{
+		{
+			name:  "1",
+			query: Query{Ingredients: []string{"Tomatoe", "Basil"}, Exclude: []string{"Garlik"}, Mode: MatchSubset, MaxMissing: 1},
+			want:  Query{Ingredients: []string{"Tomato", "Basil"}, Exclude: []string{"Garlic"}, Mode: MatchSubset, MaxMissing: 1},
+		},
+		{
+			name:  "2",
+			query: Query{Expr: And{Term("Tomatoe"), Not{Term("Garlik")}}},
+			want:  Query{Expr: And{Term("Tomato"), Not{Term("Garlic")}}},
+		},
+		{
+			name:  "3",
+			query: Query{Ingredients: []string{"Basil"}},
+			want:  Query{Ingredients: []string{"Basil"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.query.Corrected(suggestions); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Query.Corrected() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package persistence
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultCompletions is the number of ingredients CompleteIngredient suggests when a client does not
+// ask for a number, and MaxCompletions is the most a client may ask for
+const (
+	DefaultCompletions = 10
+	MaxCompletions     = 100
+)
+
+// IngredientUsage is an ingredient with the number of recipes which use it. Spellings which normalise
+// to the same name are one ingredient, which goes by the first of them in alphabetical order.
+type IngredientUsage struct {
+	Name    string
+	Recipes int
+}
+
+// CompletionLimit returns the number of completions to return for the requested number, which is the
+// default for 0 and capped at MaxCompletions
+func CompletionLimit(requested int) (int, error) {
+	switch {
+	case requested < 0:
+		return 0, fmt.Errorf("invalid limit (%d)", requested)
+	case requested == 0:
+		return DefaultCompletions, nil
+	case requested > MaxCompletions:
+		return MaxCompletions, nil
+	}
+
+	return requested, nil
+}
+
+// CompletionPrefixes returns the prefixes which CompleteIngredient looks for at the start of the normalised
+// names of ingredients: the prefix as typed, case folded, and its normalised form, so that both "Chees"
+// and "Tomatoes" complete to an ingredient
+func CompletionPrefixes(prefix string) []string {
+	folded, normalised := FoldName(prefix), NormaliseIngredient(prefix)
+	if folded == normalised {
+		return []string{folded}
+	}
+
+	return []string{folded, normalised}
+}
+
+// CompletesTo returns true if the normalised name of an ingredient starts with any of prefixes
+func CompletesTo(normalised string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(normalised, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SortByUsage sorts ingredients with the most used first, and those used equally often by name
+func SortByUsage(ingredients []IngredientUsage) {
+	sort.Slice(ingredients, func(i, j int) bool {
+		if ingredients[i].Recipes != ingredients[j].Recipes {
+			return ingredients[i].Recipes > ingredients[j].Recipes
+		}
+		return ingredients[i].Name < ingredients[j].Name
+	})
+}
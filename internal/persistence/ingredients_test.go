@@ -0,0 +1,62 @@
+package persistence
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompletionLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		requested int
+		want      int
+		wantErr   bool
+	}{
+		{name: "1", requested: 0, want: DefaultCompletions, wantErr: false},
+		{name: "2", requested: 5, want: 5, wantErr: false},
+		{name: "3", requested: MaxCompletions + 1, want: MaxCompletions, wantErr: false},
+		{name: "4", requested: -1, want: 0, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CompletionLimit(tt.requested)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CompletionLimit() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("CompletionLimit() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompletionPrefixes(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+		want   []string
+	}{
+		{name: "1", prefix: "Moz", want: []string{"moz"}},
+		{name: "2", prefix: " Ground  B", want: []string{"ground b"}},
+		{name: "3", prefix: "Tomatoes", want: []string{"tomatoes", "tomato"}},
+		{name: "4", prefix: "", want: []string{""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CompletionPrefixes(tt.prefix); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CompletionPrefixes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortByUsage(t *testing.T) {
+	got := []IngredientUsage{{Name: "Basil", Recipes: 1}, {Name: "Tomato", Recipes: 3}, {Name: "Anchovy", Recipes: 1}, {Name: "Garlic", Recipes: 3}}
+	SortByUsage(got)
+
+	want := []IngredientUsage{{Name: "Garlic", Recipes: 3}, {Name: "Tomato", Recipes: 3}, {Name: "Anchovy", Recipes: 1}, {Name: "Basil", Recipes: 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SortByUsage() = %v, want %v", got, want)
+	}
+}
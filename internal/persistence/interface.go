@@ -1,16 +1,100 @@
 package persistence
 
-import "errors"
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+)
 
 type Recipe struct {
-	Name        string
-	Ingredients []string
+	Name         string
+	Description  string
+	Ingredients  []Ingredient
+	Instructions []string // the steps of the method, in order
+	Servings     int
+	PrepMinutes  int
+	CookMinutes  int
+	Source       string // where the recipe comes from, such as a book or a URL
+}
+
+// Ingredient is a single line of the ingredient list of a Recipe, such as "2 cups flour, sifted".
+// Recipes are searched by ingredient Name only, and a Quantity of 0 means that none was specified.
+// A Component is another recipe, such as "Pizza Dough", which Name refers to.
+type Ingredient struct {
+	Name      string
+	Quantity  float64
+	Unit      string
+	Note      string
+	Component bool
+}
+
+// UnmarshalJSON also accepts a bare ingredient name, which is how ingredients were stored before they had details
+func (i *Ingredient) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*i = Ingredient{Name: name}
+		return nil
+	}
+
+	// Unmarshal into a type without this method, so that we do not end up back here
+	type ingredient Ingredient
+	return json.Unmarshal(data, (*ingredient)(i))
+}
+
+// NamedIngredients returns ingredients with the specified names and no further details
+func NamedIngredients(names []string) []Ingredient {
+	if names == nil {
+		return nil
+	}
+
+	ingredients := make([]Ingredient, 0, len(names))
+	for _, name := range names {
+		ingredients = append(ingredients, Ingredient{Name: name})
+	}
+
+	return ingredients
 }
 
-// UsesIngredient returns true if the Recipe uses the specified ingredient
+// DistinctIngredients returns a copy of ingredients without repeats, keeping the first use of each ingredient.
+// Ingredients whose names only differ in spelling are repeats, but synonyms are not.
+func DistinctIngredients(ingredients []Ingredient) []Ingredient {
+	if ingredients == nil {
+		return nil
+	}
+
+	seen := make(map[string]struct{}, len(ingredients))
+	unique := make([]Ingredient, 0, len(ingredients))
+	for _, ingredient := range ingredients {
+		key := NormaliseIngredient(ingredient.Name)
+		if _, ok := seen[key]; !ok {
+			seen[key] = struct{}{}
+			unique = append(unique, ingredient)
+		}
+	}
+
+	return unique
+}
+
+// IngredientNames returns the names of the ingredients of the Recipe, in order
+func (r *Recipe) IngredientNames() []string {
+	if r.Ingredients == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(r.Ingredients))
+	for _, v := range r.Ingredients {
+		names = append(names, v.Name)
+	}
+
+	return names
+}
+
+// UsesIngredient returns true if the Recipe uses the specified ingredient, however it is spelt
+// and including its synonyms
 func (r *Recipe) UsesIngredient(ingredient string) bool {
 	for _, v := range r.Ingredients {
-		if v == ingredient {
+		if SameIngredient(v.Name, ingredient) {
 			return true
 		}
 	}
@@ -29,12 +113,272 @@ func (r *Recipe) UsesIngredients(ingredients []string) bool {
 	return true
 }
 
+// MissingIngredients returns the names of the ingredients of the Recipe which are not in have, in recipe order
+func (r *Recipe) MissingIngredients(have []string) []string {
+	missing := []string{}
+	for _, v := range r.Ingredients {
+		if !containsIngredient(have, v.Name) {
+			missing = append(missing, v.Name)
+		}
+	}
+
+	return missing
+}
+
+// MatchMode selects which recipes a Query finds
+type MatchMode int
+
+const (
+	// MatchAll finds the recipes which use all of the ingredients
+	MatchAll MatchMode = iota
+	// MatchAny finds the recipes which use at least one of the ingredients
+	MatchAny
+	// MatchSubset finds the recipes which can be made from the ingredients, i.e. which use at least one
+	// of them and need no more than MaxMissing other ingredients
+	MatchSubset
+)
+
+// Query is a search for recipes by their ingredients
+type Query struct {
+	Ingredients []string
+	// Exclude holds ingredients which none of the recipes found may use, whatever the Mode
+	Exclude []string
+	Mode    MatchMode
+	// MaxMissing is the number of ingredients a recipe may need besides those of the query, and is only used by MatchSubset
+	MaxMissing int
+	// Expr, if not nil, must also match the recipes found, whatever the Mode
+	Expr Expr
+	// Expand makes every ingredient the query names, wherever it does, also match all kinds of that
+	// ingredient in the taxonomy, so that Cheese finds recipes using Mozzarella
+	Expand bool
+}
+
+// Variants returns the normalised names which an ingredient of the query matches, which are those of
+// ExpandIngredient for an expanded query and those of IngredientVariants otherwise
+func (q *Query) Variants(ingredient string) []string {
+	if q.Expand {
+		return ExpandIngredient(ingredient)
+	}
+
+	return IngredientVariants(ingredient)
+}
+
+// MissingIngredients returns the names of the ingredients of the Recipe which the ingredients of the query
+// do not cover, in recipe order. For an expanded query, a kind of an ingredient of the query is covered.
+func (q *Query) MissingIngredients(r *Recipe) []string {
+	if !q.Expand {
+		return r.MissingIngredients(q.Ingredients)
+	}
+
+	missing := []string{}
+	for _, v := range r.Ingredients {
+		if !q.covers(q.Ingredients, v.Name) {
+			missing = append(missing, v.Name)
+		}
+	}
+
+	return missing
+}
+
+// uses returns true if the Recipe uses the ingredient or, for an expanded query, a kind of it
+func (q *Query) uses(r *Recipe, ingredient string) bool {
+	if !q.Expand {
+		return r.UsesIngredient(ingredient)
+	}
+
+	for _, v := range r.Ingredients {
+		if IsA(v.Name, ingredient) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// covers returns true if ingredient is the same as one of the ingredients or, for an expanded query, a kind of one
+func (q *Query) covers(ingredients []string, ingredient string) bool {
+	if !q.Expand {
+		return containsIngredient(ingredients, ingredient)
+	}
+
+	for _, v := range ingredients {
+		if IsA(ingredient, v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Matches returns true if the Recipe is a result of the query
+func (q *Query) Matches(r *Recipe) bool {
+	used := 0
+	wanted := make(map[string]struct{}, len(q.Ingredients))
+	for _, ingredient := range q.Ingredients {
+		key := IngredientKey(ingredient)
+		if _, ok := wanted[key]; !ok {
+			wanted[key] = struct{}{}
+			if q.uses(r, ingredient) {
+				used++
+			}
+		}
+	}
+
+	for _, ingredient := range q.Exclude {
+		if q.uses(r, ingredient) {
+			return false
+		}
+	}
+	if q.Expr != nil && !q.Expr.Eval(func(ingredient string) bool { return q.uses(r, ingredient) }) {
+		return false
+	}
+
+	switch q.Mode {
+	case MatchAny:
+		return used > 0
+	case MatchSubset:
+		return used > 0 && len(q.MissingIngredients(r)) <= q.MaxMissing
+	default:
+		return used == len(wanted)
+	}
+}
+
+// Score measures how well a Recipe matches the ingredients of a Query
+type Score struct {
+	Matched []string // the ingredients of the recipe which are in the query, in recipe order
+	Missing []string // the ingredients of the recipe which are not in the query, in recipe order
+	Extra   int      // the number of distinct ingredients of the query which the recipe does not use
+}
+
+// Value returns the Jaccard index of the ingredients of the recipe and of the query, i.e. the number of
+// ingredients they share divided by the number of distinct ingredients in either, from 0 to 1
+func (s Score) Value() float64 {
+	total := len(s.Matched) + len(s.Missing) + s.Extra
+	if total == 0 {
+		return 0
+	}
+
+	return float64(len(s.Matched)) / float64(total)
+}
+
+// Score returns how well the Recipe matches the ingredients of the query, which are those of Ingredients
+// followed by those Expr asks for
+func (q *Query) Score(r *Recipe) Score {
+	wanted := q.Ingredients
+	if q.Expr != nil {
+		wanted = append(append([]string{}, q.Ingredients...), Terms(q.Expr)...)
+	}
+
+	score := Score{Matched: []string{}, Missing: []string{}}
+	for _, v := range r.Ingredients {
+		if q.covers(wanted, v.Name) {
+			score.Matched = append(score.Matched, v.Name)
+		} else {
+			score.Missing = append(score.Missing, v.Name)
+		}
+	}
+
+	seen := make(map[string]struct{}, len(wanted))
+	for _, ingredient := range wanted {
+		key := IngredientKey(ingredient)
+		if _, ok := seen[key]; !ok {
+			seen[key] = struct{}{}
+			if !q.uses(r, ingredient) {
+				score.Extra++
+			}
+		}
+	}
+
+	return score
+}
+
+// Ranked is a recipe found by a Query together with its Score
+type Ranked struct {
+	Recipe Recipe
+	Score  Score
+}
+
+// Rank scores the recipes found by the query and sorts them by relevance: the highest score first,
+// then the most matched ingredients, then by name
+func (q *Query) Rank(recipes []Recipe) []Ranked {
+	ranked := make([]Ranked, 0, len(recipes))
+	for i := range recipes {
+		ranked = append(ranked, Ranked{Recipe: recipes[i], Score: q.Score(&recipes[i])})
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i].Score, ranked[j].Score
+		if a.Value() != b.Value() {
+			return a.Value() > b.Value()
+		}
+		if len(a.Matched) != len(b.Matched) {
+			return len(a.Matched) > len(b.Matched)
+		}
+		return ranked[i].Recipe.Name < ranked[j].Recipe.Name
+	})
+
+	return ranked
+}
+
+// containsIngredient returns true if ingredient is the same as one of the ingredients
+func containsIngredient(ingredients []string, ingredient string) bool {
+	for _, v := range ingredients {
+		if SameIngredient(v, ingredient) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// contains returns true if s is one of the values
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
 // ErrNoResults is returned when no results are found
 var ErrNoResults = errors.New("datastore: no results found")
 
-// Persistence is an interface that can be implemented by database structures
+// Persistence is an interface that can be implemented by database structures.
+// Implementations should stop work and return the context's error as soon as
+// the received context is cancelled or its deadline expires.
 type Persistence interface {
-	AddRecipe(Recipe) error
-	GetRecipe(string) (Recipe, error)
-	FindRecipes([]string) ([]Recipe, error)
+	// AddRecipe stores the recipe, or returns ErrRecipeCycle if it would be a component of itself
+	AddRecipe(context.Context, Recipe) error
+	GetRecipe(context.Context, string) (Recipe, error)
+	DeleteRecipe(context.Context, string) error
+	FindRecipes(context.Context, []string) ([]Recipe, error)
+	// SearchRecipes returns the recipes which match the query, in the same order as FindRecipes.
+	// FindRecipes is a SearchRecipes with MatchAll. A recipe uses the ingredients of its components, all
+	// the way down, but MatchSubset only counts the ingredient lines of the recipe itself as missing.
+	SearchRecipes(context.Context, Query) ([]Recipe, error)
+	// ListRecipes returns up to limit recipes whose names sort after the cursor, in name order.
+	// The name of the last recipe returned is the cursor for the next page, and an empty cursor starts
+	// from the first recipe. Every backend compares and orders names byte-wise, whatever its collation.
+	ListRecipes(ctx context.Context, cursor string, limit int) ([]Recipe, error)
+	// SearchRecipesByName returns up to limit recipes which match the query and whose names sort after the
+	// cursor, in the same order as ListRecipes and paged in the same way
+	SearchRecipesByName(ctx context.Context, query NameQuery, cursor string, limit int) ([]Recipe, error)
+	// RecipeNames returns the names of all recipes, in no particular order
+	RecipeNames(context.Context) ([]string, error)
+	// IngredientNames returns the names of the ingredients which at least one recipe uses, in no particular
+	// order. An ingredient which recipes spell in several ways may be returned once for each spelling.
+	IngredientNames(context.Context) ([]string, error)
+	// ListIngredients returns each ingredient which at least one recipe uses with the number of recipes
+	// which use it, in name order
+	ListIngredients(context.Context) ([]IngredientUsage, error)
+	// CompleteIngredient returns up to limit of the ingredients which complete the prefix (see CompletionPrefixes),
+	// counted as ListIngredients counts them, with the most used first
+	CompleteIngredient(ctx context.Context, prefix string, limit int) ([]IngredientUsage, error)
+	// CatalogueStats summarises all recipes, with up to top of the most and of the least used ingredients
+	CatalogueStats(ctx context.Context, top int) (CatalogueStats, error)
+	// SimilarRecipes returns up to limit of the recipes which share ingredients with the named recipe, in the
+	// order of SortBySimilarity, or ErrNoResults if there is no such recipe
+	SimilarRecipes(ctx context.Context, name string, limit int) ([]SimilarRecipe, error)
 }
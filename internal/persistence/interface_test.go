@@ -1,13 +1,15 @@
 package persistence
 
 import (
+	"encoding/json"
+	"reflect"
 	"testing"
 )
 
 func TestRecipe_UsesIngredient(t *testing.T) {
 	recipe := Recipe{
 		Name:        "Test Name",
-		Ingredients: []string{"one", "two", "three"},
+		Ingredients: []Ingredient{{Name: "one"}, {Name: "two", Quantity: 2, Unit: "cups"}, {Name: "three", Note: "chopped"}},
 	}
 	tests := []struct {
 		name       string
@@ -33,7 +35,7 @@ func TestRecipe_UsesIngredient(t *testing.T) {
 func TestUsesIngredient(t *testing.T) {
 	recipe := Recipe{
 		Name:        "Test Name",
-		Ingredients: []string{"one", "two", "three"},
+		Ingredients: []Ingredient{{Name: "one"}, {Name: "two", Quantity: 2, Unit: "cups"}, {Name: "three", Note: "chopped"}},
 	}
 
 	tests := []struct {
@@ -58,7 +60,7 @@ func TestUsesIngredient(t *testing.T) {
 func TestUsesIngredients(t *testing.T) {
 	recipe := Recipe{
 		Name:        "Test Name",
-		Ingredients: []string{"one", "two", "three"},
+		Ingredients: []Ingredient{{Name: "one"}, {Name: "two", Quantity: 2, Unit: "cups"}, {Name: "three", Note: "chopped"}},
 	}
 
 	tests := []struct {
@@ -84,7 +86,7 @@ func TestUsesIngredients(t *testing.T) {
 func TestRecipe_UsesIngredients(t *testing.T) {
 	recipe := Recipe{
 		Name:        "Test Name",
-		Ingredients: []string{"one", "two", "three"},
+		Ingredients: []Ingredient{{Name: "one"}, {Name: "two", Quantity: 2, Unit: "cups"}, {Name: "three", Note: "chopped"}},
 	}
 
 	tests := []struct {
@@ -108,3 +110,194 @@ func TestRecipe_UsesIngredients(t *testing.T) {
 		})
 	}
 }
+
+func TestRecipe_IngredientNames(t *testing.T) {
+	tests := []struct {
+		name string
+		r    *Recipe
+		want []string
+	}{
+		{name: "1", r: &Recipe{Ingredients: []Ingredient{{Name: "one"}, {Name: "two", Quantity: 2, Unit: "cups"}}}, want: []string{"one", "two"}},
+		{name: "2", r: &Recipe{Ingredients: []Ingredient{}}, want: []string{}},
+		{name: "3", r: &Recipe{}, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.r.IngredientNames(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Recipe.IngredientNames() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecipe_MissingIngredients(t *testing.T) {
+	recipe := Recipe{Ingredients: []Ingredient{{Name: "Bread"}, {Name: "Bacon", Quantity: 4}, {Name: "Tomato"}}}
+	tests := []struct {
+		name string
+		r    *Recipe
+		have []string
+		want []string
+	}{
+		{name: "1", r: &recipe, have: []string{"Bread", "Bacon", "Tomato"}, want: []string{}},
+		{name: "2", r: &recipe, have: []string{"Tomato", "Cheese"}, want: []string{"Bread", "Bacon"}},
+		{name: "3", r: &recipe, have: nil, want: []string{"Bread", "Bacon", "Tomato"}},
+		{name: "4", r: &Recipe{}, have: []string{"Bread"}, want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.r.MissingIngredients(tt.have); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Recipe.MissingIngredients() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuery_Matches(t *testing.T) {
+	recipe := Recipe{Ingredients: []Ingredient{{Name: "Bread"}, {Name: "Bacon"}, {Name: "Tomato"}}}
+	tests := []struct {
+		name string
+		q    Query
+		want bool
+	}{
+		{name: "1", q: Query{Ingredients: []string{"Bacon", "Tomato"}}, want: true},
+		{name: "2", q: Query{Ingredients: []string{"Bacon", "Cheese"}}, want: false},
+		{name: "3", q: Query{Ingredients: []string{}}, want: true},
+		{name: "4", q: Query{Ingredients: []string{"Bacon", "Cheese"}, Mode: MatchAny}, want: true},
+		{name: "5", q: Query{Ingredients: []string{"Cheese"}, Mode: MatchAny}, want: false},
+		{name: "6", q: Query{Ingredients: []string{"Bread", "Bacon", "Tomato", "Cheese"}, Mode: MatchSubset}, want: true},
+		{name: "7", q: Query{Ingredients: []string{"Bread", "Bacon"}, Mode: MatchSubset}, want: false},
+		{name: "8", q: Query{Ingredients: []string{"Bread", "Bacon"}, Mode: MatchSubset, MaxMissing: 1}, want: true},
+		{name: "9", q: Query{Ingredients: []string{"Cheese"}, Mode: MatchSubset, MaxMissing: 3}, want: false},
+		{name: "10", q: Query{Ingredients: []string{"Bacon", "Bacon"}, Mode: MatchAll}, want: true},
+		{name: "11", q: Query{Ingredients: []string{"Bacon"}, Exclude: []string{"Tomato"}}, want: false},
+		{name: "12", q: Query{Ingredients: []string{"Bacon", "Cheese"}, Exclude: []string{"Cheese"}, Mode: MatchAny}, want: true},
+		{name: "13", q: Query{Ingredients: []string{"Bread", "Bacon", "Tomato"}, Exclude: []string{"Bread"}, Mode: MatchSubset}, want: false},
+		{name: "14", q: Query{Expr: And{Term("Bacon"), Or{Term("Lettuce"), Term("Tomato")}}}, want: true},
+		{name: "15", q: Query{Expr: And{Term("Bacon"), Not{Term("Bread")}}}, want: false},
+		{name: "16", q: Query{Ingredients: []string{"Cheese"}, Mode: MatchAny, Expr: Term("Bacon")}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.q.Matches(&recipe); got != tt.want {
+				t.Errorf("Query.Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuery_Score(t *testing.T) {
+	recipe := Recipe{Ingredients: []Ingredient{{Name: "Bread"}, {Name: "Bacon"}, {Name: "Tomato"}}}
+	tests := []struct {
+		name      string
+		q         Query
+		want      Score
+		wantValue float64
+	}{
+		{
+			name:      "1",
+			q:         Query{Ingredients: []string{"Tomato", "Bread", "Bacon"}},
+			want:      Score{Matched: []string{"Bread", "Bacon", "Tomato"}, Missing: []string{}, Extra: 0},
+			wantValue: 1,
+		},
+		{
+			name:      "2",
+			q:         Query{Ingredients: []string{"Tomato", "Cheese", "Tomato", "Basil"}},
+			want:      Score{Matched: []string{"Tomato"}, Missing: []string{"Bread", "Bacon"}, Extra: 2},
+			wantValue: 0.2,
+		},
+		{
+			name:      "3",
+			q:         Query{Ingredients: []string{"Cheese"}},
+			want:      Score{Matched: []string{}, Missing: []string{"Bread", "Bacon", "Tomato"}, Extra: 1},
+			wantValue: 0,
+		},
+		{
+			name:      "4",
+			q:         Query{},
+			want:      Score{Matched: []string{}, Missing: []string{"Bread", "Bacon", "Tomato"}, Extra: 0},
+			wantValue: 0,
+		},
+		{
+			name:      "5",
+			q:         Query{Ingredients: []string{"Bread"}, Expr: And{Term("Bacon"), Or{Term("Lettuce"), Term("Bread")}, Not{Term("Tomato")}}},
+			want:      Score{Matched: []string{"Bread", "Bacon"}, Missing: []string{"Tomato"}, Extra: 1},
+			wantValue: 0.5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.q.Score(&recipe)
+			if !reflect.DeepEqual(got, tt.want) || got.Value() != tt.wantValue {
+				t.Errorf("Query.Score() = %v (%v), want %v (%v)", got, got.Value(), tt.want, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestQuery_Rank(t *testing.T) {
+	recipes := []Recipe{
+		{Name: "BLT", Ingredients: NamedIngredients([]string{"Tomato", "Bacon", "Lettuce"})},
+		{Name: "Caprese Salad", Ingredients: NamedIngredients([]string{"Mozzarella", "Tomato"})},
+		{Name: "Meatballs", Ingredients: NamedIngredients([]string{"Ground Beef", "Tomato"})},
+		{Name: "Tomato Soup", Ingredients: NamedIngredients([]string{"Tomato", "Onion", "Stock", "Cream"})},
+		{Name: "Water", Ingredients: []Ingredient{}},
+	}
+	tests := []struct {
+		name string
+		q    Query
+		want []string
+	}{
+		{name: "1", q: Query{Ingredients: []string{"Tomato"}}, want: []string{"Caprese Salad", "Meatballs", "BLT", "Tomato Soup", "Water"}},
+		{name: "2", q: Query{Ingredients: []string{"Tomato", "Onion", "Stock", "Bacon"}, Mode: MatchAny}, want: []string{"Tomato Soup", "BLT", "Caprese Salad", "Meatballs", "Water"}},
+		{name: "3", q: Query{Ingredients: []string{"Tomato", "Mozzarella", "Lettuce", "Bacon"}}, want: []string{"BLT", "Caprese Salad", "Meatballs", "Tomato Soup", "Water"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, ranked := range tt.q.Rank(recipes) {
+				got = append(got, ranked.Recipe.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Query.Rank() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIngredient_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []Ingredient
+		wantErr bool
+	}{
+		{
+			name: "1",
+			data: `["Tomato","Bacon"]`,
+			want: []Ingredient{{Name: "Tomato"}, {Name: "Bacon"}},
+		},
+		{
+			name: "2",
+			data: `[{"Name":"Flour","Quantity":2,"Unit":"cups","Note":"sifted"},"Salt"]`,
+			want: []Ingredient{{Name: "Flour", Quantity: 2, Unit: "cups", Note: "sifted"}, {Name: "Salt"}},
+		},
+		{
+			name:    "3",
+			data:    `[42]`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []Ingredient
+			err := json.Unmarshal([]byte(tt.data), &got)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Ingredient.UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Ingredient.UnmarshalJSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,321 @@
+package memdb
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-incubator/internal/persistence"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	snapshotFile = "snapshot.json"
+	logFile      = "wal.log"
+)
+
+// ErrClosed is returned when writing to a file backed MemDB after it has been closed
+var ErrClosed = errors.New("datastore: database is closed")
+
+// journal keeps a MemDB on disk as a compacted snapshot plus a write-ahead log of the changes since
+type journal struct {
+	dir          string
+	log          *os.File
+	syncInterval time.Duration
+	done         chan struct{}
+	wg           sync.WaitGroup
+	// closed is set once the files are closed, under the lock of the MemDB
+	closed    bool
+	closeOnce sync.Once
+	closeErr  error
+	// failed holds the first error of the background work, which Close returns
+	failedMu sync.Mutex
+	failed   error
+}
+
+// entry is a single change recorded in the write-ahead log
+type entry struct {
+	Op     string              `json:"op"`
+	Recipe *persistence.Recipe `json:"recipe,omitempty"`
+	Name   string              `json:"name,omitempty"`
+}
+
+const (
+	opAdd    = "add"
+	opDelete = "delete"
+)
+
+// NewFileMemDB creates and returns a new MemDB which keeps its recipes in the directory dir.
+// On start the last snapshot and the write-ahead log are replayed and compacted into a new snapshot.
+// The log is flushed to disk every syncInterval (or after every change if syncInterval is 0), and a new
+// snapshot is written every snapshotInterval (or only on start and Close if snapshotInterval is 0).
+func NewFileMemDB(dir string, syncInterval time.Duration, snapshotInterval time.Duration) (MemDB, error) {
+	db, _ := NewMemDB()
+
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return db, fmt.Errorf("creating directory: %w", err)
+	}
+
+	err = db.replay(dir)
+	if err != nil {
+		return db, err
+	}
+
+	log, err := os.OpenFile(filepath.Join(dir, logFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return db, fmt.Errorf("opening log: %w", err)
+	}
+	db.journal = &journal{
+		dir:          dir,
+		log:          log,
+		syncInterval: syncInterval,
+		done:         make(chan struct{}),
+	}
+
+	// Compact whatever we replayed, so that the log only holds changes made from now on
+	err = db.Snapshot()
+	if err != nil {
+		log.Close()
+		return db, err
+	}
+
+	if syncInterval > 0 {
+		db.journal.every(syncInterval, func() {
+			if err := log.Sync(); err != nil {
+				db.journal.fail(fmt.Errorf("syncing log: %w", err))
+			}
+		})
+	}
+	if snapshotInterval > 0 {
+		db.journal.every(snapshotInterval, func() {
+			if err := db.Snapshot(); err != nil {
+				db.journal.fail(err)
+			}
+		})
+	}
+
+	return db, nil
+}
+
+// Snapshot writes all recipes to a new snapshot file and empties the write-ahead log.
+// It does nothing for a MemDB that is not backed by files.
+func (db *MemDB) Snapshot() error {
+	if db.journal == nil {
+		return nil
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.journal.closed {
+		return ErrClosed
+	}
+
+	names := make([]string, 0, len(db.recipes))
+	for name := range db.recipes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	recipes := make([]persistence.Recipe, 0, len(names))
+	for _, name := range names {
+		recipes = append(recipes, db.recipes[name])
+	}
+
+	content, err := json.Marshal(recipes)
+	if err != nil {
+		return fmt.Errorf("marshalling snapshot: %w", err)
+	}
+
+	// Replace the snapshot atomically, so that a crash leaves either the old or the new one
+	path := filepath.Join(db.journal.dir, snapshotFile)
+	err = writeFileSync(path+".tmp", content)
+	if err != nil {
+		return fmt.Errorf("writing snapshot: %w", err)
+	}
+	err = os.Rename(path+".tmp", path)
+	if err != nil {
+		return fmt.Errorf("replacing snapshot: %w", err)
+	}
+	syncDir(db.journal.dir)
+
+	// Replaying an entry that is already part of the snapshot is harmless, so
+	// a crash before the log is emptied loses nothing
+	err = db.journal.log.Truncate(0)
+	if err != nil {
+		return fmt.Errorf("truncating log: %w", err)
+	}
+
+	return db.journal.log.Sync()
+}
+
+// Close stops the background work of a file backed MemDB, writes a final snapshot and closes its files.
+// It returns the first error of the background work too, and closing again returns the same result.
+func (db *MemDB) Close() error {
+	if db.journal == nil {
+		return nil
+	}
+
+	j := db.journal
+	j.closeOnce.Do(func() {
+		close(j.done)
+		j.wg.Wait()
+
+		err := db.Snapshot()
+
+		db.mu.Lock()
+		j.closed = true
+		if cerr := j.log.Close(); err == nil {
+			err = cerr
+		}
+		db.mu.Unlock()
+
+		j.failedMu.Lock()
+		if err == nil && j.failed != nil {
+			err = fmt.Errorf("background work failed: %w", j.failed)
+		}
+		j.failedMu.Unlock()
+
+		j.closeErr = err
+	})
+
+	return j.closeErr
+}
+
+// fail records an error of the background work, keeping only the first
+func (j *journal) fail(err error) {
+	j.failedMu.Lock()
+	defer j.failedMu.Unlock()
+
+	if j.failed == nil {
+		j.failed = err
+	}
+}
+
+// append writes e to the write-ahead log. The caller must hold db.mu for writing.
+func (j *journal) append(e entry) error {
+	if j.closed {
+		return ErrClosed
+	}
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshalling log entry: %w", err)
+	}
+
+	_, err = j.log.Write(append(line, '\n'))
+	if err != nil {
+		return fmt.Errorf("writing log entry: %w", err)
+	}
+
+	if j.syncInterval <= 0 {
+		if err = j.log.Sync(); err != nil {
+			return fmt.Errorf("syncing log: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// every calls f every interval until the journal is closed
+func (j *journal) every(interval time.Duration, f func()) {
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-j.done:
+				return
+			case <-ticker.C:
+				f()
+			}
+		}
+	}()
+}
+
+// replay loads the snapshot and then applies the write-ahead log found in dir
+func (db *MemDB) replay(dir string) error {
+	content, err := os.ReadFile(filepath.Join(dir, snapshotFile))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("reading snapshot: %w", err)
+	}
+	if err == nil {
+		var recipes []persistence.Recipe
+		if err := json.Unmarshal(content, &recipes); err != nil {
+			return fmt.Errorf("unmarshalling snapshot: %w", err)
+		}
+		for _, recipe := range recipes {
+			db.add(recipe)
+		}
+	}
+
+	log, err := os.Open(filepath.Join(dir, logFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("opening log: %w", err)
+	}
+	defer log.Close()
+
+	r := bufio.NewReader(log)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			// A crash while appending can leave a partial last line, which was never acknowledged
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading log: %w", err)
+		}
+
+		var e entry
+		if err := json.Unmarshal(bytes.TrimSpace(line), &e); err != nil {
+			return fmt.Errorf("unmarshalling log entry: %w", err)
+		}
+		switch {
+		case e.Op == opAdd && e.Recipe != nil:
+			db.add(*e.Recipe)
+		case e.Op == opDelete:
+			db.delete(e.Name)
+		default:
+			return fmt.Errorf("unknown log entry (%s)", line)
+		}
+	}
+}
+
+// writeFileSync writes content to the file at path and flushes it to disk
+func writeFileSync(path string, content []byte) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	if _, err = f.Write(content); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// syncDir flushes directory entries (such as a rename) to disk where the platform supports it
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+}
@@ -0,0 +1,208 @@
+package memdb
+
+import (
+	"context"
+	"errors"
+	"go-incubator/internal/persistence"
+	"go-incubator/internal/persistence/persistencetest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewFileMemDB(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	db, err := NewFileMemDB(dir, 0, 0)
+	if err != nil {
+		t.Fatalf("NewFileMemDB() error = %v", err)
+	}
+	db.AddRecipe(ctx, persistence.Recipe{Name: "BLT", Ingredients: persistence.NamedIngredients([]string{"Tomato", "Bacon", "Lettuce"})})
+	db.AddRecipe(ctx, persistence.Recipe{Name: "Meatballs", Ingredients: persistence.NamedIngredients([]string{"Ground Beef", "Tomato"})})
+	db.AddRecipe(ctx, persistence.Recipe{Name: "Meatballs", Ingredients: persistence.NamedIngredients([]string{"Ground Beef", "Onion"})})
+	db.DeleteRecipe(ctx, "BLT")
+	if err := db.Close(); err != nil {
+		t.Fatalf("MemDB.Close() error = %v", err)
+	}
+
+	db, err = NewFileMemDB(dir, 0, 0)
+	if err != nil {
+		t.Fatalf("NewFileMemDB() error = %v", err)
+	}
+	defer db.Close()
+
+	got, _ := db.FindRecipes(ctx, []string{})
+	want := []persistence.Recipe{{Name: "Meatballs", Ingredients: persistence.NamedIngredients([]string{"Ground Beef", "Onion"})}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MemDB.FindRecipes() after reopening = %v, want %v", got, want)
+	}
+}
+
+func TestFileMemDB_Close(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := NewFileMemDB(t.TempDir(), time.Hour, time.Hour)
+	if err != nil {
+		t.Fatalf("NewFileMemDB() error = %v", err)
+	}
+	db.AddRecipe(ctx, persistence.Recipe{Name: "BLT", Ingredients: persistence.NamedIngredients([]string{"Tomato", "Bacon"})})
+
+	for i := 0; i < 2; i++ {
+		if err := db.Close(); err != nil {
+			t.Fatalf("MemDB.Close() error = %v", err)
+		}
+	}
+
+	// Writes after closing fail without changing the recipes, which can still be read
+	if err := db.AddRecipe(ctx, persistence.Recipe{Name: "Toast"}); !errors.Is(err, ErrClosed) {
+		t.Errorf("MemDB.AddRecipe() error = %v, want %v", err, ErrClosed)
+	}
+	if err := db.DeleteRecipe(ctx, "BLT"); !errors.Is(err, ErrClosed) {
+		t.Errorf("MemDB.DeleteRecipe() error = %v, want %v", err, ErrClosed)
+	}
+	if err := db.Snapshot(); !errors.Is(err, ErrClosed) {
+		t.Errorf("MemDB.Snapshot() error = %v, want %v", err, ErrClosed)
+	}
+	if names, err := db.RecipeNames(ctx); err != nil || len(names) != 1 {
+		t.Errorf("MemDB.RecipeNames() = %v, %v, want [BLT]", names, err)
+	}
+}
+
+func TestFileMemDB_replay(t *testing.T) {
+	tests := []struct {
+		name     string
+		snapshot string
+		log      string
+		want     []persistence.Recipe
+		wantErr  bool
+	}{
+		{
+			name:     "1",
+			snapshot: `[{"Name":"BLT","Ingredients":["Tomato","Bacon","Lettuce"]}]`,
+			log:      `{"op":"add","recipe":{"Name":"Meatballs","Ingredients":["Ground Beef","Tomato"]}}` + "\n" + `{"op":"delete","name":"BLT"}` + "\n",
+			want:     []persistence.Recipe{{Name: "Meatballs", Ingredients: persistence.NamedIngredients([]string{"Ground Beef", "Tomato"})}},
+		},
+		{
+			name:     "2",
+			snapshot: `[{"Name":"BLT","Ingredients":["Tomato","Bacon","Lettuce"]}]`,
+			log:      `{"op":"delete","name":"BLT"}` + "\n" + `{"op":"add","recipe":{"Name":"Meat`,
+			want:     []persistence.Recipe{},
+		},
+		{
+			name: "3",
+			log:  `{"op":"add","recipe":{"Name":"BLT","Ingredients":["Tomato"]}}` + "\n" + `{"op":"add","recipe":{"Name":"BLT","Ingredients":["Tomato","Bacon"]}}` + "\n",
+			want: []persistence.Recipe{{Name: "BLT", Ingredients: persistence.NamedIngredients([]string{"Tomato", "Bacon"})}},
+		},
+		{
+			name:    "4",
+			log:     `not json` + "\n",
+			wantErr: true,
+		},
+		{
+			name:     "5",
+			snapshot: `[{"Name":"BLT"`,
+			wantErr:  true,
+		},
+		{
+			name:     "6",
+			snapshot: `[{"Name":"BLT","Ingredients":["Tomato","Bacon"]}]`,
+			log:      `{"op":"add","recipe":{"Name":"Meatballs","Ingredients":[{"Name":"Ground Beef","Quantity":500,"Unit":"g","Note":""},"Tomato"]}}` + "\n",
+			want: []persistence.Recipe{
+				{Name: "BLT", Ingredients: []persistence.Ingredient{{Name: "Tomato"}, {Name: "Bacon"}}},
+				{Name: "Meatballs", Ingredients: []persistence.Ingredient{{Name: "Ground Beef", Quantity: 500, Unit: "g"}, {Name: "Tomato"}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.snapshot != "" {
+				os.WriteFile(filepath.Join(dir, snapshotFile), []byte(tt.snapshot), 0644)
+			}
+			if tt.log != "" {
+				os.WriteFile(filepath.Join(dir, logFile), []byte(tt.log), 0644)
+			}
+
+			db, err := NewFileMemDB(dir, 0, 0)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewFileMemDB() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			defer db.Close()
+
+			got, _ := db.FindRecipes(context.Background(), []string{})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MemDB.FindRecipes() after replay = %v, want %v", got, tt.want)
+			}
+
+			// Replayed changes are compacted into the snapshot straight away
+			if info, err := os.Stat(filepath.Join(dir, logFile)); err != nil || info.Size() != 0 {
+				t.Errorf("log after replay = %v, %v, want an empty file", info, err)
+			}
+		})
+	}
+}
+
+func TestFileMemDB_Crash(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	// Changes must be recoverable from the log even if the database is never closed
+	db, err := NewFileMemDB(dir, 0, 0)
+	if err != nil {
+		t.Fatalf("NewFileMemDB() error = %v", err)
+	}
+	db.AddRecipe(ctx, persistence.Recipe{Name: "BLT", Ingredients: persistence.NamedIngredients([]string{"Tomato", "Bacon", "Lettuce"})})
+
+	recovered, err := NewFileMemDB(dir, 0, 0)
+	if err != nil {
+		t.Fatalf("NewFileMemDB() error = %v", err)
+	}
+	defer recovered.Close()
+
+	got, err := recovered.GetRecipe(ctx, "BLT")
+	want := persistence.Recipe{Name: "BLT", Ingredients: persistence.NamedIngredients([]string{"Tomato", "Bacon", "Lettuce"})}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("MemDB.GetRecipe() after crash = %v, %v, want %v", got, err, want)
+	}
+}
+
+func TestFileMemDB_Intervals(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	db, err := NewFileMemDB(dir, 10*time.Millisecond, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewFileMemDB() error = %v", err)
+	}
+	defer db.Close()
+	db.AddRecipe(ctx, persistence.Recipe{Name: "BLT", Ingredients: persistence.NamedIngredients([]string{"Tomato", "Bacon", "Lettuce"})})
+
+	// The periodic snapshot moves the change out of the log
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		info, err := os.Stat(filepath.Join(dir, logFile))
+		if err == nil && info.Size() == 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("log was not compacted into a snapshot within 5s")
+}
+
+func TestFileMemDB_Conformance(t *testing.T) {
+	persistencetest.Run(t, func(t *testing.T) persistence.Persistence {
+		db, err := NewFileMemDB(t.TempDir(), time.Millisecond, 0)
+		if err != nil {
+			t.Fatalf("NewFileMemDB() error = %v", err)
+		}
+		t.Cleanup(func() { db.Close() })
+		return &db
+	})
+}
@@ -1,54 +1,678 @@
 package memdb
 
 import (
+	"context"
 	"go-incubator/internal/persistence"
 	"sort"
+	"strings"
+	"sync"
 )
 
+// MemDB is an in-memory implementation of persistence.Persistence which is safe for concurrent use
 type MemDB struct {
+	mu      *sync.RWMutex
 	recipes map[string]persistence.Recipe
+	// index maps each normalised ingredient name to the names of the recipes that use it
+	index map[string]map[string]struct{}
+	// keys maps each recipe name to the normalised names of its ingredients in recipe order, so that
+	// SimilarRecipes can walk the index without normalising ingredient names again
+	keys map[string][]string
+	// users maps the name of each recipe which is a component of others to the names of those recipes,
+	// whether or not a recipe with that name exists
+	users map[string]map[string]struct{}
+	// names holds the names of all recipes in alphabetical order, so that ListRecipes can page without sorting
+	names *[]string
+	// folded holds the folded names of all recipes in order, so that name searches can find prefixes by binary search
+	folded *[]foldedName
+	// journal records every change on disk, or is nil for a purely in-memory MemDB
+	journal *journal
 }
 
 func NewMemDB() (MemDB, error) {
 	db := MemDB{
+		mu:      &sync.RWMutex{},
 		recipes: make(map[string]persistence.Recipe),
+		index:   make(map[string]map[string]struct{}),
+		keys:    make(map[string][]string),
+		users:   make(map[string]map[string]struct{}),
+		names:   &[]string{},
+		folded:  &[]foldedName{},
 	}
 
 	return db, nil
 }
 
-func (db *MemDB) AddRecipe(recipe persistence.Recipe) error {
-	db.recipes[recipe.Name] = recipe
+func (db *MemDB) AddRecipe(ctx context.Context, recipe persistence.Recipe) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Store a copy so that the caller cannot change our data through its slices, and
+	// keep only the first use of each ingredient like the SQL backends do
+	recipe = copyRecipe(recipe)
+	recipe.Ingredients = persistence.DistinctIngredients(recipe.Ingredients)
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	err := persistence.CheckComponents(recipe, func(name string) ([]string, error) {
+		component := db.recipes[name]
+		return component.Components(), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if db.journal != nil {
+		if err := db.journal.append(entry{Op: opAdd, Recipe: &recipe}); err != nil {
+			return err
+		}
+	}
+	db.add(recipe)
 
 	return nil
 }
 
-func (db *MemDB) GetRecipe(name string) (persistence.Recipe, error) {
+func (db *MemDB) GetRecipe(ctx context.Context, name string) (persistence.Recipe, error) {
+	if err := ctx.Err(); err != nil {
+		return persistence.Recipe{}, err
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
 	recipe, ok := db.recipes[name]
 	if !ok {
 		return recipe, persistence.ErrNoResults
 	}
 
-	return recipe, nil
+	return copyRecipe(recipe), nil
+}
+
+func (db *MemDB) DeleteRecipe(ctx context.Context, name string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if _, ok := db.recipes[name]; !ok {
+		return persistence.ErrNoResults
+	}
+
+	if db.journal != nil {
+		if err := db.journal.append(entry{Op: opDelete, Name: name}); err != nil {
+			return err
+		}
+	}
+	db.delete(name)
+
+	return nil
+}
+
+func (db *MemDB) FindRecipes(ctx context.Context, ingredients []string) ([]persistence.Recipe, error) {
+	return db.SearchRecipes(ctx, persistence.Query{Ingredients: ingredients})
+}
+
+func (db *MemDB) SearchRecipes(ctx context.Context, query persistence.Query) ([]persistence.Recipe, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	// Anything but MatchAny and MatchSubset finds like MatchAll, as in Query.Matches
+	var names []string
+	if query.Mode == persistence.MatchAny || query.Mode == persistence.MatchSubset {
+		names = db.findSome(query)
+	} else {
+		names = db.findAll(query)
+	}
+
+	// Drop the recipes which use any of the excluded ingredients or do not match the expression
+	if len(query.Exclude) > 0 || query.Expr != nil {
+		kept := names[:0]
+		for _, name := range names {
+			if !db.usesAny(query, name, query.Exclude) && (query.Expr == nil || query.Expr.Eval(db.uses(query, name))) {
+				kept = append(kept, name)
+			}
+		}
+		names = kept
+	}
+
+	return db.sortedRecipes(names), nil
+}
+
+func (db *MemDB) ListRecipes(ctx context.Context, cursor string, limit int) ([]persistence.Recipe, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	// Find the first name after the cursor, which need not be the name of an existing recipe
+	names := *db.names
+	start := sort.SearchStrings(names, cursor)
+	if start < len(names) && names[start] == cursor {
+		start++
+	}
+
+	end := start
+	if limit > 0 {
+		end = start + limit
+	}
+	if end > len(names) {
+		end = len(names)
+	}
+
+	return db.copyRecipes(names[start:end]), nil
+}
+
+func (db *MemDB) SearchRecipesByName(ctx context.Context, query persistence.NameQuery, cursor string, limit int) ([]persistence.Recipe, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	// A prefix search only needs the run of folded names which start with the prefix, while
+	// a substring search has to look at every name
+	folded := *db.folded
+	if query.Match == persistence.NamePrefix {
+		prefix := persistence.FoldName(query.Text)
+		start := sort.Search(len(folded), func(i int) bool { return folded[i].key >= prefix })
+		end := start
+		for end < len(folded) && strings.HasPrefix(folded[end].key, prefix) {
+			end++
+		}
+		folded = folded[start:end]
+	}
+
+	names := []string{}
+	for _, f := range folded {
+		if f.name > cursor && query.MatchesFolded(f.key) {
+			names = append(names, f.name)
+		}
+	}
+
+	sort.Strings(names)
+	if limit < 0 {
+		limit = 0
+	}
+	if len(names) > limit {
+		names = names[:limit]
+	}
+
+	return db.copyRecipes(names), nil
+}
+
+func (db *MemDB) RecipeNames(ctx context.Context) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	return append([]string{}, *db.names...), nil
 }
 
-func (db *MemDB) FindRecipes(ingredients []string) ([]persistence.Recipe, error) {
-	// We want to return the list of recipes in alphabetical order (by name)
-	// To do that, we first extract map keys into a slice, then sort the slice,
-	// then iterate over the slice, to obtain map entries in alphabetical order
-	keys := make([]string, 0, len(db.recipes))
-	for k := range db.recipes {
-		keys = append(keys, k)
+func (db *MemDB) IngredientNames(ctx context.Context) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
-	sort.Strings(keys)
 
-	recipes := make([]persistence.Recipe, 0)
-	for _, k := range keys {
-		recipe := db.recipes[k]
-		if recipe.UsesIngredients(ingredients) {
-			recipes = append(recipes, recipe)
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	// The index only knows the normalised names, so collect the spellings from the recipes
+	seen := make(map[string]struct{}, len(db.index))
+	names := make([]string, 0, len(db.index))
+	for _, recipe := range db.recipes {
+		for _, ingredient := range recipe.Ingredients {
+			if _, ok := seen[ingredient.Name]; !ok {
+				seen[ingredient.Name] = struct{}{}
+				names = append(names, ingredient.Name)
+			}
 		}
 	}
 
-	return recipes, nil
+	return names, nil
+}
+
+func (db *MemDB) ListIngredients(ctx context.Context) ([]persistence.IngredientUsage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	ingredients := db.usage(func(string) bool { return true })
+	sort.Slice(ingredients, func(i, j int) bool { return ingredients[i].Name < ingredients[j].Name })
+
+	return ingredients, nil
+}
+
+func (db *MemDB) CompleteIngredient(ctx context.Context, prefix string, limit int) ([]persistence.IngredientUsage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if limit < 0 {
+		limit = 0
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	prefixes := persistence.CompletionPrefixes(prefix)
+	ingredients := db.usage(func(key string) bool { return persistence.CompletesTo(key, prefixes) })
+	persistence.SortByUsage(ingredients)
+	if len(ingredients) > limit {
+		ingredients = ingredients[:limit]
+	}
+
+	return ingredients, nil
+}
+
+func (db *MemDB) CatalogueStats(ctx context.Context, top int) (persistence.CatalogueStats, error) {
+	if err := ctx.Err(); err != nil {
+		return persistence.CatalogueStats{}, err
+	}
+	if top < 0 {
+		top = 0
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	stats := persistence.CatalogueStats{Recipes: len(db.recipes), Ingredients: len(db.index), IsolatedRecipes: []string{}}
+
+	// A recipe is isolated if it is the only one in the posting lists of all of its ingredients
+	total := 0
+	for _, name := range *db.names {
+		recipe := db.recipes[name]
+		total += len(recipe.Ingredients)
+		isolated := true
+		for _, ingredient := range recipe.Ingredients {
+			if len(db.index[persistence.NormaliseIngredient(ingredient.Name)]) > 1 {
+				isolated = false
+				break
+			}
+		}
+		if isolated {
+			stats.IsolatedRecipes = append(stats.IsolatedRecipes, name)
+		}
+	}
+	stats.AverageIngredients = persistence.Average(total, len(db.recipes))
+
+	ingredients := db.usage(func(string) bool { return true })
+	persistence.SortByUsage(ingredients)
+	n := top
+	if n > len(ingredients) {
+		n = len(ingredients)
+	}
+	stats.MostUsed = append([]persistence.IngredientUsage{}, ingredients[:n]...)
+
+	// The least used come last, but in name order among those used equally often
+	sort.SliceStable(ingredients, func(i, j int) bool { return ingredients[i].Recipes < ingredients[j].Recipes })
+	stats.LeastUsed = append([]persistence.IngredientUsage{}, ingredients[:n]...)
+
+	return stats, nil
+}
+
+func (db *MemDB) SimilarRecipes(ctx context.Context, name string, limit int) ([]persistence.SimilarRecipe, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if limit < 0 {
+		limit = 0
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	recipe, ok := db.recipes[name]
+	if !ok {
+		return nil, persistence.ErrNoResults
+	}
+
+	// Only the recipes in the posting lists of the ingredients of the recipe share any with it, so
+	// collect the shared ingredients of each of them from there rather than comparing every recipe
+	keys := db.keys[name]
+	shared := make(map[string][]string)
+	for i, key := range keys {
+		for other := range db.index[key] {
+			if other != name {
+				shared[other] = append(shared[other], recipe.Ingredients[i].Name)
+			}
+		}
+	}
+
+	similar := make([]persistence.SimilarRecipe, 0, len(shared))
+	for other, ingredients := range shared {
+		similar = append(similar, persistence.SimilarRecipe{
+			Name:       other,
+			Similarity: persistence.Jaccard(len(ingredients), len(keys), len(db.keys[other])),
+			Shared:     ingredients,
+		})
+	}
+	persistence.SortBySimilarity(similar)
+	if len(similar) > limit {
+		similar = similar[:limit]
+	}
+
+	return similar, nil
+}
+
+// usage counts the recipes which use each ingredient whose normalised name keep accepts, in no particular order.
+// The caller must hold db.mu.
+func (db *MemDB) usage(keep func(key string) bool) []persistence.IngredientUsage {
+	// The index counts the recipes, but only knows the normalised names, so find the spellings in the recipes
+	spellings := map[string]string{}
+	for _, recipe := range db.recipes {
+		for _, ingredient := range recipe.Ingredients {
+			key := persistence.NormaliseIngredient(ingredient.Name)
+			if !keep(key) {
+				continue
+			}
+			if spelling, ok := spellings[key]; !ok || ingredient.Name < spelling {
+				spellings[key] = ingredient.Name
+			}
+		}
+	}
+
+	ingredients := make([]persistence.IngredientUsage, 0, len(spellings))
+	for key, spelling := range spellings {
+		ingredients = append(ingredients, persistence.IngredientUsage{Name: spelling, Recipes: len(db.index[key])})
+	}
+
+	return ingredients
+}
+
+// findAll returns the names of the recipes which use all of the ingredients of the query, in no particular order.
+// The caller must hold db.mu.
+func (db *MemDB) findAll(query persistence.Query) []string {
+	// Every recipe uses all of no ingredients at all
+	ingredients := query.Ingredients
+	if len(ingredients) == 0 {
+		return append([]string{}, *db.names...)
+	}
+
+	// Intersect the posting lists of the requested ingredients, starting from
+	// the shortest one so that we check as few candidates as possible
+	lists := make([]map[string]struct{}, 0, len(ingredients))
+	for _, ingredient := range ingredients {
+		names := db.postings(query, ingredient)
+		if len(names) == 0 {
+			return []string{}
+		}
+		lists = append(lists, names)
+	}
+	sort.Slice(lists, func(i, j int) bool { return len(lists[i]) < len(lists[j]) })
+
+	names := make([]string, 0, len(lists[0]))
+	for name := range lists[0] {
+		found := true
+		for _, list := range lists[1:] {
+			if _, ok := list[name]; !ok {
+				found = false
+				break
+			}
+		}
+		if found {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// findSome returns the names of the recipes which use some of the ingredients of a MatchAny or
+// MatchSubset query and match it, in no particular order. The caller must hold db.mu.
+func (db *MemDB) findSome(query persistence.Query) []string {
+	// Collect the recipes in the posting lists of any of the requested ingredients
+	used := make(map[string]struct{})
+	for _, ingredient := range query.Ingredients {
+		for name := range db.postings(query, ingredient) {
+			used[name] = struct{}{}
+		}
+	}
+
+	names := make([]string, 0, len(used))
+	for name := range used {
+		recipe := db.recipes[name]
+		if query.Mode == persistence.MatchAny || len(query.MissingIngredients(&recipe)) <= query.MaxMissing {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// usesAny returns true if the named recipe uses at least one of the ingredients, as the query matches them.
+// The caller must hold db.mu.
+func (db *MemDB) usesAny(query persistence.Query, name string, ingredients []string) bool {
+	for _, ingredient := range ingredients {
+		if db.usesIngredient(query, name, ingredient) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// usesIngredient returns true if the named recipe, or one of its components all the way down, uses the ingredient
+// or one of its synonyms, or for an expanded query one of its kinds. The caller must hold db.mu.
+func (db *MemDB) usesIngredient(query persistence.Query, name string, ingredient string) bool {
+	for _, variant := range query.Variants(ingredient) {
+		if _, ok := db.index[variant][name]; ok {
+			return true
+		}
+	}
+
+	recipe := db.recipes[name]
+	for _, component := range recipe.Components() {
+		if db.usesIngredient(query, component, ingredient) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// postings returns the names of the recipes which use the ingredient or one of its synonyms, or for
+// an expanded query one of its kinds, themselves or through their components. The caller must hold db.mu,
+// and must not change the result.
+func (db *MemDB) postings(query persistence.Query, ingredient string) map[string]struct{} {
+	variants := query.Variants(ingredient)
+	if len(variants) == 1 {
+		return db.withUsers(db.index[variants[0]])
+	}
+
+	names := make(map[string]struct{})
+	for _, variant := range variants {
+		for name := range db.index[variant] {
+			names[name] = struct{}{}
+		}
+	}
+
+	return db.withUsers(names)
+}
+
+// withUsers returns the names together with the names of the recipes which use any of them as a component, all
+// the way up. It returns names itself if there are none, so the caller must hold db.mu and must not change either.
+func (db *MemDB) withUsers(names map[string]struct{}) map[string]struct{} {
+	if len(db.users) == 0 {
+		return names
+	}
+
+	all := names
+	pending := make([]string, 0, len(names))
+	for name := range names {
+		pending = append(pending, name)
+	}
+	for len(pending) > 0 {
+		name := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		for user := range db.users[name] {
+			if _, ok := all[user]; ok {
+				continue
+			}
+			if len(all) == len(names) {
+				// Copy before the first change, as names may be a posting list of the index
+				all = make(map[string]struct{}, len(names)+1)
+				for n := range names {
+					all[n] = struct{}{}
+				}
+			}
+			all[user] = struct{}{}
+			pending = append(pending, user)
+		}
+	}
+
+	return all
+}
+
+// uses returns a function which reports whether the named recipe uses an ingredient as the query matches it,
+// for evaluating an expression. The caller must hold db.mu while using it.
+func (db *MemDB) uses(query persistence.Query, name string) func(string) bool {
+	return func(ingredient string) bool {
+		return db.usesIngredient(query, name, ingredient)
+	}
+}
+
+// sortedRecipes returns copies of the named recipes in alphabetical order (by name).
+// The caller must hold db.mu.
+func (db *MemDB) sortedRecipes(names []string) []persistence.Recipe {
+	sort.Strings(names)
+
+	return db.copyRecipes(names)
+}
+
+// copyRecipes returns copies of the named recipes in the order of names.
+// The caller must hold db.mu.
+func (db *MemDB) copyRecipes(names []string) []persistence.Recipe {
+	recipes := make([]persistence.Recipe, 0, len(names))
+	for _, name := range names {
+		recipes = append(recipes, copyRecipe(db.recipes[name]))
+	}
+
+	return recipes
+}
+
+// add stores the recipe, replacing any recipe with the same name.
+// The caller must hold db.mu for writing.
+func (db *MemDB) add(recipe persistence.Recipe) {
+	if _, ok := db.recipes[recipe.Name]; ok {
+		db.unindex(recipe.Name)
+	} else {
+		names := *db.names
+		i := sort.SearchStrings(names, recipe.Name)
+		names = append(names, "")
+		copy(names[i+1:], names[i:])
+		names[i] = recipe.Name
+		*db.names = names
+
+		f := foldedName{key: persistence.FoldName(recipe.Name), name: recipe.Name}
+		folded := *db.folded
+		j := f.search(folded)
+		folded = append(folded, foldedName{})
+		copy(folded[j+1:], folded[j:])
+		folded[j] = f
+		*db.folded = folded
+	}
+	db.recipes[recipe.Name] = recipe
+	keys := make([]string, 0, len(recipe.Ingredients))
+	for _, ingredient := range recipe.Ingredients {
+		key := persistence.NormaliseIngredient(ingredient.Name)
+		keys = append(keys, key)
+		names, ok := db.index[key]
+		if !ok {
+			names = make(map[string]struct{})
+			db.index[key] = names
+		}
+		names[recipe.Name] = struct{}{}
+	}
+	db.keys[recipe.Name] = keys
+	for _, component := range recipe.Components() {
+		users, ok := db.users[component]
+		if !ok {
+			users = make(map[string]struct{})
+			db.users[component] = users
+		}
+		users[recipe.Name] = struct{}{}
+	}
+}
+
+// delete removes the named recipe if it exists.
+// The caller must hold db.mu for writing.
+func (db *MemDB) delete(name string) {
+	if _, ok := db.recipes[name]; ok {
+		db.unindex(name)
+		delete(db.recipes, name)
+
+		names := *db.names
+		i := sort.SearchStrings(names, name)
+		*db.names = append(names[:i], names[i+1:]...)
+
+		folded := *db.folded
+		j := foldedName{key: persistence.FoldName(name), name: name}.search(folded)
+		*db.folded = append(folded[:j], folded[j+1:]...)
+	}
+}
+
+// unindex removes the named recipe from the posting lists of all of its ingredients, and from the users of its components.
+// The caller must hold db.mu for writing.
+func (db *MemDB) unindex(name string) {
+	for _, key := range db.keys[name] {
+		names := db.index[key]
+		delete(names, name)
+		if len(names) == 0 {
+			delete(db.index, key)
+		}
+	}
+	delete(db.keys, name)
+
+	recipe := db.recipes[name]
+	for _, component := range recipe.Components() {
+		users := db.users[component]
+		delete(users, name)
+		if len(users) == 0 {
+			delete(db.users, component)
+		}
+	}
+}
+
+// foldedName is an entry of the name search index of a MemDB
+type foldedName struct {
+	key  string // persistence.FoldName of the name
+	name string
+}
+
+// search returns the index of the first entry of the sorted index which does not sort before f
+func (f foldedName) search(index []foldedName) int {
+	return sort.Search(len(index), func(i int) bool {
+		return index[i].key > f.key || index[i].key == f.key && index[i].name >= f.name
+	})
+}
+
+// copyRecipe returns a copy of recipe which shares no memory with the original
+func copyRecipe(recipe persistence.Recipe) persistence.Recipe {
+	if recipe.Ingredients != nil {
+		ingredients := make([]persistence.Ingredient, len(recipe.Ingredients))
+		copy(ingredients, recipe.Ingredients)
+		recipe.Ingredients = ingredients
+	}
+	if recipe.Instructions != nil {
+		instructions := make([]string, len(recipe.Instructions))
+		copy(instructions, recipe.Instructions)
+		recipe.Instructions = instructions
+	}
+
+	return recipe
 }
@@ -1,8 +1,12 @@
 package memdb
 
 import (
+	"context"
+	"fmt"
 	"go-incubator/internal/persistence"
+	"go-incubator/internal/persistence/persistencetest"
 	"reflect"
+	"sync"
 	"testing"
 )
 
@@ -14,7 +18,7 @@ func TestNewMemDB(t *testing.T) {
 	}{
 		{
 			name:    "1",
-			want:    MemDB{recipes: make(map[string]persistence.Recipe)},
+			want:    MemDB{mu: &sync.RWMutex{}, recipes: make(map[string]persistence.Recipe), index: make(map[string]map[string]struct{}), keys: make(map[string][]string), users: make(map[string]map[string]struct{}), names: &[]string{}, folded: &[]foldedName{}},
 			wantErr: false,
 		},
 	}
@@ -44,28 +48,28 @@ func TestMemDB_AddRecipe(t *testing.T) {
 		{
 			name:    "1",
 			db:      &db,
-			recipe:  persistence.Recipe{Name: "Meatballs", Ingredients: []string{"Ground Beef", "Tomato"}},
+			recipe:  persistence.Recipe{Name: "Meatballs", Ingredients: persistence.NamedIngredients([]string{"Ground Beef", "Tomato"})},
 			wantErr: false,
 			wantLen: 1,
 		},
 		{
 			name:    "2",
 			db:      &db,
-			recipe:  persistence.Recipe{Name: "Meatballs", Ingredients: []string{"Tomato", "Ground Beef"}},
+			recipe:  persistence.Recipe{Name: "Meatballs", Ingredients: persistence.NamedIngredients([]string{"Tomato", "Ground Beef"})},
 			wantErr: false,
 			wantLen: 1,
 		},
 		{
 			name:    "3",
 			db:      &db,
-			recipe:  persistence.Recipe{Name: "Cheese Fondue", Ingredients: []string{"Gruyere", "Emmental"}},
+			recipe:  persistence.Recipe{Name: "Cheese Fondue", Ingredients: persistence.NamedIngredients([]string{"Gruyere", "Emmental"})},
 			wantErr: false,
 			wantLen: 2,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := tt.db.AddRecipe(tt.recipe); (err != nil) != tt.wantErr {
+			if err := tt.db.AddRecipe(context.Background(), tt.recipe); (err != nil) != tt.wantErr {
 				t.Errorf("MemDB.AddRecipe() error = %v, wantErr %v", err, tt.wantErr)
 			}
 			if len(db.recipes) != tt.wantLen {
@@ -77,13 +81,13 @@ func TestMemDB_AddRecipe(t *testing.T) {
 
 func TestMemDB_GetRecipe(t *testing.T) {
 	db, _ := NewMemDB()
-	db.recipes["Cheese Fondue"] = persistence.Recipe{Name: "Cheese Fondue", Ingredients: []string{"Gruyere", "Emmental"}}
-	db.recipes["Mac & Cheese"] = persistence.Recipe{Name: "Mac & Cheese", Ingredients: []string{"Mozzarella", "Macaroni"}}
-	db.recipes["SpagBol"] = persistence.Recipe{Name: "SpagBol", Ingredients: []string{"Spaghetti", "Ground Beef", "Tomato"}}
-	db.recipes["BLT"] = persistence.Recipe{Name: "BLT", Ingredients: []string{"Tomato", "Bacon", "Lettuce"}}
-	db.recipes["Greek Salad"] = persistence.Recipe{Name: "Greek Salad", Ingredients: []string{"Feta", "Tomato", "Cucumber"}}
-	db.recipes["Caprese Salad"] = persistence.Recipe{Name: "Caprese Salad", Ingredients: []string{"Mozzarella", "Tomato"}}
-	db.recipes["Meatballs"] = persistence.Recipe{Name: "Meatballs", Ingredients: []string{"Ground Beef", "Tomato"}}
+	db.AddRecipe(context.Background(), persistence.Recipe{Name: "Cheese Fondue", Ingredients: persistence.NamedIngredients([]string{"Gruyere", "Emmental"})})
+	db.AddRecipe(context.Background(), persistence.Recipe{Name: "Mac & Cheese", Ingredients: persistence.NamedIngredients([]string{"Mozzarella", "Macaroni"})})
+	db.AddRecipe(context.Background(), persistence.Recipe{Name: "SpagBol", Ingredients: persistence.NamedIngredients([]string{"Spaghetti", "Ground Beef", "Tomato"})})
+	db.AddRecipe(context.Background(), persistence.Recipe{Name: "BLT", Ingredients: persistence.NamedIngredients([]string{"Tomato", "Bacon", "Lettuce"})})
+	db.AddRecipe(context.Background(), persistence.Recipe{Name: "Greek Salad", Ingredients: persistence.NamedIngredients([]string{"Feta", "Tomato", "Cucumber"})})
+	db.AddRecipe(context.Background(), persistence.Recipe{Name: "Caprese Salad", Ingredients: persistence.NamedIngredients([]string{"Mozzarella", "Tomato"})})
+	db.AddRecipe(context.Background(), persistence.Recipe{Name: "Meatballs", Ingredients: persistence.NamedIngredients([]string{"Ground Beef", "Tomato"})})
 
 	tests := []struct {
 		name    string
@@ -96,14 +100,14 @@ func TestMemDB_GetRecipe(t *testing.T) {
 			name:    "1",
 			db:      &db,
 			rname:   "Cheese Fondue",
-			want:    persistence.Recipe{Name: "Cheese Fondue", Ingredients: []string{"Gruyere", "Emmental"}},
+			want:    persistence.Recipe{Name: "Cheese Fondue", Ingredients: persistence.NamedIngredients([]string{"Gruyere", "Emmental"})},
 			wantErr: false,
 		},
 		{
 			name:    "2",
 			db:      &db,
 			rname:   "SpagBol",
-			want:    persistence.Recipe{Name: "SpagBol", Ingredients: []string{"Spaghetti", "Ground Beef", "Tomato"}},
+			want:    persistence.Recipe{Name: "SpagBol", Ingredients: persistence.NamedIngredients([]string{"Spaghetti", "Ground Beef", "Tomato"})},
 			wantErr: false,
 		},
 		{
@@ -116,7 +120,7 @@ func TestMemDB_GetRecipe(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := tt.db.GetRecipe(tt.rname)
+			got, err := tt.db.GetRecipe(context.Background(), tt.rname)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("MemDB.GetRecipe() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -128,15 +132,61 @@ func TestMemDB_GetRecipe(t *testing.T) {
 	}
 }
 
+func TestMemDB_DeleteRecipe(t *testing.T) {
+	db, _ := NewMemDB()
+	db.AddRecipe(context.Background(), persistence.Recipe{Name: "Cheese Fondue", Ingredients: persistence.NamedIngredients([]string{"Gruyere", "Emmental"})})
+	db.AddRecipe(context.Background(), persistence.Recipe{Name: "BLT", Ingredients: persistence.NamedIngredients([]string{"Tomato", "Bacon", "Lettuce"})})
+
+	tests := []struct {
+		name    string
+		db      *MemDB
+		rname   string
+		wantErr error
+		wantLen int
+	}{
+		{
+			name:    "1",
+			db:      &db,
+			rname:   "BLT",
+			wantErr: nil,
+			wantLen: 1,
+		},
+		{
+			name:    "2",
+			db:      &db,
+			rname:   "BLT",
+			wantErr: persistence.ErrNoResults,
+			wantLen: 1,
+		},
+		{
+			name:    "3",
+			db:      &db,
+			rname:   "Cheese Fondue",
+			wantErr: nil,
+			wantLen: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.db.DeleteRecipe(context.Background(), tt.rname); err != tt.wantErr {
+				t.Errorf("MemDB.DeleteRecipe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(db.recipes) != tt.wantLen {
+				t.Errorf("MemDB.DeleteRecipe() len = %v, wantLen %v", len(db.recipes), tt.wantLen)
+			}
+		})
+	}
+}
+
 func TestMemDB_FindRecipes(t *testing.T) {
 	db, _ := NewMemDB()
-	db.recipes["Cheese Fondue"] = persistence.Recipe{Name: "Cheese Fondue", Ingredients: []string{"Gruyere", "Emmental"}}
-	db.recipes["Mac & Cheese"] = persistence.Recipe{Name: "Mac & Cheese", Ingredients: []string{"Mozzarella", "Macaroni"}}
-	db.recipes["SpagBol"] = persistence.Recipe{Name: "SpagBol", Ingredients: []string{"Spaghetti", "Ground Beef", "Tomato"}}
-	db.recipes["BLT"] = persistence.Recipe{Name: "BLT", Ingredients: []string{"Tomato", "Bacon", "Lettuce"}}
-	db.recipes["Greek Salad"] = persistence.Recipe{Name: "Greek Salad", Ingredients: []string{"Feta", "Tomato", "Cucumber"}}
-	db.recipes["Caprese Salad"] = persistence.Recipe{Name: "Caprese Salad", Ingredients: []string{"Mozzarella", "Tomato"}}
-	db.recipes["Meatballs"] = persistence.Recipe{Name: "Meatballs", Ingredients: []string{"Ground Beef", "Tomato"}}
+	db.AddRecipe(context.Background(), persistence.Recipe{Name: "Cheese Fondue", Ingredients: persistence.NamedIngredients([]string{"Gruyere", "Emmental"})})
+	db.AddRecipe(context.Background(), persistence.Recipe{Name: "Mac & Cheese", Ingredients: persistence.NamedIngredients([]string{"Mozzarella", "Macaroni"})})
+	db.AddRecipe(context.Background(), persistence.Recipe{Name: "SpagBol", Ingredients: persistence.NamedIngredients([]string{"Spaghetti", "Ground Beef", "Tomato"})})
+	db.AddRecipe(context.Background(), persistence.Recipe{Name: "BLT", Ingredients: persistence.NamedIngredients([]string{"Tomato", "Bacon", "Lettuce"})})
+	db.AddRecipe(context.Background(), persistence.Recipe{Name: "Greek Salad", Ingredients: persistence.NamedIngredients([]string{"Feta", "Tomato", "Cucumber"})})
+	db.AddRecipe(context.Background(), persistence.Recipe{Name: "Caprese Salad", Ingredients: persistence.NamedIngredients([]string{"Mozzarella", "Tomato"})})
+	db.AddRecipe(context.Background(), persistence.Recipe{Name: "Meatballs", Ingredients: persistence.NamedIngredients([]string{"Ground Beef", "Tomato"})})
 
 	tests := []struct {
 		name        string
@@ -149,19 +199,19 @@ func TestMemDB_FindRecipes(t *testing.T) {
 			name:        "1",
 			db:          &db,
 			ingredients: []string{"Gruyere", "Emmental"},
-			want:        []persistence.Recipe{{Name: "Cheese Fondue", Ingredients: []string{"Gruyere", "Emmental"}}},
+			want:        []persistence.Recipe{{Name: "Cheese Fondue", Ingredients: persistence.NamedIngredients([]string{"Gruyere", "Emmental"})}},
 		},
 		{
 			name:        "2",
 			db:          &db,
 			ingredients: []string{"Emmental", "Gruyere"},
-			want:        []persistence.Recipe{{Name: "Cheese Fondue", Ingredients: []string{"Gruyere", "Emmental"}}},
+			want:        []persistence.Recipe{{Name: "Cheese Fondue", Ingredients: persistence.NamedIngredients([]string{"Gruyere", "Emmental"})}},
 		},
 		{
 			name:        "3",
 			db:          &db,
 			ingredients: []string{"Tomato"},
-			want:        []persistence.Recipe{{Name: "BLT", Ingredients: []string{"Tomato", "Bacon", "Lettuce"}}, {Name: "Caprese Salad", Ingredients: []string{"Mozzarella", "Tomato"}}, {Name: "Greek Salad", Ingredients: []string{"Feta", "Tomato", "Cucumber"}}, {Name: "Meatballs", Ingredients: []string{"Ground Beef", "Tomato"}}, {Name: "SpagBol", Ingredients: []string{"Spaghetti", "Ground Beef", "Tomato"}}},
+			want:        []persistence.Recipe{{Name: "BLT", Ingredients: persistence.NamedIngredients([]string{"Tomato", "Bacon", "Lettuce"})}, {Name: "Caprese Salad", Ingredients: persistence.NamedIngredients([]string{"Mozzarella", "Tomato"})}, {Name: "Greek Salad", Ingredients: persistence.NamedIngredients([]string{"Feta", "Tomato", "Cucumber"})}, {Name: "Meatballs", Ingredients: persistence.NamedIngredients([]string{"Ground Beef", "Tomato"})}, {Name: "SpagBol", Ingredients: persistence.NamedIngredients([]string{"Spaghetti", "Ground Beef", "Tomato"})}},
 		},
 		{
 			name:        "4",
@@ -172,7 +222,7 @@ func TestMemDB_FindRecipes(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := tt.db.FindRecipes(tt.ingredients)
+			got, err := tt.db.FindRecipes(context.Background(), tt.ingredients)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("MemDB.FindRecipes() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -183,3 +233,152 @@ func TestMemDB_FindRecipes(t *testing.T) {
 		})
 	}
 }
+
+func TestMemDB_CancelledContext(t *testing.T) {
+	db, _ := NewMemDB()
+	db.AddRecipe(context.Background(), persistence.Recipe{Name: "BLT", Ingredients: persistence.NamedIngredients([]string{"Tomato", "Bacon", "Lettuce"})})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{
+			name: "AddRecipe",
+			call: func() error {
+				return db.AddRecipe(ctx, persistence.Recipe{Name: "Meatballs", Ingredients: persistence.NamedIngredients([]string{"Ground Beef", "Tomato"})})
+			},
+		},
+		{
+			name: "GetRecipe",
+			call: func() error {
+				_, err := db.GetRecipe(ctx, "BLT")
+				return err
+			},
+		},
+		{
+			name: "FindRecipes",
+			call: func() error {
+				_, err := db.FindRecipes(ctx, []string{"Tomato"})
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); err != context.Canceled {
+				t.Errorf("MemDB.%s() error = %v, want %v", tt.name, err, context.Canceled)
+			}
+		})
+	}
+
+	if len(db.recipes) != 1 {
+		t.Errorf("MemDB.AddRecipe() with cancelled context stored a recipe, len = %v, want 1", len(db.recipes))
+	}
+}
+
+func TestMemDB_Index(t *testing.T) {
+	db, _ := NewMemDB()
+	ctx := context.Background()
+	db.AddRecipe(ctx, persistence.Recipe{Name: "Meatballs", Ingredients: persistence.NamedIngredients([]string{"Ground Beef", "Tomato"})})
+	db.AddRecipe(ctx, persistence.Recipe{Name: "BLT", Ingredients: persistence.NamedIngredients([]string{"Tomato", "Bacon", "Lettuce"})})
+	db.AddRecipe(ctx, persistence.Recipe{Name: "Meatballs", Ingredients: persistence.NamedIngredients([]string{"Ground Beef", "Onion"})})
+	db.DeleteRecipe(ctx, "BLT")
+
+	tests := []struct {
+		name        string
+		ingredients []string
+		want        []persistence.Recipe
+	}{
+		{
+			name:        "1",
+			ingredients: []string{"Tomato"},
+			want:        []persistence.Recipe{},
+		},
+		{
+			name:        "2",
+			ingredients: []string{"Onion", "Ground Beef"},
+			want:        []persistence.Recipe{{Name: "Meatballs", Ingredients: persistence.NamedIngredients([]string{"Ground Beef", "Onion"})}},
+		},
+		{
+			name:        "3",
+			ingredients: []string{},
+			want:        []persistence.Recipe{{Name: "Meatballs", Ingredients: persistence.NamedIngredients([]string{"Ground Beef", "Onion"})}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := db.FindRecipes(ctx, tt.ingredients)
+			if err != nil {
+				t.Errorf("MemDB.FindRecipes() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MemDB.FindRecipes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if len(db.index) != 2 {
+		t.Errorf("MemDB index len = %v, want 2", len(db.index))
+	}
+}
+
+func TestMemDB_DefensiveCopies(t *testing.T) {
+	db, _ := NewMemDB()
+	ctx := context.Background()
+	want := persistence.Recipe{Name: "BLT", Ingredients: persistence.NamedIngredients([]string{"Tomato", "Bacon", "Lettuce"})}
+
+	added := persistence.Recipe{Name: "BLT", Ingredients: persistence.NamedIngredients([]string{"Tomato", "Bacon", "Lettuce"})}
+	db.AddRecipe(ctx, added)
+	added.Ingredients[0].Name = "Changed by AddRecipe caller"
+
+	got, _ := db.GetRecipe(ctx, "BLT")
+	got.Ingredients[1].Name = "Changed by GetRecipe caller"
+
+	found, _ := db.FindRecipes(ctx, []string{"Bacon"})
+	found[0].Ingredients[2].Name = "Changed by FindRecipes caller"
+
+	got, _ = db.GetRecipe(ctx, "BLT")
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MemDB.GetRecipe() = %v, want %v", got, want)
+	}
+}
+
+func TestMemDB_Concurrency(t *testing.T) {
+	db, _ := NewMemDB()
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("Recipe %d", i%5)
+			for j := 0; j < 100; j++ {
+				db.AddRecipe(ctx, persistence.Recipe{Name: name, Ingredients: persistence.NamedIngredients([]string{"Tomato", fmt.Sprintf("Ingredient %d", j%3)})})
+				db.GetRecipe(ctx, name)
+				db.FindRecipes(ctx, []string{"Tomato"})
+				if j%10 == 0 {
+					db.DeleteRecipe(ctx, name)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// Every recipe that is still stored must be reachable through the index
+	found, _ := db.FindRecipes(ctx, []string{"Tomato"})
+	if len(found) != len(db.recipes) {
+		t.Errorf("MemDB.FindRecipes() len = %v, want %v", len(found), len(db.recipes))
+	}
+}
+
+func TestMemDB_Conformance(t *testing.T) {
+	persistencetest.Run(t, func(t *testing.T) persistence.Persistence {
+		db, _ := NewMemDB()
+		return &db
+	})
+}
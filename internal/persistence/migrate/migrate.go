@@ -0,0 +1,266 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Migration is a single versioned schema change
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Migrator applies and reverts Migrations, recording the applied versions in the schema_migrations table
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// fileName matches migration files such as 0001_create_recipes.up.sql
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// NewMigrator creates and returns a new Migrator for the migrations found in the root of fsys
+func NewMigrator(db *sql.DB, fsys fs.FS) (Migrator, error) {
+	m := Migrator{db: db}
+
+	migrations, err := Load(fsys)
+	if err != nil {
+		return m, err
+	}
+	m.migrations = migrations
+
+	return m, nil
+}
+
+// Load reads all migration files in the root of fsys and returns them in version order.
+// Every version needs both an up and a down file.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("reading migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names (%s, %s)", version, m.Name, match[2])
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("reading migration %s: %w", entry.Name(), err)
+		}
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d (%s) needs both an up and a down file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// Statements splits a migration script into its individual statements, so that
+// drivers which do not allow several statements per call can still execute it.
+// A statement ends with a semicolon at the end of a line (or before a -- comment),
+// outside quotes and comments, so semicolons may appear in string literals, defaults
+// and comments. Quotes within quotes are escaped by doubling them. Statements which
+// hold nothing but comments are dropped.
+func Statements(script string) []string {
+	var stmts []string
+	start, code := 0, false
+	for i := 0; i < len(script); {
+		switch c := script[i]; {
+		case c == ';' && endsLine(script[i+1:]):
+			if code {
+				stmts = append(stmts, strings.TrimSpace(script[start:i]))
+			}
+			// A comment after the semicolon belongs to the statement it ends
+			i += 1 + lineLength(script[i+1:])
+			start, code = i, false
+		case strings.HasPrefix(script[i:], "--"):
+			i += lineLength(script[i:])
+		case strings.HasPrefix(script[i:], "/*"):
+			if end := strings.Index(script[i+2:], "*/"); end >= 0 {
+				i += end + 4
+			} else {
+				i = len(script)
+			}
+		case c == '\'' || c == '"' || c == '`':
+			i = quoteEnd(script, i)
+			code = true
+		default:
+			if !strings.ContainsRune(" \t\r\n", rune(c)) {
+				code = true
+			}
+			i++
+		}
+	}
+	if code {
+		stmts = append(stmts, strings.TrimSpace(script[start:]))
+	}
+
+	return stmts
+}
+
+// endsLine returns true if rest, which follows a semicolon, holds nothing but whitespace
+// or a -- comment up to the end of its line
+func endsLine(rest string) bool {
+	line := strings.TrimSpace(rest[:lineLength(rest)])
+
+	return line == "" || strings.HasPrefix(line, "--")
+}
+
+// lineLength returns the length of the first line of s, without its line break
+func lineLength(s string) int {
+	if n := strings.IndexByte(s, '\n'); n >= 0 {
+		return n
+	}
+
+	return len(s)
+}
+
+// quoteEnd returns the index just after the quoted string or identifier which starts at i
+func quoteEnd(script string, i int) int {
+	quote := script[i]
+	for j := i + 1; j < len(script); j++ {
+		if script[j] != quote {
+			continue
+		}
+		if j+1 < len(script) && script[j+1] == quote {
+			j++
+			continue
+		}
+		return j + 1
+	}
+
+	return len(script)
+}
+
+// Version returns the highest applied migration version, or 0 if none have been applied
+func (m *Migrator) Version(ctx context.Context) (int, error) {
+	if err := m.init(ctx); err != nil {
+		return 0, err
+	}
+
+	var version sql.NullInt64
+	err := m.db.QueryRowContext(ctx, "SELECT MAX(version) FROM schema_migrations").Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("reading schema version: %w", err)
+	}
+
+	return int(version.Int64), nil
+}
+
+// Up applies all migrations that have not been applied yet, in version order
+func (m *Migrator) Up(ctx context.Context) error {
+	current, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range m.migrations {
+		if migration.Version <= current {
+			continue
+		}
+		err := m.apply(ctx, migration.Up, "INSERT INTO schema_migrations (version, name) VALUES (?, ?)", migration.Version, migration.Name)
+		if err != nil {
+			return fmt.Errorf("applying migration %d (%s): %w", migration.Version, migration.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// Down reverts the specified number of most recently applied migrations
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	current, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := len(m.migrations) - 1; i >= 0 && steps > 0; i-- {
+		migration := m.migrations[i]
+		if migration.Version > current {
+			continue
+		}
+		err := m.apply(ctx, migration.Down, "DELETE FROM schema_migrations WHERE version = ?", migration.Version)
+		if err != nil {
+			return fmt.Errorf("reverting migration %d (%s): %w", migration.Version, migration.Name, err)
+		}
+		steps--
+	}
+
+	return nil
+}
+
+// init creates the schema_migrations table if it does not exist yet
+func (m *Migrator) init(ctx context.Context) error {
+	_, err := m.db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INT NOT NULL PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`,
+	)
+	if err != nil {
+		return fmt.Errorf("creating schema_migrations table: %w", err)
+	}
+
+	return nil
+}
+
+// apply executes a migration script and records the change in schema_migrations in a single transaction.
+// Note that some databases (e.g. MySQL) commit DDL statements implicitly.
+func (m *Migrator) apply(ctx context.Context, script string, record string, args ...any) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range Statements(script) {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return fmt.Errorf("recording migration: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package migrate
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		fsys    fstest.MapFS
+		want    []Migration
+		wantErr bool
+	}{
+		{
+			name: "1",
+			fsys: fstest.MapFS{
+				"0002_add_index.up.sql":      {Data: []byte("CREATE INDEX x ON t (c);")},
+				"0002_add_index.down.sql":    {Data: []byte("DROP INDEX x ON t;")},
+				"0001_create_table.up.sql":   {Data: []byte("CREATE TABLE t (c INT);")},
+				"0001_create_table.down.sql": {Data: []byte("DROP TABLE t;")},
+				"README.md":                  {Data: []byte("not a migration")},
+			},
+			want: []Migration{
+				{Version: 1, Name: "create_table", Up: "CREATE TABLE t (c INT);", Down: "DROP TABLE t;"},
+				{Version: 2, Name: "add_index", Up: "CREATE INDEX x ON t (c);", Down: "DROP INDEX x ON t;"},
+			},
+		},
+		{
+			name: "2",
+			fsys: fstest.MapFS{
+				"0001_create_table.up.sql": {Data: []byte("CREATE TABLE t (c INT);")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "3",
+			fsys: fstest.MapFS{
+				"0001_create_table.up.sql": {Data: []byte("CREATE TABLE t (c INT);")},
+				"0001_drop_table.down.sql": {Data: []byte("DROP TABLE t;")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "4",
+			fsys: fstest.MapFS{},
+			want: []Migration{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Load(tt.fsys)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Load() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Load() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{
+			name:   "1",
+			script: "CREATE TABLE a (id INT);\n\nCREATE TABLE b (id INT);\n",
+			want:   []string{"CREATE TABLE a (id INT)", "CREATE TABLE b (id INT)"},
+		},
+		{
+			name:   "2",
+			script: "DROP TABLE a",
+			want:   []string{"DROP TABLE a"},
+		},
+		{
+			name:   "3",
+			script: " ;\n ",
+			want:   nil,
+		},
+		{
+			name:   "4",
+			script: "ALTER TABLE a ADD COLUMN s VARCHAR(8) NOT NULL DEFAULT ';';\nINSERT INTO a (s) VALUES ('it''s; fine');\n",
+			want:   []string{"ALTER TABLE a ADD COLUMN s VARCHAR(8) NOT NULL DEFAULT ';'", "INSERT INTO a (s) VALUES ('it''s; fine')"},
+		},
+		{
+			name:   "5",
+			script: "-- Create a; then b\nCREATE TABLE a (id INT); -- done\n/* b;\n*/\nCREATE TABLE b (id INT);\n-- trailing; comment\n",
+			want:   []string{"-- Create a; then b\nCREATE TABLE a (id INT)", "/* b;\n*/\nCREATE TABLE b (id INT)"},
+		},
+		{
+			name:   "6",
+			script: "SELECT 1; SELECT 2;\n",
+			want:   []string{"SELECT 1; SELECT 2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Statements(tt.script); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Statements() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package mysqldb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"go-incubator/internal/persistence"
+	"go-incubator/internal/persistence/persistencetest"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"modernc.org/sqlite"
+)
+
+// standIn is the name of a database/sql driver which runs MySqlDB against SQLite, so that
+// the conformance suite does not need a MySQL server. It rewrites the few pieces of MySQL
+// syntax that our statements and migrations use and SQLite does not understand. It therefore
+// only checks the SQL which sqldb shares between backends, not MySQL itself: neither its
+// syntax, its locks nor its case-insensitive collations. TestMySqlDB_Server does that.
+const standIn = "mysql-standin"
+
+// rewrites turns MySQL syntax into its SQLite equivalent
+var rewrites = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	{regexp.MustCompile(`INSERT IGNORE`), "INSERT OR IGNORE"},
+	{regexp.MustCompile(`INT NOT NULL AUTO_INCREMENT`), "INTEGER NOT NULL"},
+	{regexp.MustCompile(`UNIQUE KEY (\w+)`), "CONSTRAINT $1 UNIQUE"},
+	{regexp.MustCompile(`\n\s*KEY \w+ \(\w+\),`), ""},
+	{regexp.MustCompile(`COLLATE utf8mb4_bin`), "COLLATE BINARY"},
+	{regexp.MustCompile(`DROP INDEX (\w+) ON \w+`), "DROP INDEX $1"},
+}
+
+func init() {
+	// Every connection may take the migration lock, as there is only ever one
+	sqlite.MustRegisterScalarFunction("GET_LOCK", 2, func(*sqlite.FunctionContext, []driver.Value) (driver.Value, error) {
+		return int64(1), nil
+	})
+	sqlite.MustRegisterScalarFunction("RELEASE_LOCK", 1, func(*sqlite.FunctionContext, []driver.Value) (driver.Value, error) {
+		return int64(1), nil
+	})
+
+	// Functions are only registered with the driver which modernc.org/sqlite registers itself
+	db, err := sql.Open("sqlite", "")
+	if err != nil {
+		panic(err)
+	}
+	sql.Register(standIn, standInDriver{sqlite: db.Driver()})
+}
+
+type standInDriver struct {
+	sqlite driver.Driver
+}
+
+func (d standInDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.sqlite.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return standInConn{conn}, nil
+}
+
+// standInConn only exposes Prepare for running statements, so database/sql
+// sends every query through it and therefore through the rewrites
+type standInConn struct {
+	driver.Conn
+}
+
+func (c standInConn) Prepare(query string) (driver.Stmt, error) {
+	for _, r := range rewrites {
+		query = r.re.ReplaceAllString(query, r.repl)
+	}
+
+	return c.Conn.Prepare(query)
+}
+
+func (c standInConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return c.Conn.(driver.ConnBeginTx).BeginTx(ctx, opts)
+}
+
+// newStandInDB returns a migrated MySqlDB which stores its data in a temporary SQLite file
+func newStandInDB(t *testing.T) MySqlDB {
+	db, err := sql.Open(standIn, "file:"+filepath.Join(t.TempDir(), "recipes.db")+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_txlock=immediate")
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	// The migration lock holds on to one connection while the migrations run on another.
+	// Transactions take the SQLite write lock up front, so that they wait for each other
+	// rather than fail.
+	db.SetMaxOpenConns(2)
+
+	msdb, err := newMySqlDB(db)
+	if err != nil {
+		t.Fatalf("newMySqlDB() error = %v", err)
+	}
+	if err := msdb.MigrateUp(context.Background()); err != nil {
+		t.Fatalf("MySqlDB.MigrateUp() error = %v", err)
+	}
+
+	return msdb
+}
+
+// TestMySqlDB_StandInConformance runs the conformance suite against the SQLite stand-in, which checks the shared SQL only
+func TestMySqlDB_StandInConformance(t *testing.T) {
+	persistencetest.Run(t, func(t *testing.T) persistence.Persistence {
+		db := newStandInDB(t)
+		return &db
+	})
+}
+
+// TestMySqlDB_StandInMigrations reverts and reapplies the migrations on the SQLite stand-in
+func TestMySqlDB_StandInMigrations(t *testing.T) {
+	db := newStandInDB(t)
+	ctx := context.Background()
+
+	version, err := db.SchemaVersion(ctx)
+	if err != nil || version == 0 {
+		t.Fatalf("MySqlDB.SchemaVersion() = %v, %v, want a migrated schema", version, err)
+	}
+
+	if err := db.MigrateDown(ctx, version); err != nil {
+		t.Fatalf("MySqlDB.MigrateDown() error = %v", err)
+	}
+	if version, _ := db.SchemaVersion(ctx); version != 0 {
+		t.Errorf("MySqlDB.SchemaVersion() = %v, want 0", version)
+	}
+
+	if err := db.MigrateUp(ctx); err != nil {
+		t.Fatalf("MySqlDB.MigrateUp() error = %v", err)
+	}
+}
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS recipes (
+    id INT NOT NULL AUTO_INCREMENT,
+    name VARCHAR(255) NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY recipes_name (name)
+);
+
+CREATE TABLE IF NOT EXISTS ingredients (
+    id INT NOT NULL AUTO_INCREMENT,
+    name VARCHAR(255) NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY ingredients_name (name)
+);
+
+CREATE TABLE IF NOT EXISTS recipe_ingredients (
+    recipe_id INT NOT NULL,
+    ingredient_id INT NOT NULL,
+    PRIMARY KEY (recipe_id, ingredient_id),
+    KEY recipe_ingredients_ingredient (ingredient_id),
+    CONSTRAINT fk_recipe_ingredients_recipe FOREIGN KEY (recipe_id) REFERENCES recipes (id) ON DELETE CASCADE,
+    CONSTRAINT fk_recipe_ingredients_ingredient FOREIGN KEY (ingredient_id) REFERENCES ingredients (id)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE recipe_ingredients ADD COLUMN quantity DOUBLE NOT NULL DEFAULT 0;
+ALTER TABLE recipe_ingredients ADD COLUMN unit VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE recipe_ingredients ADD COLUMN note VARCHAR(255) NOT NULL DEFAULT '';
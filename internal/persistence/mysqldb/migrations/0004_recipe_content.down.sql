@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS recipe_steps;
+
+ALTER TABLE recipes DROP COLUMN source;
+ALTER TABLE recipes DROP COLUMN cook_minutes;
+ALTER TABLE recipes DROP COLUMN prep_minutes;
+ALTER TABLE recipes DROP COLUMN servings;
+ALTER TABLE recipes DROP COLUMN description;
@@ -0,0 +1,13 @@
+ALTER TABLE recipes ADD COLUMN description VARCHAR(2048) NOT NULL DEFAULT '';
+ALTER TABLE recipes ADD COLUMN servings INT NOT NULL DEFAULT 0;
+ALTER TABLE recipes ADD COLUMN prep_minutes INT NOT NULL DEFAULT 0;
+ALTER TABLE recipes ADD COLUMN cook_minutes INT NOT NULL DEFAULT 0;
+ALTER TABLE recipes ADD COLUMN source VARCHAR(512) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS recipe_steps (
+    recipe_id INT NOT NULL,
+    position INT NOT NULL,
+    instruction TEXT NOT NULL,
+    PRIMARY KEY (recipe_id, position),
+    CONSTRAINT fk_recipe_steps_recipe FOREIGN KEY (recipe_id) REFERENCES recipes (id) ON DELETE CASCADE
+);
@@ -0,0 +1,4 @@
+ALTER TABLE recipe_ingredients DROP COLUMN display_name;
+
+DROP INDEX ingredients_search_name ON ingredients;
+ALTER TABLE ingredients DROP COLUMN search_name;
@@ -0,0 +1,5 @@
+ALTER TABLE ingredients ADD COLUMN search_name VARCHAR(255) COLLATE utf8mb4_bin NOT NULL DEFAULT '';
+CREATE INDEX ingredients_search_name ON ingredients (search_name);
+
+ALTER TABLE recipe_ingredients ADD COLUMN display_name VARCHAR(255) NOT NULL DEFAULT '';
+UPDATE recipe_ingredients SET display_name = (SELECT name FROM ingredients WHERE ingredients.id = recipe_ingredients.ingredient_id);
@@ -0,0 +1,2 @@
+DROP INDEX recipes_search_name ON recipes;
+ALTER TABLE recipes DROP COLUMN search_name;
@@ -0,0 +1,2 @@
+ALTER TABLE recipes ADD COLUMN search_name VARCHAR(255) COLLATE utf8mb4_bin NOT NULL DEFAULT '';
+CREATE INDEX recipes_search_name ON recipes (search_name);
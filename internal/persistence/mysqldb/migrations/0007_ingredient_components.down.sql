@@ -0,0 +1,2 @@
+DROP INDEX recipe_ingredients_component ON recipe_ingredients;
+ALTER TABLE recipe_ingredients DROP COLUMN component;
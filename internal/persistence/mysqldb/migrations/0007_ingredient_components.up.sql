@@ -0,0 +1,2 @@
+ALTER TABLE recipe_ingredients ADD COLUMN component BOOLEAN NOT NULL DEFAULT 0;
+CREATE INDEX recipe_ingredients_component ON recipe_ingredients (display_name, component);
@@ -1,153 +1,93 @@
 package mysqldb
 
 import (
+	"context"
 	"database/sql"
+	"embed"
 	"fmt"
-	"go-incubator/internal/persistence"
-	"strings"
+	"go-incubator/internal/persistence/sqldb"
+	"io/fs"
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 )
 
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// migrationLock is the name of the MySQL user lock which stops several instances migrating at once
+const migrationLock = "go-incubator.schema_migrations"
+
+// dialect is the MySQL flavour of the SQL which sqldb writes
+var dialect = sqldb.Dialect{
+	InsertIgnore: "INSERT IGNORE",
+	Binary:       "utf8mb4_bin",
+	Lock:         lock,
+}
+
 type MySqlDB struct {
-	db *sql.DB
+	sqldb.SqlDB
 }
 
+// NewMySqlDB connects to the database and applies any schema migrations that have not been applied yet
 func NewMySqlDB(connectionString string) (MySqlDB, error) {
-	msdb := MySqlDB{}
-	var err error
-
-	msdb.db, err = sql.Open("mysql", connectionString)
+	msdb, err := OpenMySqlDB(connectionString)
 	if err != nil {
-		return msdb, fmt.Errorf("opening database: %w", err)
+		return msdb, err
 	}
 
-	msdb.db.SetConnMaxLifetime(time.Minute * 3)
-	msdb.db.SetMaxOpenConns(10)
-	msdb.db.SetMaxIdleConns(10)
-
-	err = msdb.db.Ping()
+	err = msdb.MigrateUp(context.Background())
 	if err != nil {
-		return msdb, fmt.Errorf("pinging database: %w", err)
+		return msdb, fmt.Errorf("migrating database: %w", err)
 	}
 
 	return msdb, nil
 }
 
-func (mysql *MySqlDB) AddRecipe(recipe persistence.Recipe) error {
-
-	// Start SQL transaction
-	tx, err := mysql.db.Begin()
+// OpenMySqlDB connects to the database without touching its schema
+func OpenMySqlDB(connectionString string) (MySqlDB, error) {
+	db, err := sql.Open("mysql", connectionString)
 	if err != nil {
-		return fmt.Errorf("beginning transaction: %w", err)
-	}
-	defer tx.Rollback()
-
-	// Insert all ingredients from recipe, ignoring those that are already in db
-	for _, ingredient := range recipe.Ingredients {
-		_, err := tx.Exec("INSERT IGNORE INTO ingredients (name) VALUES (?)", ingredient)
-		if err != nil {
-			return fmt.Errorf("writing ingredient: %w", err)
-		}
+		return MySqlDB{}, fmt.Errorf("opening database: %w", err)
 	}
 
-	// Delete existing ingredients relationships for recipe (if it does exist)
-	_, err = tx.Exec("DELETE FROM recipe_ingredients WHERE recipe_id = (SELECT id FROM recipes WHERE name = ? LIMIT 1)", recipe.Name)
-	if err != nil {
-		return fmt.Errorf("adding recipe: %w", err)
-	}
+	db.SetConnMaxLifetime(time.Minute * 3)
+	db.SetMaxOpenConns(10)
+	db.SetMaxIdleConns(10)
 
-	// Insert recipe, ignoring it if it is already in db
-	_, err = tx.Exec("INSERT IGNORE INTO recipes (name) VALUES (?)", recipe.Name)
+	msdb, err := newMySqlDB(db)
 	if err != nil {
-		return fmt.Errorf("adding recipe: %w", err)
+		return msdb, err
 	}
 
-	// Add ingredient relationships
-	for _, ingredient := range recipe.Ingredients {
-		_, err := tx.Exec("INSERT INTO recipe_ingredients (recipe_id, ingredient_id) SELECT (SELECT id FROM recipes WHERE name = ? LIMIT 1), id FROM ingredients WHERE name = ?", recipe.Name, ingredient)
-		if err != nil {
-			return fmt.Errorf("adding ingredient: %w", err)
-		}
-	}
-
-	// Commit the transaction.
-	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("committing transaction: %w", err)
+	err = db.Ping()
+	if err != nil {
+		return msdb, fmt.Errorf("pinging database: %w", err)
 	}
 
-	return nil
+	return msdb, nil
 }
 
-func (mysql *MySqlDB) GetRecipe(name string) (persistence.Recipe, error) {
-	recipe := persistence.Recipe{Name: name}
-	var iname string
-
-	rows, err := mysql.db.Query(`
-		SELECT I.name FROM recipes R
-		INNER JOIN recipe_ingredients RI ON RI.recipe_id = r.id
-		INNER JOIN ingredients I ON I.id = RI.ingredient_id
-		WHERE R.name = ?
-		ORDER BY I.name`,
-		name,
-	)
+// newMySqlDB returns a MySqlDB which stores recipes in db
+func newMySqlDB(db *sql.DB) (MySqlDB, error) {
+	dir, err := fs.Sub(migrations, "migrations")
 	if err != nil {
-		return recipe, fmt.Errorf("executing query :%w", err)
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		err = rows.Scan(&iname)
-		if err != nil {
-			return recipe, fmt.Errorf("reading ingredient name: %w", err)
-		}
-		recipe.Ingredients = append(recipe.Ingredients, iname)
+		return MySqlDB{}, fmt.Errorf("reading migrations: %w", err)
 	}
 
-	if len(recipe.Ingredients) == 0 {
-		return recipe, persistence.ErrNoResults
-	}
-
-	return recipe, nil
+	return MySqlDB{SqlDB: sqldb.NewSqlDB(db, dialect, dir)}, nil
 }
 
-func (mysql *MySqlDB) FindRecipes(ingredients []string) ([]persistence.Recipe, error) {
-	recipes := []persistence.Recipe{}
-
-	// convert this ingredients to a slice of type any, which is what
-	// the func (*sql.DB).Query(query string, args ...any) requires
-	var args []any
-	for _, ingredient := range ingredients {
-		args = append(args, ingredient)
-	}
-	args = append(args, len(ingredients))
-
-	// Construct a statement which includes all the ingredients we are looking for
-	stmt := `
-	SELECT R.name FROM recipe_ingredients RI
-	INNER JOIN recipes R ON R.id = RI.recipe_id
-	INNER JOIN ingredients I ON I.id = RI.ingredient_id
-	WHERE I.name IN (?` + strings.Repeat(",?", len(ingredients)-1) + `)
-	GROUP BY RI.recipe_id
-	HAVING COUNT(*) = ?`
-	rows, err := mysql.db.Query(stmt, args...)
+// lock takes the MySQL user lock for migrations, which belongs to conn, waiting up to a minute for it
+func lock(ctx context.Context, conn *sql.Conn) (func(), error) {
+	var locked sql.NullInt64
+	err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, 60)", migrationLock).Scan(&locked)
 	if err != nil {
-		return nil, fmt.Errorf("finding recipes: %w", err)
+		return nil, err
 	}
-
-	var rname string
-	for rows.Next() {
-		err = rows.Scan(&rname)
-		if err != nil {
-			return nil, fmt.Errorf("reading recipe: %w", err)
-		}
-		recipe, err := mysql.GetRecipe(rname)
-		if err != nil {
-			return nil, fmt.Errorf("reading recipe: %w", err)
-		}
-		recipes = append(recipes, recipe)
+	if locked.Int64 != 1 {
+		return nil, fmt.Errorf("timed out")
 	}
 
-	return recipes, nil
+	return func() { conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK(?)", migrationLock) }, nil
 }
@@ -0,0 +1,26 @@
+package mysqldb
+
+import (
+	"go-incubator/internal/persistence/migrate"
+	"io/fs"
+	"testing"
+)
+
+func TestMigrations(t *testing.T) {
+	dir, err := fs.Sub(migrations, "migrations")
+	if err != nil {
+		t.Fatalf("fs.Sub() error = %v", err)
+	}
+
+	got, err := migrate.Load(dir)
+	if err != nil {
+		t.Fatalf("migrate.Load() error = %v", err)
+	}
+
+	// Versions must start at 1 and have no gaps, so that the schema version tells exactly what is applied
+	for i, m := range got {
+		if m.Version != i+1 {
+			t.Errorf("migration %d (%s) has version %d, want %d", i, m.Name, m.Version, i+1)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package mysqldb
+
+import (
+	"context"
+	"go-incubator/internal/persistence"
+	"go-incubator/internal/persistence/persistencetest"
+	"os"
+	"testing"
+)
+
+// TestMySqlDB_Server runs the conformance suite against a real MySQL server. It is skipped unless
+// INCUBATOR_DBMS is mysql and INCUBATOR_CONSTRING holds the connection string of a database which
+// the test may wipe, as it reverts and reapplies all migrations before every check.
+func TestMySqlDB_Server(t *testing.T) {
+	connectionString := os.Getenv("INCUBATOR_CONSTRING")
+	if os.Getenv("INCUBATOR_DBMS") != "mysql" || connectionString == "" {
+		t.Skip("set INCUBATOR_DBMS=mysql and INCUBATOR_CONSTRING to test against a MySQL server")
+	}
+
+	persistencetest.Run(t, func(t *testing.T) persistence.Persistence {
+		ctx := context.Background()
+		db, err := NewMySqlDB(connectionString)
+		if err != nil {
+			t.Fatalf("NewMySqlDB() error = %v", err)
+		}
+		t.Cleanup(func() { db.Close() })
+
+		// Start every check from an empty schema
+		version, err := db.SchemaVersion(ctx)
+		if err != nil {
+			t.Fatalf("MySqlDB.SchemaVersion() error = %v", err)
+		}
+		if err := db.MigrateDown(ctx, version); err != nil {
+			t.Fatalf("MySqlDB.MigrateDown() error = %v", err)
+		}
+		if err := db.MigrateUp(ctx); err != nil {
+			t.Fatalf("MySqlDB.MigrateUp() error = %v", err)
+		}
+
+		return &db
+	})
+}
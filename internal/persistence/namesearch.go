@@ -0,0 +1,41 @@
+package persistence
+
+import "strings"
+
+// NameMatch selects how a NameQuery matches the names of recipes
+type NameMatch int
+
+const (
+	// NamePrefix finds the recipes whose names start with the text
+	NamePrefix NameMatch = iota
+	// NameSubstring finds the recipes whose names contain the text anywhere
+	NameSubstring
+)
+
+// NameQuery is a search for recipes by their names. Case and whitespace do not count, so that
+// "salad" finds both "Greek Salad" and "SALAD NIÇOISE" as a substring.
+type NameQuery struct {
+	Text  string
+	Match NameMatch
+}
+
+// Matches returns true if the name of a recipe is a result of the query
+func (q *NameQuery) Matches(name string) bool {
+	return q.MatchesFolded(FoldName(name))
+}
+
+// MatchesFolded returns true if a recipe name which FoldName has already been applied to is a result of the query
+func (q *NameQuery) MatchesFolded(folded string) bool {
+	text := FoldName(q.Text)
+	if q.Match == NameSubstring {
+		return strings.Contains(folded, text)
+	}
+
+	return strings.HasPrefix(folded, text)
+}
+
+// FoldName returns the form of a recipe name which name searches compare: case folded and
+// without surrounding or repeated whitespace
+func FoldName(name string) string {
+	return strings.Join(strings.Fields(strings.Map(foldRune, name)), " ")
+}
@@ -0,0 +1,27 @@
+package persistence
+
+import "testing"
+
+func TestNameQuery_Matches(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  NameQuery
+		recipe string
+		want   bool
+	}{
+		{name: "1", query: NameQuery{Text: "greek", Match: NamePrefix}, recipe: "Greek Salad", want: true},
+		{name: "2", query: NameQuery{Text: "salad", Match: NamePrefix}, recipe: "Greek Salad", want: false},
+		{name: "3", query: NameQuery{Text: "SALAD", Match: NameSubstring}, recipe: "Greek Salad", want: true},
+		{name: "4", query: NameQuery{Text: "k  sal", Match: NameSubstring}, recipe: "Greek   Salad", want: true},
+		{name: "5", query: NameQuery{Text: "niçoise", Match: NameSubstring}, recipe: "Salade NIÇOISE", want: true},
+		{name: "6", query: NameQuery{Text: "", Match: NamePrefix}, recipe: "BLT", want: true},
+		{name: "7", query: NameQuery{Text: "ham", Match: NameSubstring}, recipe: "BLT", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.query.Matches(tt.recipe); got != tt.want {
+				t.Errorf("NameQuery.Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package persistence
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// synonyms maps the normalised name of each ingredient with synonyms to the normalised names of
+// all ingredients in its group, in the order they were listed
+var synonyms = struct {
+	mu     sync.RWMutex
+	groups map[string][]string
+}{groups: map[string][]string{}}
+
+// SetSynonyms replaces the synonym table. Each group lists names of the same ingredient, such as
+// {"Coriander", "Cilantro"}, and searching for any of them finds recipes using any other. Names are
+// normalised first, and a name which appears in several groups joins them into one. Backends store
+// ingredients without resolving synonyms, so the table can change without touching stored recipes.
+func SetSynonyms(groups [][]string) {
+	// Union the groups, so that every name maps to the same set of names
+	parent := map[string]string{}
+	var find func(string) string
+	find = func(name string) string {
+		if parent[name] == name {
+			return name
+		}
+		parent[name] = find(parent[name])
+		return parent[name]
+	}
+
+	var order []string
+	for _, group := range groups {
+		first := ""
+		for _, name := range group {
+			name = NormaliseIngredient(name)
+			if name == "" {
+				continue
+			}
+			if _, ok := parent[name]; !ok {
+				parent[name] = name
+				order = append(order, name)
+			}
+			if first == "" {
+				first = find(name)
+			} else if root := find(name); root != first {
+				parent[root] = first
+			}
+		}
+	}
+
+	members := map[string][]string{}
+	for _, name := range order {
+		root := find(name)
+		members[root] = append(members[root], name)
+	}
+
+	table := map[string][]string{}
+	for _, names := range members {
+		if len(names) < 2 {
+			continue
+		}
+		for _, name := range names {
+			table[name] = names
+		}
+	}
+
+	synonyms.mu.Lock()
+	defer synonyms.mu.Unlock()
+	synonyms.groups = table
+}
+
+// NormaliseIngredient returns the name which backends store and compare for an ingredient: case folded,
+// without surrounding or repeated whitespace, and with the last word in the singular, so that
+// " Cherry  Tomatoes" and "cherry tomato" are the same ingredient. Recipes keep the spelling they were added with.
+func NormaliseIngredient(name string) string {
+	words := strings.Fields(name)
+	for i, word := range words {
+		words[i] = strings.Map(foldRune, word)
+	}
+	if len(words) > 0 {
+		words[len(words)-1] = singular(words[len(words)-1])
+	}
+
+	return strings.Join(words, " ")
+}
+
+// IngredientVariants returns the normalised names which a search for the ingredient matches, which
+// are those of its synonym group in the order they were listed, or else only its own
+func IngredientVariants(name string) []string {
+	name = NormaliseIngredient(name)
+
+	synonyms.mu.RLock()
+	defer synonyms.mu.RUnlock()
+	if variants, ok := synonyms.groups[name]; ok {
+		return append([]string{}, variants...)
+	}
+
+	return []string{name}
+}
+
+// SameIngredient returns true if a and b name the same ingredient, allowing for different
+// spellings and synonyms
+func SameIngredient(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	a, b = NormaliseIngredient(a), NormaliseIngredient(b)
+	if a == b {
+		return true
+	}
+
+	synonyms.mu.RLock()
+	defer synonyms.mu.RUnlock()
+
+	return contains(synonyms.groups[a], b)
+}
+
+// IngredientKey returns the same value for the names of all ingredients which are the same, allowing
+// for different spellings and synonyms, for use as a map key
+func IngredientKey(name string) string {
+	return IngredientVariants(name)[0]
+}
+
+// foldRune maps a rune to the lower case of its upper case, which folds all runes which only
+// differ in case (such as "K", "k" and the Kelvin sign) onto the same one
+func foldRune(r rune) rune {
+	return unicode.ToLower(unicode.ToUpper(r))
+}
+
+// singular turns a case folded English plural into its singular with a few simple rules,
+// leaving short words and words which do not look like plurals as they are
+func singular(word string) string {
+	if len([]rune(word)) <= 3 {
+		return word
+	}
+
+	switch {
+	case strings.HasSuffix(word, "ies") && len([]rune(word)) > 4:
+		// berries -> berry, but pies -> pie below
+		return strings.TrimSuffix(word, "ies") + "y"
+	case strings.HasSuffix(word, "ie"):
+		// cookie -> cooky, which is where cookies ends up as well
+		return strings.TrimSuffix(word, "ie") + "y"
+	case strings.HasSuffix(word, "sses"), strings.HasSuffix(word, "shes"), strings.HasSuffix(word, "ches"),
+		strings.HasSuffix(word, "xes"), strings.HasSuffix(word, "oes"):
+		// glasses -> glass, radishes -> radish, peaches -> peach, boxes -> box, tomatoes -> tomato
+		return strings.TrimSuffix(word, "es")
+	case strings.HasSuffix(word, "ss"), strings.HasSuffix(word, "us"), strings.HasSuffix(word, "is"):
+		// not plurals: swiss, asparagus, anis
+		return word
+	case strings.HasSuffix(word, "s"):
+		// olives -> olive, lentils -> lentil
+		return strings.TrimSuffix(word, "s")
+	}
+
+	return word
+}
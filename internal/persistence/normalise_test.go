@@ -0,0 +1,84 @@
+package persistence
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormaliseIngredient(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "1", in: " Cherry  Tomatoes ", want: "cherry tomato"},
+		{name: "2", in: "BLUEBERRIES", want: "blueberry"},
+		{name: "3", in: "Pies", want: "pie"},
+		{name: "4", in: "Cookies", want: "cooky"},
+		{name: "5", in: "Cookie", want: "cooky"},
+		{name: "6", in: "Peaches", want: "peach"},
+		{name: "7", in: "Asparagus", want: "asparagus"},
+		{name: "8", in: "Swiss Cheese", want: "swiss cheese"},
+		{name: "9", in: "Peas", want: "pea"},
+		{name: "10", in: "Kale", want: "kale"},
+		{name: "11", in: "Crème Fraîche", want: "crème fraîche"},
+		{name: "12", in: "   ", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormaliseIngredient(tt.in); got != tt.want {
+				t.Errorf("NormaliseIngredient() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIngredientVariants(t *testing.T) {
+	SetSynonyms([][]string{{"Coriander", "Cilantro"}, {"Aubergine", "Eggplant"}, {"Eggplants", "Brinjal"}, {"Salt"}})
+	t.Cleanup(func() { SetSynonyms(nil) })
+
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{name: "1", in: "CILANTRO", want: []string{"coriander", "cilantro"}},
+		{name: "2", in: "brinjals", want: []string{"aubergine", "eggplant", "brinjal"}},
+		{name: "3", in: "Salt", want: []string{"salt"}},
+		{name: "4", in: "Tomatoes", want: []string{"tomato"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IngredientVariants(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("IngredientVariants() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSameIngredient(t *testing.T) {
+	SetSynonyms([][]string{{"Coriander", "Cilantro"}})
+	t.Cleanup(func() { SetSynonyms(nil) })
+
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want bool
+	}{
+		{name: "1", a: "Tomato", b: "tomatoes", want: true},
+		{name: "2", a: "coriander", b: "Cilantro ", want: true},
+		{name: "3", a: "Coriander", b: "Parsley", want: false},
+		{name: "4", a: "Ground Beef", b: "Beef", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SameIngredient(tt.a, tt.b); got != tt.want {
+				t.Errorf("SameIngredient() = %v, want %v", got, tt.want)
+			}
+			if got, want := IngredientKey(tt.a) == IngredientKey(tt.b), tt.want; got != want {
+				t.Errorf("IngredientKey() equal = %v, want %v", got, want)
+			}
+		})
+	}
+}
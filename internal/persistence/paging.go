@@ -0,0 +1,43 @@
+package persistence
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// DefaultPageSize is the number of recipes per page when a client does not ask for a page size,
+// and MaxPageSize is the largest page size a client may ask for
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 1000
+)
+
+// PageSize returns the page size to use for the requested size, which is the default for 0 and capped at MaxPageSize
+func PageSize(requested int) (int, error) {
+	switch {
+	case requested < 0:
+		return 0, fmt.Errorf("invalid page size (%d)", requested)
+	case requested == 0:
+		return DefaultPageSize, nil
+	case requested > MaxPageSize:
+		return MaxPageSize, nil
+	}
+
+	return requested, nil
+}
+
+// EncodePageToken turns a ListRecipes cursor into an opaque page token which is safe to use in URLs
+func EncodePageToken(cursor string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursor))
+}
+
+// DecodePageToken turns a page token created by EncodePageToken back into a ListRecipes cursor.
+// An empty token is the cursor of the first page.
+func DecodePageToken(token string) (string, error) {
+	cursor, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return "", fmt.Errorf("invalid page token (%s)", token)
+	}
+
+	return string(cursor), nil
+}
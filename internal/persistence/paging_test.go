@@ -0,0 +1,57 @@
+package persistence
+
+import (
+	"testing"
+)
+
+func TestPageSize(t *testing.T) {
+	tests := []struct {
+		name      string
+		requested int
+		want      int
+		wantErr   bool
+	}{
+		{name: "1", requested: 0, want: DefaultPageSize, wantErr: false},
+		{name: "2", requested: 10, want: 10, wantErr: false},
+		{name: "3", requested: MaxPageSize + 1, want: MaxPageSize, wantErr: false},
+		{name: "4", requested: -1, want: 0, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PageSize(tt.requested)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PageSize() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("PageSize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodePageToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		token   string
+		want    string
+		wantErr bool
+	}{
+		{name: "1", token: "", want: "", wantErr: false},
+		{name: "2", token: EncodePageToken("Mac & Cheese"), want: "Mac & Cheese", wantErr: false},
+		{name: "3", token: EncodePageToken("Crème brûlée/?"), want: "Crème brûlée/?", wantErr: false},
+		{name: "4", token: "not a token!", want: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodePageToken(tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DecodePageToken() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("DecodePageToken() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
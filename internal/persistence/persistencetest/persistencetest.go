@@ -0,0 +1,1400 @@
+// Package persistencetest provides a test suite which every implementation of
+// persistence.Persistence must pass, so that all backends behave the same way
+package persistencetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-incubator/internal/persistence"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+)
+
+// Factory returns a new, empty database for each call
+type Factory func(t *testing.T) persistence.Persistence
+
+// Fixtures are the recipes that Run adds to a database before most tests
+var Fixtures = []persistence.Recipe{
+	{Name: "Cheese Fondue", Ingredients: []persistence.Ingredient{{Name: "Gruyere", Quantity: 200, Unit: "g", Note: "grated"}, {Name: "Emmental", Quantity: 200, Unit: "g", Note: "grated"}}},
+	{Name: "Mac & Cheese", Ingredients: []persistence.Ingredient{{Name: "Mozzarella", Quantity: 1.5, Unit: "cups"}, {Name: "Macaroni", Quantity: 250, Unit: "g"}}},
+	{
+		Name:         "SpagBol",
+		Description:  "Spaghetti with a slow cooked meat sauce",
+		Ingredients:  []persistence.Ingredient{{Name: "Spaghetti", Quantity: 500, Unit: "g"}, {Name: "Ground Beef", Quantity: 400, Unit: "g"}, {Name: "Tomato", Quantity: 2, Unit: "cans", Note: "chopped"}},
+		Instructions: []string{"Brown the beef.", "Add the tomatoes and simmer for an hour.", "Cook the spaghetti and serve with the sauce."},
+		Servings:     4,
+		PrepMinutes:  15,
+		CookMinutes:  75,
+	},
+	{
+		Name:         "BLT",
+		Ingredients:  []persistence.Ingredient{{Name: "Tomato", Quantity: 1, Note: "sliced"}, {Name: "Bacon", Quantity: 4, Unit: "rashers"}, {Name: "Lettuce"}},
+		Instructions: []string{"Fry the bacon until crisp.", "Layer the bacon, lettuce and tomato between toasted bread."},
+		Servings:     2,
+		CookMinutes:  10,
+		Source:       "https://example.com/blt",
+	},
+	{Name: "Greek Salad", Ingredients: []persistence.Ingredient{{Name: "Feta", Quantity: 200, Unit: "g", Note: "crumbled"}, {Name: "Tomato", Quantity: 3}, {Name: "Cucumber", Quantity: 0.5}}},
+	{Name: "Caprese Salad", Ingredients: []persistence.Ingredient{{Name: "Mozzarella", Quantity: 1, Unit: "ball", Note: "torn"}, {Name: "Tomato", Quantity: 2, Note: "sliced"}}},
+	{Name: "Meatballs", Ingredients: []persistence.Ingredient{{Name: "Ground Beef", Quantity: 500, Unit: "g"}, {Name: "Tomato", Quantity: 1, Unit: "can"}}},
+}
+
+// Run runs the conformance suite against databases created by newDB. It pins down that:
+//   - ingredients are returned in the order they were added, with duplicates dropped after their first use
+//   - the quantity, unit and note of each ingredient are stored, but only its name is searched
+//   - the description, instructions, servings, timings and source of each recipe are stored
+//   - FindRecipes and SearchRecipes return recipes in byte-wise alphabetical order of their names
+//   - SearchRecipes with MatchSubset only returns recipes using at least one of the ingredients
+//   - SearchRecipes never returns a recipe using an excluded ingredient or not matching the expression
+//   - ingredients are searched regardless of case, whitespace, plurals and synonyms, but keep their spelling
+//   - expanded searches also find the kinds of each ingredient in the taxonomy, wherever the query names it
+//   - ListRecipes pages through all recipes in name order without overlaps or gaps
+//   - SearchRecipesByName pages in the same way through the recipes whose names start with or contain
+//     some text regardless of case and whitespace, and takes LIKE wildcards literally
+//   - RecipeNames and IngredientNames return the names of the recipes and of the ingredients they use
+//   - ListIngredients and CompleteIngredient count each ingredient once for all of its spellings
+//   - CatalogueStats counts ingredients in the same way, and finds the recipes which share no ingredient
+//   - SimilarRecipes ranks the recipes sharing ingredients by Jaccard index, then shared ingredients, then name
+//   - searches find the recipes which use an ingredient through their components, all the way up
+//   - adding a recipe which would be a component of itself fails with persistence.ErrRecipeCycle
+//   - adding a recipe with an existing name replaces it completely
+//   - unknown recipes are reported as persistence.ErrNoResults
+//   - recipes without ingredients can be stored and read back
+//   - cancelled contexts stop the work with an error wrapping context.Canceled
+//   - the database can be used from several goroutines at once
+func Run(t *testing.T, newDB Factory) {
+	t.Run("GetRecipe", func(t *testing.T) { testGetRecipe(t, newDB) })
+	t.Run("AddRecipe", func(t *testing.T) { testAddRecipe(t, newDB) })
+	t.Run("DeleteRecipe", func(t *testing.T) { testDeleteRecipe(t, newDB) })
+	t.Run("FindRecipes", func(t *testing.T) { testFindRecipes(t, newDB) })
+	t.Run("SearchRecipes", func(t *testing.T) { testSearchRecipes(t, newDB) })
+	t.Run("ListRecipes", func(t *testing.T) { testListRecipes(t, newDB) })
+	t.Run("SearchRecipesByName", func(t *testing.T) { testSearchRecipesByName(t, newDB) })
+	t.Run("Names", func(t *testing.T) { testNames(t, newDB) })
+	t.Run("Ingredients", func(t *testing.T) { testIngredients(t, newDB) })
+	t.Run("CatalogueStats", func(t *testing.T) { testCatalogueStats(t, newDB) })
+	t.Run("SimilarRecipes", func(t *testing.T) { testSimilarRecipes(t, newDB) })
+	t.Run("Normalisation", func(t *testing.T) { testNormalisation(t, newDB) })
+	t.Run("Taxonomy", func(t *testing.T) { testTaxonomy(t, newDB) })
+	t.Run("Components", func(t *testing.T) { testComponents(t, newDB) })
+	t.Run("NoIngredients", func(t *testing.T) { testNoIngredients(t, newDB) })
+	t.Run("CancelledContext", func(t *testing.T) { testCancelledContext(t, newDB) })
+	t.Run("Concurrency", func(t *testing.T) { testConcurrency(t, newDB) })
+}
+
+// withFixtures returns a new database containing the Fixtures
+func withFixtures(t *testing.T, newDB Factory) persistence.Persistence {
+	db := newDB(t)
+	for _, recipe := range Fixtures {
+		if err := db.AddRecipe(context.Background(), recipe); err != nil {
+			t.Fatalf("AddRecipe(%s) error = %v", recipe.Name, err)
+		}
+	}
+
+	return db
+}
+
+func testGetRecipe(t *testing.T, newDB Factory) {
+	db := withFixtures(t, newDB)
+
+	tests := []struct {
+		name    string
+		rname   string
+		want    persistence.Recipe
+		wantErr error
+	}{
+		{
+			name:  "1",
+			rname: "Cheese Fondue",
+			want:  Fixtures[0],
+		},
+		{
+			name:  "2",
+			rname: "SpagBol",
+			want:  Fixtures[2],
+		},
+		{
+			name:    "3",
+			rname:   "Pizza",
+			wantErr: persistence.ErrNoResults,
+		},
+		{
+			name:    "4",
+			rname:   "",
+			wantErr: persistence.ErrNoResults,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := db.GetRecipe(context.Background(), tt.rname)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetRecipe() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && !Equal(got, tt.want) {
+				t.Errorf("GetRecipe() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Changing a returned recipe must not change what is stored
+	got, _ := db.GetRecipe(context.Background(), "BLT")
+	got.Ingredients[0].Name = "Changed"
+	got, _ = db.GetRecipe(context.Background(), "BLT")
+	if want := Fixtures[3]; !Equal(got, want) {
+		t.Errorf("GetRecipe() after changing the returned recipe = %v, want %v", got, want)
+	}
+}
+
+func testAddRecipe(t *testing.T, newDB Factory) {
+	db := newDB(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		recipe persistence.Recipe
+		want   persistence.Recipe
+	}{
+		{
+			name:   "1",
+			recipe: persistence.Recipe{Name: "Meatballs", Ingredients: persistence.NamedIngredients([]string{"Tomato", "Ground Beef"})},
+			want:   persistence.Recipe{Name: "Meatballs", Ingredients: persistence.NamedIngredients([]string{"Tomato", "Ground Beef"})},
+		},
+		{
+			name:   "2",
+			recipe: persistence.Recipe{Name: "Meatballs", Ingredients: persistence.NamedIngredients([]string{"Onion", "Ground Beef", "Breadcrumbs"})},
+			want:   persistence.Recipe{Name: "Meatballs", Ingredients: persistence.NamedIngredients([]string{"Onion", "Ground Beef", "Breadcrumbs"})},
+		},
+		{
+			name:   "3",
+			recipe: persistence.Recipe{Name: "BLT", Ingredients: persistence.NamedIngredients([]string{"Bacon", "Lettuce", "Bacon", "Tomato"})},
+			want:   persistence.Recipe{Name: "BLT", Ingredients: persistence.NamedIngredients([]string{"Bacon", "Lettuce", "Tomato"})},
+		},
+		{
+			name: "4",
+			recipe: persistence.Recipe{Name: "Pancakes", Ingredients: []persistence.Ingredient{
+				{Name: "Flour", Quantity: 1.25, Unit: "cups", Note: "sifted"},
+				{Name: "Milk", Quantity: 300, Unit: "ml"},
+				{Name: "Flour", Quantity: 2, Unit: "tbsp"},
+				{Name: "Egg", Quantity: 1, Note: "beaten"},
+			}},
+			want: persistence.Recipe{Name: "Pancakes", Ingredients: []persistence.Ingredient{
+				{Name: "Flour", Quantity: 1.25, Unit: "cups", Note: "sifted"},
+				{Name: "Milk", Quantity: 300, Unit: "ml"},
+				{Name: "Egg", Quantity: 1, Note: "beaten"},
+			}},
+		},
+		{
+			name: "5",
+			recipe: persistence.Recipe{
+				Name:         "Pancakes",
+				Description:  "Thin pancakes; serve with lemon & sugar",
+				Ingredients:  persistence.NamedIngredients([]string{"Flour", "Milk", "Egg"}),
+				Instructions: []string{"Whisk everything into a smooth batter.", "Fry thin pancakes in a hot pan."},
+				Servings:     4,
+				PrepMinutes:  5,
+				CookMinutes:  20,
+				Source:       "Grandma's notebook",
+			},
+			want: persistence.Recipe{
+				Name:         "Pancakes",
+				Description:  "Thin pancakes; serve with lemon & sugar",
+				Ingredients:  persistence.NamedIngredients([]string{"Flour", "Milk", "Egg"}),
+				Instructions: []string{"Whisk everything into a smooth batter.", "Fry thin pancakes in a hot pan."},
+				Servings:     4,
+				PrepMinutes:  5,
+				CookMinutes:  20,
+				Source:       "Grandma's notebook",
+			},
+		},
+		{
+			name:   "6",
+			recipe: persistence.Recipe{Name: "Pancakes", Ingredients: persistence.NamedIngredients([]string{"Flour", "Milk", "Egg"}), Instructions: []string{"Fry."}},
+			want:   persistence.Recipe{Name: "Pancakes", Ingredients: persistence.NamedIngredients([]string{"Flour", "Milk", "Egg"}), Instructions: []string{"Fry."}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recipe := Copy(tt.recipe)
+			if err := db.AddRecipe(ctx, recipe); err != nil {
+				t.Errorf("AddRecipe() error = %v", err)
+				return
+			}
+
+			// Changing the added recipe afterwards must not change what is stored
+			recipe.Ingredients[0].Name = "Changed"
+			if len(recipe.Instructions) > 0 {
+				recipe.Instructions[0] = "Changed"
+			}
+
+			got, err := db.GetRecipe(ctx, tt.want.Name)
+			if err != nil || !Equal(got, tt.want) {
+				t.Errorf("GetRecipe() after AddRecipe() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+
+	// Replaced ingredients must no longer find the recipe
+	got, err := db.FindRecipes(ctx, []string{"Tomato", "Ground Beef"})
+	if err != nil || len(got) != 0 {
+		t.Errorf("FindRecipes() after replacing recipe = %v, %v, want []", got, err)
+	}
+}
+
+func testDeleteRecipe(t *testing.T, newDB Factory) {
+	db := withFixtures(t, newDB)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		rname   string
+		wantErr error
+	}{
+		{name: "1", rname: "BLT", wantErr: nil},
+		{name: "2", rname: "BLT", wantErr: persistence.ErrNoResults},
+		{name: "3", rname: "Pizza", wantErr: persistence.ErrNoResults},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := db.DeleteRecipe(ctx, tt.rname); !errors.Is(err, tt.wantErr) {
+				t.Errorf("DeleteRecipe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := db.GetRecipe(ctx, "BLT"); !errors.Is(err, persistence.ErrNoResults) {
+		t.Errorf("GetRecipe() after DeleteRecipe() error = %v, want %v", err, persistence.ErrNoResults)
+	}
+
+	got, err := db.FindRecipes(ctx, []string{"Bacon"})
+	if err != nil || len(got) != 0 {
+		t.Errorf("FindRecipes() after DeleteRecipe() = %v, %v, want []", got, err)
+	}
+
+	// A deleted recipe can be added again
+	if err := db.AddRecipe(ctx, Fixtures[3]); err != nil {
+		t.Errorf("AddRecipe() after DeleteRecipe() error = %v", err)
+	}
+}
+
+func testFindRecipes(t *testing.T, newDB Factory) {
+	db := withFixtures(t, newDB)
+
+	tests := []struct {
+		name        string
+		ingredients []string
+		want        []persistence.Recipe
+	}{
+		{
+			name:        "1",
+			ingredients: []string{"Gruyere", "Emmental"},
+			want:        []persistence.Recipe{Fixtures[0]},
+		},
+		{
+			name:        "2",
+			ingredients: []string{"Emmental", "Gruyere"},
+			want:        []persistence.Recipe{Fixtures[0]},
+		},
+		{
+			name:        "3",
+			ingredients: []string{"Tomato"},
+			want:        []persistence.Recipe{Fixtures[3], Fixtures[5], Fixtures[4], Fixtures[6], Fixtures[2]},
+		},
+		{
+			name:        "4",
+			ingredients: []string{"Tomato", "Ground Beef", "Tomato"},
+			want:        []persistence.Recipe{Fixtures[6], Fixtures[2]},
+		},
+		{
+			name:        "5",
+			ingredients: []string{"Tomato", "Onion"},
+			want:        []persistence.Recipe{},
+		},
+		{
+			name:        "6",
+			ingredients: []string{},
+			want:        []persistence.Recipe{Fixtures[3], Fixtures[5], Fixtures[0], Fixtures[4], Fixtures[1], Fixtures[6], Fixtures[2]},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := db.FindRecipes(context.Background(), tt.ingredients)
+			if err != nil {
+				t.Errorf("FindRecipes() error = %v", err)
+				return
+			}
+			if got == nil {
+				t.Errorf("FindRecipes() = nil, want a non-nil slice")
+			}
+			if !EqualSlices(got, tt.want) {
+				t.Errorf("FindRecipes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func testSearchRecipes(t *testing.T, newDB Factory) {
+	db := withFixtures(t, newDB)
+
+	tests := []struct {
+		name  string
+		query persistence.Query
+		want  []persistence.Recipe
+	}{
+		{
+			name:  "1",
+			query: persistence.Query{Ingredients: []string{"Tomato", "Ground Beef"}, Mode: persistence.MatchAll},
+			want:  []persistence.Recipe{Fixtures[6], Fixtures[2]},
+		},
+		{
+			name:  "2",
+			query: persistence.Query{Ingredients: []string{"Gruyere", "Mozzarella", "Onion"}, Mode: persistence.MatchAny},
+			want:  []persistence.Recipe{Fixtures[5], Fixtures[0], Fixtures[1]},
+		},
+		{
+			name:  "3",
+			query: persistence.Query{Ingredients: []string{"Onion"}, Mode: persistence.MatchAny},
+			want:  []persistence.Recipe{},
+		},
+		{
+			name:  "4",
+			query: persistence.Query{Ingredients: []string{}, Mode: persistence.MatchAny},
+			want:  []persistence.Recipe{},
+		},
+		{
+			name:  "5",
+			query: persistence.Query{Ingredients: []string{"Tomato", "Mozzarella", "Ground Beef"}, Mode: persistence.MatchSubset},
+			want:  []persistence.Recipe{Fixtures[5], Fixtures[6]},
+		},
+		{
+			name:  "6",
+			query: persistence.Query{Ingredients: []string{"Tomato", "Mozzarella", "Ground Beef"}, Mode: persistence.MatchSubset, MaxMissing: 1},
+			want:  []persistence.Recipe{Fixtures[5], Fixtures[1], Fixtures[6], Fixtures[2]},
+		},
+		{
+			name:  "7",
+			query: persistence.Query{Ingredients: []string{"Tomato", "Tomato", "Ground Beef"}, Mode: persistence.MatchSubset},
+			want:  []persistence.Recipe{Fixtures[6]},
+		},
+		{
+			name:  "8",
+			query: persistence.Query{Ingredients: []string{"Onion"}, Mode: persistence.MatchSubset, MaxMissing: 5},
+			want:  []persistence.Recipe{},
+		},
+		{
+			name:  "9",
+			query: persistence.Query{Ingredients: []string{"Tomato"}, Exclude: []string{"Ground Beef", "Bacon"}},
+			want:  []persistence.Recipe{Fixtures[5], Fixtures[4]},
+		},
+		{
+			name:  "10",
+			query: persistence.Query{Ingredients: []string{"Mozzarella", "Gruyere"}, Exclude: []string{"Tomato"}, Mode: persistence.MatchAny},
+			want:  []persistence.Recipe{Fixtures[0], Fixtures[1]},
+		},
+		{
+			name:  "11",
+			query: persistence.Query{Ingredients: []string{"Tomato", "Mozzarella", "Ground Beef"}, Exclude: []string{"Macaroni"}, Mode: persistence.MatchSubset, MaxMissing: 1},
+			want:  []persistence.Recipe{Fixtures[5], Fixtures[6], Fixtures[2]},
+		},
+		{
+			name:  "12",
+			query: persistence.Query{Ingredients: []string{}, Exclude: []string{"Tomato"}},
+			want:  []persistence.Recipe{Fixtures[0], Fixtures[1]},
+		},
+		{
+			name:  "13",
+			query: persistence.Query{Ingredients: []string{"Bacon"}, Exclude: []string{"Onion", "Onion"}},
+			want:  []persistence.Recipe{Fixtures[3]},
+		},
+		{
+			name: "14",
+			query: persistence.Query{Expr: persistence.And{
+				persistence.Term("Tomato"),
+				persistence.Or{persistence.Term("Mozzarella"), persistence.Term("Feta")},
+				persistence.Not{Expr: persistence.Term("Bacon")},
+			}},
+			want: []persistence.Recipe{Fixtures[5], Fixtures[4]},
+		},
+		{
+			name:  "15",
+			query: persistence.Query{Expr: persistence.Not{Expr: persistence.Term("Tomato")}},
+			want:  []persistence.Recipe{Fixtures[0], Fixtures[1]},
+		},
+		{
+			name:  "16",
+			query: persistence.Query{Ingredients: []string{"Tomato"}, Expr: persistence.Or{persistence.Term("Mozzarella"), persistence.Term("Gruyere")}},
+			want:  []persistence.Recipe{Fixtures[5]},
+		},
+		{
+			name: "17",
+			query: persistence.Query{
+				Exclude: []string{"Feta"},
+				Expr:    persistence.And{persistence.Term("Tomato"), persistence.Not{Expr: persistence.Or{persistence.Term("Ground Beef"), persistence.Term("Bacon")}}},
+			},
+			want: []persistence.Recipe{Fixtures[5]},
+		},
+		{
+			name: "18",
+			query: persistence.Query{
+				Ingredients: []string{"Mozzarella", "Tomato", "Ground Beef"},
+				Mode:        persistence.MatchSubset,
+				Expr:        persistence.Not{Expr: persistence.Term("Ground Beef")},
+			},
+			want: []persistence.Recipe{Fixtures[5]},
+		},
+		{
+			name:  "19",
+			query: persistence.Query{Expr: persistence.Or{persistence.Term("Truffle"), persistence.And{}}},
+			want:  []persistence.Recipe{Fixtures[3], Fixtures[5], Fixtures[0], Fixtures[4], Fixtures[1], Fixtures[6], Fixtures[2]},
+		},
+		{
+			name:  "20",
+			query: persistence.Query{Expr: persistence.And{persistence.Term("Tomato"), persistence.Or{}}},
+			want:  []persistence.Recipe{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := db.SearchRecipes(context.Background(), tt.query)
+			if err != nil {
+				t.Errorf("SearchRecipes() error = %v", err)
+				return
+			}
+			if got == nil {
+				t.Errorf("SearchRecipes() = nil, want a non-nil slice")
+			}
+			if !EqualSlices(got, tt.want) {
+				t.Errorf("SearchRecipes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func testListRecipes(t *testing.T, newDB Factory) {
+	db := withFixtures(t, newDB)
+
+	tests := []struct {
+		name   string
+		cursor string
+		limit  int
+		want   []persistence.Recipe
+	}{
+		{
+			name:   "1",
+			cursor: "",
+			limit:  3,
+			want:   []persistence.Recipe{Fixtures[3], Fixtures[5], Fixtures[0]},
+		},
+		{
+			name:   "2",
+			cursor: "Cheese Fondue",
+			limit:  3,
+			want:   []persistence.Recipe{Fixtures[4], Fixtures[1], Fixtures[6]},
+		},
+		{
+			name:   "3",
+			cursor: "Meatballs",
+			limit:  3,
+			want:   []persistence.Recipe{Fixtures[2]},
+		},
+		{
+			name:   "4",
+			cursor: "SpagBol",
+			limit:  3,
+			want:   []persistence.Recipe{},
+		},
+		{
+			name:   "5",
+			cursor: "D",
+			limit:  2,
+			want:   []persistence.Recipe{Fixtures[4], Fixtures[1]},
+		},
+		{
+			name:   "6",
+			cursor: "",
+			limit:  100,
+			want:   []persistence.Recipe{Fixtures[3], Fixtures[5], Fixtures[0], Fixtures[4], Fixtures[1], Fixtures[6], Fixtures[2]},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := db.ListRecipes(context.Background(), tt.cursor, tt.limit)
+			if err != nil {
+				t.Errorf("ListRecipes() error = %v", err)
+				return
+			}
+			if got == nil {
+				t.Errorf("ListRecipes() = nil, want a non-nil slice")
+			}
+			if !EqualSlices(got, tt.want) {
+				t.Errorf("ListRecipes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Paging while recipes are added and deleted must neither repeat nor skip the recipes that stay
+	ctx := context.Background()
+	var seen []string
+	cursor := ""
+	for i := 0; ; i++ {
+		page, err := db.ListRecipes(ctx, cursor, 2)
+		if err != nil {
+			t.Fatalf("ListRecipes() error = %v", err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		for _, recipe := range page {
+			seen = append(seen, recipe.Name)
+		}
+		cursor = page[len(page)-1].Name
+
+		if i == 0 {
+			db.AddRecipe(ctx, persistence.Recipe{Name: "Apple Pie", Ingredients: persistence.NamedIngredients([]string{"Apple"})})
+			db.AddRecipe(ctx, persistence.Recipe{Name: "Pizza", Ingredients: persistence.NamedIngredients([]string{"Dough", "Tomato"})})
+			db.DeleteRecipe(ctx, "Greek Salad")
+		}
+	}
+	want := []string{"BLT", "Caprese Salad", "Cheese Fondue", "Mac & Cheese", "Meatballs", "Pizza", "SpagBol"}
+	if !reflect.DeepEqual(seen, want) {
+		t.Errorf("ListRecipes() pages while changing recipes = %v, want %v", seen, want)
+	}
+}
+
+func testSearchRecipesByName(t *testing.T, newDB Factory) {
+	db := withFixtures(t, newDB)
+	if err := db.AddRecipe(context.Background(), persistence.Recipe{Name: "100% Rye_Bread!", Ingredients: persistence.NamedIngredients([]string{"Rye Flour"})}); err != nil {
+		t.Fatalf("AddRecipe() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		query  persistence.NameQuery
+		cursor string
+		limit  int
+		want   []string
+	}{
+		{name: "1", query: persistence.NameQuery{Text: "mac", Match: persistence.NamePrefix}, limit: 10, want: []string{"Mac & Cheese"}},
+		{name: "2", query: persistence.NameQuery{Text: "SALAD", Match: persistence.NameSubstring}, limit: 10, want: []string{"Caprese Salad", "Greek Salad"}},
+		{name: "3", query: persistence.NameQuery{Text: "salad", Match: persistence.NamePrefix}, limit: 10, want: []string{}},
+		{name: "4", query: persistence.NameQuery{Text: "salad", Match: persistence.NameSubstring}, cursor: "Caprese Salad", limit: 10, want: []string{"Greek Salad"}},
+		{name: "5", query: persistence.NameQuery{Text: "a", Match: persistence.NameSubstring}, cursor: "Caprese Salad", limit: 2, want: []string{"Greek Salad", "Mac & Cheese"}},
+		{name: "6", query: persistence.NameQuery{Text: " cheese  fon", Match: persistence.NamePrefix}, limit: 10, want: []string{"Cheese Fondue"}},
+		{name: "7", query: persistence.NameQuery{Text: "", Match: persistence.NamePrefix}, cursor: "Meatballs", limit: 10, want: []string{"SpagBol"}},
+		{name: "8", query: persistence.NameQuery{Text: "%", Match: persistence.NameSubstring}, limit: 10, want: []string{"100% Rye_Bread!"}},
+		{name: "9", query: persistence.NameQuery{Text: "rye_", Match: persistence.NameSubstring}, limit: 10, want: []string{"100% Rye_Bread!"}},
+		{name: "10", query: persistence.NameQuery{Text: "b_t", Match: persistence.NamePrefix}, limit: 10, want: []string{}},
+		{name: "11", query: persistence.NameQuery{Text: "bread!", Match: persistence.NameSubstring}, limit: 10, want: []string{"100% Rye_Bread!"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := db.SearchRecipesByName(context.Background(), tt.query, tt.cursor, tt.limit)
+			if err != nil {
+				t.Errorf("SearchRecipesByName() error = %v", err)
+				return
+			}
+			if got == nil {
+				t.Errorf("SearchRecipesByName() = nil, want a non-nil slice")
+			}
+			names := []string{}
+			for _, recipe := range got {
+				names = append(names, recipe.Name)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("SearchRecipesByName() = %v, want %v", names, tt.want)
+			}
+		})
+	}
+
+	// The recipes found are complete, like those of ListRecipes
+	got, err := db.SearchRecipesByName(context.Background(), persistence.NameQuery{Text: "bl", Match: persistence.NamePrefix}, "", 10)
+	if err != nil || !EqualSlices(got, []persistence.Recipe{Fixtures[3]}) {
+		t.Errorf("SearchRecipesByName() = %v, %v, want [%v]", got, err, Fixtures[3])
+	}
+
+	// Deleted recipes are no longer found
+	if err := db.DeleteRecipe(context.Background(), "Greek Salad"); err != nil {
+		t.Fatalf("DeleteRecipe() error = %v", err)
+	}
+	got, err = db.SearchRecipesByName(context.Background(), persistence.NameQuery{Text: "salad", Match: persistence.NameSubstring}, "", 10)
+	if err != nil || !EqualSlices(got, []persistence.Recipe{Fixtures[5]}) {
+		t.Errorf("SearchRecipesByName() after DeleteRecipe() = %v, %v, want [%v]", got, err, Fixtures[5])
+	}
+}
+
+func testNames(t *testing.T, newDB Factory) {
+	db := withFixtures(t, newDB)
+	ctx := context.Background()
+
+	names, err := db.RecipeNames(ctx)
+	sort.Strings(names)
+	want := []string{"BLT", "Caprese Salad", "Cheese Fondue", "Greek Salad", "Mac & Cheese", "Meatballs", "SpagBol"}
+	if err != nil || !reflect.DeepEqual(names, want) {
+		t.Errorf("RecipeNames() = %v, %v, want %v", names, err, want)
+	}
+
+	// The ingredients of a deleted recipe are gone unless another recipe uses them
+	if err := db.DeleteRecipe(ctx, "Cheese Fondue"); err != nil {
+		t.Fatalf("DeleteRecipe() error = %v", err)
+	}
+	names, err = db.IngredientNames(ctx)
+	sort.Strings(names)
+	want = []string{"Bacon", "Cucumber", "Feta", "Ground Beef", "Lettuce", "Macaroni", "Mozzarella", "Spaghetti", "Tomato"}
+	if err != nil || !reflect.DeepEqual(names, want) {
+		t.Errorf("IngredientNames() = %v, %v, want %v", names, err, want)
+	}
+}
+
+func testIngredients(t *testing.T, newDB Factory) {
+	db := withFixtures(t, newDB)
+	ctx := context.Background()
+
+	got, err := db.ListIngredients(ctx)
+	want := []persistence.IngredientUsage{
+		{Name: "Bacon", Recipes: 1}, {Name: "Cucumber", Recipes: 1}, {Name: "Emmental", Recipes: 1}, {Name: "Feta", Recipes: 1},
+		{Name: "Ground Beef", Recipes: 2}, {Name: "Gruyere", Recipes: 1}, {Name: "Lettuce", Recipes: 1}, {Name: "Macaroni", Recipes: 1},
+		{Name: "Mozzarella", Recipes: 2}, {Name: "Spaghetti", Recipes: 1}, {Name: "Tomato", Recipes: 5},
+	}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("ListIngredients() = %v, %v, want %v", got, err, want)
+	}
+
+	tests := []struct {
+		name   string
+		prefix string
+		limit  int
+		want   []persistence.IngredientUsage
+	}{
+		{name: "1", prefix: "m", limit: 10, want: []persistence.IngredientUsage{{Name: "Mozzarella", Recipes: 2}, {Name: "Macaroni", Recipes: 1}}},
+		{name: "2", prefix: "", limit: 3, want: []persistence.IngredientUsage{{Name: "Tomato", Recipes: 5}, {Name: "Ground Beef", Recipes: 2}, {Name: "Mozzarella", Recipes: 2}}},
+		{name: "3", prefix: "G", limit: 10, want: []persistence.IngredientUsage{{Name: "Ground Beef", Recipes: 2}, {Name: "Gruyere", Recipes: 1}}},
+		{name: "4", prefix: " ground  B", limit: 10, want: []persistence.IngredientUsage{{Name: "Ground Beef", Recipes: 2}}},
+		{name: "5", prefix: "Tomatoes", limit: 10, want: []persistence.IngredientUsage{{Name: "Tomato", Recipes: 5}}},
+		{name: "6", prefix: "lettuces", limit: 10, want: []persistence.IngredientUsage{{Name: "Lettuce", Recipes: 1}}},
+		{name: "7", prefix: "x", limit: 10, want: []persistence.IngredientUsage{}},
+		{name: "8", prefix: "m", limit: 1, want: []persistence.IngredientUsage{{Name: "Mozzarella", Recipes: 2}}},
+		{name: "9", prefix: "m", limit: 0, want: []persistence.IngredientUsage{}},
+		{name: "10", prefix: "%", limit: 10, want: []persistence.IngredientUsage{}},
+		{name: "11", prefix: "m", limit: -1, want: []persistence.IngredientUsage{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := db.CompleteIngredient(ctx, tt.prefix, tt.limit)
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CompleteIngredient() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+
+	// Another spelling of an ingredient adds to its count under the first spelling, and a deleted
+	// recipe no longer counts
+	bruschetta := persistence.Recipe{Name: "Bruschetta", Ingredients: persistence.NamedIngredients([]string{"tomatoes", "Basil"})}
+	if err := db.AddRecipe(ctx, bruschetta); err != nil {
+		t.Fatalf("AddRecipe() error = %v", err)
+	}
+	if err := db.DeleteRecipe(ctx, "Mac & Cheese"); err != nil {
+		t.Fatalf("DeleteRecipe() error = %v", err)
+	}
+	got, err = db.CompleteIngredient(ctx, "", 100)
+	want = []persistence.IngredientUsage{
+		{Name: "Tomato", Recipes: 6}, {Name: "Ground Beef", Recipes: 2}, {Name: "Bacon", Recipes: 1}, {Name: "Basil", Recipes: 1},
+		{Name: "Cucumber", Recipes: 1}, {Name: "Emmental", Recipes: 1}, {Name: "Feta", Recipes: 1}, {Name: "Gruyere", Recipes: 1},
+		{Name: "Lettuce", Recipes: 1}, {Name: "Mozzarella", Recipes: 1}, {Name: "Spaghetti", Recipes: 1},
+	}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("CompleteIngredient() after changes = %v, %v, want %v", got, err, want)
+	}
+}
+
+func testCatalogueStats(t *testing.T, newDB Factory) {
+	ctx := context.Background()
+
+	got, err := newDB(t).CatalogueStats(ctx, 3)
+	want := persistence.CatalogueStats{MostUsed: []persistence.IngredientUsage{}, LeastUsed: []persistence.IngredientUsage{}, IsolatedRecipes: []string{}}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("CatalogueStats() of an empty database = %+v, %v, want %+v", got, err, want)
+	}
+
+	db := withFixtures(t, newDB)
+	got, err = db.CatalogueStats(ctx, 3)
+	want = persistence.CatalogueStats{
+		Recipes:            7,
+		Ingredients:        11,
+		MostUsed:           []persistence.IngredientUsage{{Name: "Tomato", Recipes: 5}, {Name: "Ground Beef", Recipes: 2}, {Name: "Mozzarella", Recipes: 2}},
+		LeastUsed:          []persistence.IngredientUsage{{Name: "Bacon", Recipes: 1}, {Name: "Cucumber", Recipes: 1}, {Name: "Emmental", Recipes: 1}},
+		AverageIngredients: 17.0 / 7,
+		IsolatedRecipes:    []string{"Cheese Fondue"},
+	}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("CatalogueStats() = %+v, %v, want %+v", got, err, want)
+	}
+
+	// Another spelling of an ingredient is shared with the recipes which use the first, while a recipe without
+	// ingredients shares nothing
+	recipes := []persistence.Recipe{
+		{Name: "Bruschetta", Ingredients: persistence.NamedIngredients([]string{"tomatoes", "Basil"})},
+		{Name: "Pancakes", Ingredients: persistence.NamedIngredients([]string{"Flour", "Eggs", "Milk"})},
+		{Name: "Toast"},
+	}
+	for _, recipe := range recipes {
+		if err := db.AddRecipe(ctx, recipe); err != nil {
+			t.Fatalf("AddRecipe(%s) error = %v", recipe.Name, err)
+		}
+	}
+	got, err = db.CatalogueStats(ctx, 2)
+	want = persistence.CatalogueStats{
+		Recipes:            10,
+		Ingredients:        15,
+		MostUsed:           []persistence.IngredientUsage{{Name: "Tomato", Recipes: 6}, {Name: "Ground Beef", Recipes: 2}},
+		LeastUsed:          []persistence.IngredientUsage{{Name: "Bacon", Recipes: 1}, {Name: "Basil", Recipes: 1}},
+		AverageIngredients: 2.2,
+		IsolatedRecipes:    []string{"Cheese Fondue", "Pancakes", "Toast"},
+	}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("CatalogueStats() after changes = %+v, %v, want %+v", got, err, want)
+	}
+}
+
+func testSimilarRecipes(t *testing.T, newDB Factory) {
+	db := withFixtures(t, newDB)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		recipe  string
+		limit   int
+		want    []persistence.SimilarRecipe
+		wantErr error
+	}{
+		{
+			name:   "1",
+			recipe: "SpagBol",
+			limit:  10,
+			want: []persistence.SimilarRecipe{
+				{Name: "Meatballs", Similarity: 2.0 / 3, Shared: []string{"Ground Beef", "Tomato"}},
+				{Name: "Caprese Salad", Similarity: 0.25, Shared: []string{"Tomato"}},
+				{Name: "BLT", Similarity: 0.2, Shared: []string{"Tomato"}},
+				{Name: "Greek Salad", Similarity: 0.2, Shared: []string{"Tomato"}},
+			},
+		},
+		{
+			name:   "2",
+			recipe: "SpagBol",
+			limit:  2,
+			want: []persistence.SimilarRecipe{
+				{Name: "Meatballs", Similarity: 2.0 / 3, Shared: []string{"Ground Beef", "Tomato"}},
+				{Name: "Caprese Salad", Similarity: 0.25, Shared: []string{"Tomato"}},
+			},
+		},
+		{
+			name:   "3",
+			recipe: "Mac & Cheese",
+			limit:  10,
+			want:   []persistence.SimilarRecipe{{Name: "Caprese Salad", Similarity: 1.0 / 3, Shared: []string{"Mozzarella"}}},
+		},
+		{
+			name:   "4",
+			recipe: "Cheese Fondue",
+			limit:  10,
+			want:   []persistence.SimilarRecipe{},
+		},
+		{
+			name:   "5",
+			recipe: "SpagBol",
+			limit:  0,
+			want:   []persistence.SimilarRecipe{},
+		},
+		{
+			name:    "6",
+			recipe:  "Pizza",
+			limit:   10,
+			wantErr: persistence.ErrNoResults,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := db.SimilarRecipes(ctx, tt.recipe, tt.limit)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("SimilarRecipes() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SimilarRecipes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Another spelling of an ingredient is shared, and listed as the recipe asked about spells it, while
+	// a recipe without ingredients is like no other
+	recipes := []persistence.Recipe{
+		{Name: "Bruschetta", Ingredients: persistence.NamedIngredients([]string{"tomatoes", "Basil"})},
+		{Name: "Toast"},
+	}
+	for _, recipe := range recipes {
+		if err := db.AddRecipe(ctx, recipe); err != nil {
+			t.Fatalf("AddRecipe(%s) error = %v", recipe.Name, err)
+		}
+	}
+	got, err := db.SimilarRecipes(ctx, "Bruschetta", 1)
+	want := []persistence.SimilarRecipe{{Name: "Caprese Salad", Similarity: 1.0 / 3, Shared: []string{"tomatoes"}}}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("SimilarRecipes(Bruschetta) = %v, %v, want %v", got, err, want)
+	}
+	got, err = db.SimilarRecipes(ctx, "Toast", 10)
+	if err != nil || !reflect.DeepEqual(got, []persistence.SimilarRecipe{}) {
+		t.Errorf("SimilarRecipes(Toast) = %v, %v, want []", got, err)
+	}
+
+	// A deleted recipe is no longer similar to anything
+	if err := db.DeleteRecipe(ctx, "Caprese Salad"); err != nil {
+		t.Fatalf("DeleteRecipe() error = %v", err)
+	}
+	got, err = db.SimilarRecipes(ctx, "Mac & Cheese", 10)
+	if err != nil || !reflect.DeepEqual(got, []persistence.SimilarRecipe{}) {
+		t.Errorf("SimilarRecipes() after DeleteRecipe = %v, %v, want []", got, err)
+	}
+}
+
+func testNormalisation(t *testing.T, newDB Factory) {
+	persistence.SetSynonyms([][]string{{"Coriander", "Cilantro"}})
+	t.Cleanup(func() { persistence.SetSynonyms(nil) })
+
+	db := newDB(t)
+	recipes := []persistence.Recipe{
+		{Name: "Chilli", Ingredients: persistence.NamedIngredients([]string{"Chilli Peppers", "Tomato"})},
+		{Name: "Curry", Ingredients: persistence.NamedIngredients([]string{"Coriander", "Chicken Thighs"})},
+		{Name: "Salsa", Ingredients: persistence.NamedIngredients([]string{"Tomatoes", "CILANTRO ", "Onion"})},
+	}
+	for _, recipe := range recipes {
+		if err := db.AddRecipe(context.Background(), recipe); err != nil {
+			t.Fatalf("AddRecipe(%s) error = %v", recipe.Name, err)
+		}
+	}
+
+	// A repeat in another spelling is dropped like any other repeat
+	curry := persistence.Recipe{Name: "Curry", Ingredients: persistence.NamedIngredients([]string{"Coriander", "Chicken Thighs", "chicken  thigh"})}
+	if err := db.AddRecipe(context.Background(), curry); err != nil {
+		t.Fatalf("AddRecipe(%s) error = %v", curry.Name, err)
+	}
+
+	// Recipes keep the spelling they were added with
+	for _, want := range recipes {
+		got, err := db.GetRecipe(context.Background(), want.Name)
+		if err != nil || !Equal(got, want) {
+			t.Errorf("GetRecipe(%s) = %v, %v, want %v", want.Name, got, err, want)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		query persistence.Query
+		want  []persistence.Recipe
+	}{
+		{
+			name:  "1",
+			query: persistence.Query{Ingredients: []string{"tomato"}},
+			want:  []persistence.Recipe{recipes[0], recipes[2]},
+		},
+		{
+			name:  "2",
+			query: persistence.Query{Ingredients: []string{" TOMATOES", "cilantro"}},
+			want:  []persistence.Recipe{recipes[2]},
+		},
+		{
+			name:  "3",
+			query: persistence.Query{Ingredients: []string{"coriander"}},
+			want:  []persistence.Recipe{recipes[1], recipes[2]},
+		},
+		{
+			name:  "4",
+			query: persistence.Query{Ingredients: []string{"chilli pepper", "chicken thigh"}, Mode: persistence.MatchAny},
+			want:  []persistence.Recipe{recipes[0], recipes[1]},
+		},
+		{
+			name:  "5",
+			query: persistence.Query{Ingredients: []string{"tomato", "onions", "Coriander"}, Mode: persistence.MatchSubset},
+			want:  []persistence.Recipe{recipes[2]},
+		},
+		{
+			name:  "6",
+			query: persistence.Query{Exclude: []string{"cilantro"}},
+			want:  []persistence.Recipe{recipes[0]},
+		},
+		{
+			name:  "7",
+			query: persistence.Query{Expr: persistence.And{persistence.Term("chicken thigh"), persistence.Term("CILANTRO")}},
+			want:  []persistence.Recipe{recipes[1]},
+		},
+		{
+			name:  "8",
+			query: persistence.Query{Ingredients: []string{"Coriander", "Cilantro"}},
+			want:  []persistence.Recipe{recipes[1], recipes[2]},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := db.SearchRecipes(context.Background(), tt.query)
+			if err != nil {
+				t.Errorf("SearchRecipes() error = %v", err)
+				return
+			}
+			if !EqualSlices(got, tt.want) {
+				t.Errorf("SearchRecipes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func testTaxonomy(t *testing.T, newDB Factory) {
+	err := persistence.SetTaxonomy([]persistence.Kind{
+		{Ingredient: "Gruyere", Parent: "Cheese"},
+		{Ingredient: "Emmental", Parent: "Cheese"},
+		{Ingredient: "Mozzarella", Parent: "Cheese"},
+		{Ingredient: "Feta", Parent: "cheeses"},
+		{Ingredient: "Cheese", Parent: "Dairy"},
+		{Ingredient: "Spaghetti", Parent: "Pasta"},
+		{Ingredient: "Macaroni", Parent: "Pasta"},
+		{Ingredient: "Bacon", Parent: "Meat"},
+		{Ingredient: "Ground Beef", Parent: "Meat"},
+	})
+	if err != nil {
+		t.Fatalf("SetTaxonomy() error = %v", err)
+	}
+	t.Cleanup(func() { persistence.SetTaxonomy(nil) })
+
+	db := withFixtures(t, newDB)
+
+	tests := []struct {
+		name  string
+		query persistence.Query
+		want  []persistence.Recipe
+	}{
+		{
+			name:  "1",
+			query: persistence.Query{Ingredients: []string{"Cheese"}},
+			want:  []persistence.Recipe{},
+		},
+		{
+			name:  "2",
+			query: persistence.Query{Ingredients: []string{"Cheese"}, Expand: true},
+			want:  []persistence.Recipe{Fixtures[5], Fixtures[0], Fixtures[4], Fixtures[1]},
+		},
+		{
+			name:  "3",
+			query: persistence.Query{Ingredients: []string{"dairy", "Tomato"}, Expand: true},
+			want:  []persistence.Recipe{Fixtures[5], Fixtures[4]},
+		},
+		{
+			name:  "4",
+			query: persistence.Query{Ingredients: []string{"Pasta", "Meat"}, Mode: persistence.MatchAny, Expand: true},
+			want:  []persistence.Recipe{Fixtures[3], Fixtures[1], Fixtures[6], Fixtures[2]},
+		},
+		{
+			name:  "5",
+			query: persistence.Query{Ingredients: []string{"Cheese", "Tomato"}, Mode: persistence.MatchSubset, Expand: true},
+			want:  []persistence.Recipe{Fixtures[5], Fixtures[0]},
+		},
+		{
+			name:  "6",
+			query: persistence.Query{Ingredients: []string{"Tomato"}, Exclude: []string{"Cheese"}, Expand: true},
+			want:  []persistence.Recipe{Fixtures[3], Fixtures[6], Fixtures[2]},
+		},
+		{
+			name:  "7",
+			query: persistence.Query{Expr: persistence.And{persistence.Term("Pasta"), persistence.Not{Expr: persistence.Term("Dairy")}}, Expand: true},
+			want:  []persistence.Recipe{Fixtures[2]},
+		},
+		{
+			name:  "8",
+			query: persistence.Query{Ingredients: []string{"Mozzarella"}, Expand: true},
+			want:  []persistence.Recipe{Fixtures[5], Fixtures[1]},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := db.SearchRecipes(context.Background(), tt.query)
+			if err != nil {
+				t.Errorf("SearchRecipes() error = %v", err)
+				return
+			}
+			if !EqualSlices(got, tt.want) {
+				t.Errorf("SearchRecipes() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !tt.query.Matches(&got[i]) {
+					t.Errorf("Query.Matches(%s) = false, want true", got[i].Name)
+				}
+			}
+		})
+	}
+}
+
+func testComponents(t *testing.T, newDB Factory) {
+	db := withFixtures(t, newDB)
+	ctx := context.Background()
+
+	dough := persistence.Recipe{Name: "Pizza Dough", Ingredients: []persistence.Ingredient{{Name: "Flour", Quantity: 500, Unit: "g"}, {Name: "Water", Quantity: 300, Unit: "ml"}, {Name: "Yeast", Quantity: 7, Unit: "g"}}}
+	sauce := persistence.Recipe{Name: "Tomato Sauce", Ingredients: []persistence.Ingredient{{Name: "Tomato", Quantity: 1, Unit: "can"}, {Name: "Garlic", Quantity: 2, Unit: "cloves"}}}
+	pizza := persistence.Recipe{
+		Name:         "Margherita",
+		Ingredients:  []persistence.Ingredient{{Name: "Pizza Dough", Component: true}, {Name: "Tomato Sauce", Component: true, Note: "thinly spread"}, {Name: "Mozzarella", Quantity: 1, Unit: "ball"}, {Name: "Tomato", Quantity: 1, Unit: "can"}},
+		Instructions: []string{"Top the dough with the sauce and cheese.", "Bake."},
+	}
+	party := persistence.Recipe{Name: "Pizza Party", Ingredients: []persistence.Ingredient{{Name: "Margherita", Quantity: 2, Component: true}, {Name: "Lemonade", Component: true}}}
+	for _, recipe := range []persistence.Recipe{dough, sauce, pizza, party} {
+		if err := db.AddRecipe(ctx, recipe); err != nil {
+			t.Fatalf("AddRecipe(%s) error = %v", recipe.Name, err)
+		}
+	}
+
+	got, err := db.GetRecipe(ctx, "Margherita")
+	if err != nil || !Equal(got, pizza) {
+		t.Errorf("GetRecipe() = %v, %v, want %v", got, err, pizza)
+	}
+
+	tests := []struct {
+		name  string
+		query persistence.Query
+		want  []persistence.Recipe
+	}{
+		{
+			name:  "1",
+			query: persistence.Query{Ingredients: []string{"Yeast"}},
+			want:  []persistence.Recipe{pizza, dough, party},
+		},
+		{
+			name:  "2",
+			query: persistence.Query{Ingredients: []string{"Garlic", "Mozzarella"}},
+			want:  []persistence.Recipe{pizza, party},
+		},
+		{
+			name:  "3",
+			query: persistence.Query{Ingredients: []string{"Garlic", "Feta"}, Mode: persistence.MatchAny},
+			want:  []persistence.Recipe{Fixtures[4], pizza, party, sauce},
+		},
+		{
+			name:  "4",
+			query: persistence.Query{Ingredients: []string{"Tomato"}, Exclude: []string{"Yeast", "Bacon"}},
+			want:  []persistence.Recipe{Fixtures[5], Fixtures[4], Fixtures[6], Fixtures[2], sauce},
+		},
+		{
+			name:  "5",
+			query: persistence.Query{Expr: persistence.And{persistence.Term("Flour"), persistence.Not{Expr: persistence.Term("Garlic")}}},
+			want:  []persistence.Recipe{dough},
+		},
+		{
+			name:  "6",
+			query: persistence.Query{Ingredients: []string{"Lemonade"}},
+			want:  []persistence.Recipe{party},
+		},
+		{
+			name:  "7",
+			query: persistence.Query{Ingredients: []string{"Yeast"}, Mode: persistence.MatchSubset, MaxMissing: 2},
+			want:  []persistence.Recipe{dough, party},
+		},
+		{
+			name:  "8",
+			query: persistence.Query{Ingredients: []string{"Yeast", "Lemonade"}, Mode: persistence.MatchSubset, MaxMissing: 1},
+			want:  []persistence.Recipe{party},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := db.SearchRecipes(ctx, tt.query)
+			if err != nil {
+				t.Errorf("SearchRecipes() error = %v", err)
+				return
+			}
+			if !EqualSlices(got, tt.want) {
+				t.Errorf("SearchRecipes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Two batches of the pizza need two batches of the dough and of the sauce, while the unknown
+	// lemonade stays as it is
+	expanded, err := persistence.ExpandRecipe(ctx, db, party)
+	want := persistence.Recipe{Name: "Pizza Party", Ingredients: []persistence.Ingredient{
+		{Name: "Flour", Quantity: 1000, Unit: "g"}, {Name: "Water", Quantity: 600, Unit: "ml"}, {Name: "Yeast", Quantity: 14, Unit: "g"},
+		{Name: "Tomato", Quantity: 4, Unit: "can"}, {Name: "Garlic", Quantity: 4, Unit: "cloves"},
+		{Name: "Mozzarella", Quantity: 2, Unit: "ball"}, {Name: "Lemonade", Component: true},
+	}}
+	if err != nil || !Equal(expanded, want) {
+		t.Errorf("ExpandRecipe() = %v, %v, want %v", expanded, err, want)
+	}
+
+	// Neither directly nor through other recipes can a recipe be a component of itself, and the
+	// stored recipe is left as it was
+	for _, recipe := range []persistence.Recipe{
+		{Name: "Sourdough", Ingredients: []persistence.Ingredient{{Name: "Flour"}, {Name: "Sourdough", Component: true}}},
+		{Name: "Pizza Dough", Ingredients: []persistence.Ingredient{{Name: "Flour"}, {Name: "Pizza Party", Component: true}}},
+	} {
+		if err := db.AddRecipe(ctx, recipe); !errors.Is(err, persistence.ErrRecipeCycle) {
+			t.Errorf("AddRecipe(%s) error = %v, want %v", recipe.Name, err, persistence.ErrRecipeCycle)
+		}
+	}
+	if got, err := db.GetRecipe(ctx, "Pizza Dough"); err != nil || !Equal(got, dough) {
+		t.Errorf("GetRecipe() = %v, %v, want %v", got, err, dough)
+	}
+	if _, err := db.GetRecipe(ctx, "Sourdough"); !errors.Is(err, persistence.ErrNoResults) {
+		t.Errorf("GetRecipe() error = %v, want %v", err, persistence.ErrNoResults)
+	}
+
+	// Changing or deleting a component changes what finds the recipes using it
+	if err := db.AddRecipe(ctx, persistence.Recipe{Name: "Pizza Dough", Ingredients: persistence.NamedIngredients([]string{"Flour", "Water", "Salt"})}); err != nil {
+		t.Fatalf("AddRecipe() error = %v", err)
+	}
+	if err := db.DeleteRecipe(ctx, "Tomato Sauce"); err != nil {
+		t.Fatalf("DeleteRecipe() error = %v", err)
+	}
+	for _, ingredient := range []string{"Yeast", "Garlic"} {
+		if got, err := db.FindRecipes(ctx, []string{ingredient}); err != nil || len(got) != 0 {
+			t.Errorf("FindRecipes(%s) = %v, %v, want none", ingredient, got, err)
+		}
+	}
+	if got, err := db.FindRecipes(ctx, []string{"Salt"}); err != nil || len(got) != 3 {
+		t.Errorf("FindRecipes(Salt) = %v, %v, want Margherita, Pizza Dough and Pizza Party", got, err)
+	}
+}
+
+func testNoIngredients(t *testing.T, newDB Factory) {
+	db := withFixtures(t, newDB)
+	ctx := context.Background()
+	want := persistence.Recipe{Name: "Water"}
+
+	if err := db.AddRecipe(ctx, want); err != nil {
+		t.Fatalf("AddRecipe() error = %v", err)
+	}
+
+	got, err := db.GetRecipe(ctx, "Water")
+	if err != nil || !Equal(got, want) {
+		t.Errorf("GetRecipe() = %v, %v, want %v", got, err, want)
+	}
+
+	all, err := db.FindRecipes(ctx, []string{})
+	if err != nil || len(all) != len(Fixtures)+1 || !Equal(all[len(all)-1], want) {
+		t.Errorf("FindRecipes() = %v, %v, want all recipes ending with %v", all, err, want)
+	}
+
+	listed, err := db.ListRecipes(ctx, "SpagBol", 10)
+	if err != nil || len(listed) != 1 || !Equal(listed[0], want) {
+		t.Errorf("ListRecipes() = %v, %v, want [%v]", listed, err, want)
+	}
+
+	// Removing all ingredients from an existing recipe keeps the recipe
+	if err := db.AddRecipe(ctx, persistence.Recipe{Name: "BLT", Ingredients: persistence.NamedIngredients([]string{})}); err != nil {
+		t.Fatalf("AddRecipe() error = %v", err)
+	}
+	got, err = db.GetRecipe(ctx, "BLT")
+	if err != nil || len(got.Ingredients) != 0 {
+		t.Errorf("GetRecipe() = %v, %v, want BLT without ingredients", got, err)
+	}
+
+	if err := db.DeleteRecipe(ctx, "Water"); err != nil {
+		t.Errorf("DeleteRecipe() error = %v", err)
+	}
+}
+
+func testCancelledContext(t *testing.T, newDB Factory) {
+	db := withFixtures(t, newDB)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{
+			name: "AddRecipe",
+			call: func() error {
+				return db.AddRecipe(ctx, persistence.Recipe{Name: "Pizza", Ingredients: persistence.NamedIngredients([]string{"Dough", "Tomato"})})
+			},
+		},
+		{
+			name: "GetRecipe",
+			call: func() error {
+				_, err := db.GetRecipe(ctx, "BLT")
+				return err
+			},
+		},
+		{
+			name: "DeleteRecipe",
+			call: func() error {
+				return db.DeleteRecipe(ctx, "BLT")
+			},
+		},
+		{
+			name: "FindRecipes",
+			call: func() error {
+				_, err := db.FindRecipes(ctx, []string{"Tomato"})
+				return err
+			},
+		},
+		{
+			name: "SearchRecipes",
+			call: func() error {
+				_, err := db.SearchRecipes(ctx, persistence.Query{Ingredients: []string{"Tomato"}, Mode: persistence.MatchSubset})
+				return err
+			},
+		},
+		{
+			name: "ListRecipes",
+			call: func() error {
+				_, err := db.ListRecipes(ctx, "", 10)
+				return err
+			},
+		},
+		{
+			name: "SearchRecipesByName",
+			call: func() error {
+				_, err := db.SearchRecipesByName(ctx, persistence.NameQuery{Text: "Salad", Match: persistence.NameSubstring}, "", 10)
+				return err
+			},
+		},
+		{
+			name: "RecipeNames",
+			call: func() error {
+				_, err := db.RecipeNames(ctx)
+				return err
+			},
+		},
+		{
+			name: "IngredientNames",
+			call: func() error {
+				_, err := db.IngredientNames(ctx)
+				return err
+			},
+		},
+		{
+			name: "ListIngredients",
+			call: func() error {
+				_, err := db.ListIngredients(ctx)
+				return err
+			},
+		},
+		{
+			name: "CompleteIngredient",
+			call: func() error {
+				_, err := db.CompleteIngredient(ctx, "m", 10)
+				return err
+			},
+		},
+		{
+			name: "CatalogueStats",
+			call: func() error {
+				_, err := db.CatalogueStats(ctx, 3)
+				return err
+			},
+		},
+		{
+			name: "SimilarRecipes",
+			call: func() error {
+				_, err := db.SimilarRecipes(ctx, "SpagBol", 5)
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, context.Canceled) {
+				t.Errorf("%s() error = %v, want %v", tt.name, err, context.Canceled)
+			}
+		})
+	}
+
+	// Nothing may have changed
+	all, err := db.FindRecipes(context.Background(), []string{})
+	if err != nil || len(all) != len(Fixtures) {
+		t.Errorf("FindRecipes() after cancelled calls = %v, %v, want the %d fixtures", all, err, len(Fixtures))
+	}
+}
+
+func testConcurrency(t *testing.T, newDB Factory) {
+	db := withFixtures(t, newDB)
+	ctx := context.Background()
+	routines := 8
+	recipes := 10
+
+	var wg sync.WaitGroup
+	errs := make(chan error, routines*recipes*3)
+	for i := 0; i < routines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < recipes; j++ {
+				name := fmt.Sprintf("Recipe %d-%d", i, j)
+				if err := db.AddRecipe(ctx, persistence.Recipe{Name: name, Ingredients: persistence.NamedIngredients([]string{"Tomato", fmt.Sprintf("Spice %d", j)})}); err != nil {
+					errs <- fmt.Errorf("AddRecipe(%s): %w", name, err)
+				}
+				if _, err := db.GetRecipe(ctx, name); err != nil {
+					errs <- fmt.Errorf("GetRecipe(%s): %w", name, err)
+				}
+				if _, err := db.FindRecipes(ctx, []string{"Tomato"}); err != nil {
+					errs <- fmt.Errorf("FindRecipes(): %w", err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("concurrent call error = %v", err)
+	}
+
+	got, err := db.FindRecipes(ctx, []string{"Tomato"})
+	if want := 5 + routines*recipes; err != nil || len(got) != want {
+		t.Errorf("FindRecipes() after concurrent calls returned %d recipes (%v), want %d", len(got), err, want)
+	}
+}
+
+// Equal returns true if a and b have the same content, with their ingredients and instructions in the same order.
+// A nil and an empty list are considered equal.
+func Equal(a, b persistence.Recipe) bool {
+	if a.Name != b.Name || a.Description != b.Description || a.Source != b.Source ||
+		a.Servings != b.Servings || a.PrepMinutes != b.PrepMinutes || a.CookMinutes != b.CookMinutes {
+		return false
+	}
+
+	if len(a.Ingredients) != len(b.Ingredients) || len(a.Instructions) != len(b.Instructions) {
+		return false
+	}
+	for i := range a.Ingredients {
+		if a.Ingredients[i] != b.Ingredients[i] {
+			return false
+		}
+	}
+	for i := range a.Instructions {
+		if a.Instructions[i] != b.Instructions[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// EqualSlices returns true if a and b hold Equal recipes in the same order
+func EqualSlices(a, b []persistence.Recipe) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if !Equal(a[i], b[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Copy returns a copy of recipe which shares no memory with the original
+func Copy(recipe persistence.Recipe) persistence.Recipe {
+	recipe.Ingredients = append([]persistence.Ingredient{}, recipe.Ingredients...)
+	recipe.Instructions = append([]string{}, recipe.Instructions...)
+
+	return recipe
+}
@@ -0,0 +1,37 @@
+package persistence
+
+import (
+	"errors"
+	"go-incubator/internal/units"
+)
+
+// ErrUnknownServings is returned when scaling a recipe which does not say how many servings it makes
+var ErrUnknownServings = errors.New("datastore: recipe does not say how many servings it makes")
+
+// ScaleRecipe returns a copy of the recipe for the number of servings, with the quantities of its ingredients
+// scaled, converted to the system of units and rounded by units.Scale. An empty system keeps the system of each
+// unit, and 0 servings keeps the servings of the recipe, so that only its units are converted.
+func ScaleRecipe(recipe Recipe, servings int, system units.System) (Recipe, error) {
+	factor := 1.0
+	if servings != 0 && servings != recipe.Servings {
+		if recipe.Servings <= 0 {
+			return Recipe{}, ErrUnknownServings
+		}
+		factor = float64(servings) / float64(recipe.Servings)
+		recipe.Servings = servings
+	}
+
+	ingredients := make([]Ingredient, 0, len(recipe.Ingredients))
+	for _, ingredient := range recipe.Ingredients {
+		if ingredient.Quantity != 0 {
+			ingredient.Quantity, ingredient.Unit = units.Scale(ingredient.Quantity, ingredient.Unit, factor, system)
+		}
+		ingredients = append(ingredients, ingredient)
+	}
+	if recipe.Ingredients != nil {
+		recipe.Ingredients = ingredients
+	}
+	recipe.Instructions = append([]string(nil), recipe.Instructions...)
+
+	return recipe, nil
+}
@@ -0,0 +1,87 @@
+package persistence
+
+import (
+	"go-incubator/internal/units"
+	"reflect"
+	"testing"
+)
+
+func TestScaleRecipe(t *testing.T) {
+	pancakes := Recipe{
+		Name:         "Pancakes",
+		Ingredients:  []Ingredient{{Name: "Flour", Quantity: 1.5, Unit: "cups", Note: "sifted"}, {Name: "Eggs", Quantity: 2}, {Name: "Butter", Quantity: 30, Unit: "g"}, {Name: "Salt"}},
+		Instructions: []string{"Whisk.", "Fry."},
+		Servings:     4,
+	}
+
+	tests := []struct {
+		name     string
+		recipe   Recipe
+		servings int
+		system   units.System
+		want     Recipe
+		wantErr  error
+	}{
+		{
+			name:     "1",
+			recipe:   pancakes,
+			servings: 8,
+			want: Recipe{
+				Name:         "Pancakes",
+				Ingredients:  []Ingredient{{Name: "Flour", Quantity: 3, Unit: "cups", Note: "sifted"}, {Name: "Eggs", Quantity: 4}, {Name: "Butter", Quantity: 60, Unit: "g"}, {Name: "Salt"}},
+				Instructions: []string{"Whisk.", "Fry."},
+				Servings:     8,
+			},
+		},
+		{
+			name:     "2",
+			recipe:   pancakes,
+			servings: 1,
+			want: Recipe{
+				Name:         "Pancakes",
+				Ingredients:  []Ingredient{{Name: "Flour", Quantity: 0.375, Unit: "cups", Note: "sifted"}, {Name: "Eggs", Quantity: 0.5}, {Name: "Butter", Quantity: 7.5, Unit: "g"}, {Name: "Salt"}},
+				Instructions: []string{"Whisk.", "Fry."},
+				Servings:     1,
+			},
+		},
+		{
+			name:   "3",
+			recipe: pancakes,
+			system: units.Metric,
+			want: Recipe{
+				Name:         "Pancakes",
+				Ingredients:  []Ingredient{{Name: "Flour", Quantity: 355, Unit: "ml", Note: "sifted"}, {Name: "Eggs", Quantity: 2}, {Name: "Butter", Quantity: 30, Unit: "g"}, {Name: "Salt"}},
+				Instructions: []string{"Whisk.", "Fry."},
+				Servings:     4,
+			},
+		},
+		{
+			name:     "4",
+			recipe:   Recipe{Name: "Toast", Ingredients: []Ingredient{{Name: "Bread", Quantity: 2, Unit: "slices"}}},
+			servings: 2,
+			wantErr:  ErrUnknownServings,
+		},
+		{
+			name:     "5",
+			recipe:   Recipe{Name: "Water", Servings: 1},
+			servings: 3,
+			want:     Recipe{Name: "Water", Servings: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ScaleRecipe(tt.recipe, tt.servings, tt.system)
+			if err != tt.wantErr {
+				t.Errorf("ScaleRecipe() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ScaleRecipe() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if pancakes.Ingredients[0].Quantity != 1.5 || pancakes.Servings != 4 {
+		t.Errorf("ScaleRecipe() changed the recipe it scaled: %+v", pancakes)
+	}
+}
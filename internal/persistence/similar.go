@@ -0,0 +1,64 @@
+package persistence
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DefaultSimilar is the number of recipes SimilarRecipes returns when a client does not ask for a number,
+// and MaxSimilar is the most a client may ask for
+const (
+	DefaultSimilar = 5
+	MaxSimilar     = 50
+)
+
+// SimilarRecipe is a recipe which shares ingredients with another. Ingredients are the same if their
+// names normalise to the same name.
+type SimilarRecipe struct {
+	Name string
+	// Similarity is the Jaccard index of the ingredients of the two recipes, from 0 to 1
+	Similarity float64
+	// Shared are the ingredients of the other recipe which this one also uses, as the other recipe spells them
+	// and in its order
+	Shared []string
+}
+
+// SimilarLimit returns the number of similar recipes to return for the requested number, which is the
+// default for 0 and capped at MaxSimilar
+func SimilarLimit(requested int) (int, error) {
+	switch {
+	case requested < 0:
+		return 0, fmt.Errorf("invalid limit (%d)", requested)
+	case requested == 0:
+		return DefaultSimilar, nil
+	case requested > MaxSimilar:
+		return MaxSimilar, nil
+	}
+
+	return requested, nil
+}
+
+// Jaccard returns the number of ingredients which two recipes with a and b ingredients share divided by the
+// number of distinct ingredients in either, or 0 if neither has any
+func Jaccard(shared, a, b int) float64 {
+	union := a + b - shared
+	if union == 0 {
+		return 0
+	}
+
+	return float64(shared) / float64(union)
+}
+
+// SortBySimilarity sorts recipes with the most similar first, then those sharing the most ingredients, then by name
+func SortBySimilarity(recipes []SimilarRecipe) {
+	sort.Slice(recipes, func(i, j int) bool {
+		a, b := recipes[i], recipes[j]
+		if a.Similarity != b.Similarity {
+			return a.Similarity > b.Similarity
+		}
+		if len(a.Shared) != len(b.Shared) {
+			return len(a.Shared) > len(b.Shared)
+		}
+		return a.Name < b.Name
+	})
+}
@@ -0,0 +1,74 @@
+package persistence
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSimilarLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		requested int
+		want      int
+		wantErr   bool
+	}{
+		{name: "1", requested: 0, want: DefaultSimilar, wantErr: false},
+		{name: "2", requested: 3, want: 3, wantErr: false},
+		{name: "3", requested: MaxSimilar + 1, want: MaxSimilar, wantErr: false},
+		{name: "4", requested: -1, want: 0, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SimilarLimit(tt.requested)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SimilarLimit() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("SimilarLimit() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJaccard(t *testing.T) {
+	tests := []struct {
+		name   string
+		shared int
+		a      int
+		b      int
+		want   float64
+	}{
+		{name: "1", shared: 2, a: 3, b: 3, want: 0.5},
+		{name: "2", shared: 2, a: 2, b: 2, want: 1},
+		{name: "3", shared: 0, a: 2, b: 3, want: 0},
+		{name: "4", shared: 0, a: 0, b: 0, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Jaccard(tt.shared, tt.a, tt.b); got != tt.want {
+				t.Errorf("Jaccard() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortBySimilarity(t *testing.T) {
+	got := []SimilarRecipe{
+		{Name: "Meatballs", Similarity: 0.5, Shared: []string{"Tomato"}},
+		{Name: "BLT", Similarity: 0.2, Shared: []string{"Tomato"}},
+		{Name: "Lasagne", Similarity: 0.5, Shared: []string{"Tomato", "Ground Beef"}},
+		{Name: "Greek Salad", Similarity: 0.2, Shared: []string{"Tomato"}},
+	}
+	SortBySimilarity(got)
+
+	want := []SimilarRecipe{
+		{Name: "Lasagne", Similarity: 0.5, Shared: []string{"Tomato", "Ground Beef"}},
+		{Name: "Meatballs", Similarity: 0.5, Shared: []string{"Tomato"}},
+		{Name: "BLT", Similarity: 0.2, Shared: []string{"Tomato"}},
+		{Name: "Greek Salad", Similarity: 0.2, Shared: []string{"Tomato"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SortBySimilarity() = %v, want %v", got, want)
+	}
+}
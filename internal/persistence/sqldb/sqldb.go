@@ -0,0 +1,959 @@
+// Package sqldb stores recipes in a SQL database. It holds the queries and transactions which MySQL and
+// SQLite share, while the mysqldb and sqlitedb packages open the database, supply its schema migrations
+// and describe its Dialect.
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"go-incubator/internal/persistence"
+	"go-incubator/internal/persistence/migrate"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// Dialect describes the few pieces of SQL which differ between databases
+type Dialect struct {
+	// InsertIgnore starts a statement which inserts rows, skipping those which would repeat a unique
+	// key, such as "INSERT IGNORE" or "INSERT OR IGNORE"
+	InsertIgnore string
+	// Binary is the collation which compares text byte-wise, such as "utf8mb4_bin" or "BINARY"
+	Binary string
+	// Lock takes a database wide lock on conn, so that servers which start at the same time do not
+	// apply the same migrations twice, and returns the function which releases it. It is nil for
+	// databases which need no lock.
+	Lock func(ctx context.Context, conn *sql.Conn) (func(), error)
+}
+
+type SqlDB struct {
+	db         *sql.DB
+	dialect    Dialect
+	migrations fs.FS
+}
+
+// NewSqlDB returns a SqlDB which stores recipes in db, and whose schema migrations are the files of migrations
+func NewSqlDB(db *sql.DB, dialect Dialect, migrations fs.FS) SqlDB {
+	return SqlDB{db: db, dialect: dialect, migrations: migrations}
+}
+
+// Close closes the underlying database
+func (sqldb *SqlDB) Close() error {
+	return sqldb.db.Close()
+}
+
+// SchemaVersion returns the version of the most recently applied schema migration
+func (sqldb *SqlDB) SchemaVersion(ctx context.Context) (int, error) {
+	m, err := migrate.NewMigrator(sqldb.db, sqldb.migrations)
+	if err != nil {
+		return 0, err
+	}
+
+	return m.Version(ctx)
+}
+
+// MigrateUp applies all schema migrations that have not been applied yet
+func (sqldb *SqlDB) MigrateUp(ctx context.Context) error {
+	return sqldb.withMigrationLock(ctx, func(m *migrate.Migrator) error {
+		if err := m.Up(ctx); err != nil {
+			return err
+		}
+		return sqldb.fillSearchNames(ctx)
+	})
+}
+
+// MigrateDown reverts the specified number of most recently applied schema migrations
+func (sqldb *SqlDB) MigrateDown(ctx context.Context, steps int) error {
+	return sqldb.withMigrationLock(ctx, func(m *migrate.Migrator) error {
+		return m.Down(ctx, steps)
+	})
+}
+
+// withMigrationLock runs f while holding the migration lock of the dialect, if it has one
+func (sqldb *SqlDB) withMigrationLock(ctx context.Context, f func(m *migrate.Migrator) error) error {
+	m, err := migrate.NewMigrator(sqldb.db, sqldb.migrations)
+	if err != nil {
+		return err
+	}
+	if sqldb.dialect.Lock == nil {
+		return f(&m)
+	}
+
+	// Locks may belong to a connection, so hold on to one until we are done
+	conn, err := sqldb.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("getting connection: %w", err)
+	}
+	defer conn.Close()
+
+	release, err := sqldb.dialect.Lock(ctx, conn)
+	if err != nil {
+		return fmt.Errorf("acquiring migration lock: %w", err)
+	}
+	defer release()
+
+	return f(&m)
+}
+
+// fillSearchNames sets the search names of ingredients and recipes stored before they had them
+func (sqldb *SqlDB) fillSearchNames(ctx context.Context) error {
+	if err := sqldb.fillSearchColumn(ctx, "ingredients", persistence.NormaliseIngredient); err != nil {
+		return err
+	}
+
+	return sqldb.fillSearchColumn(ctx, "recipes", persistence.FoldName)
+}
+
+// fillSearchColumn sets the empty search names of the rows of a table with id, name and search_name columns
+func (sqldb *SqlDB) fillSearchColumn(ctx context.Context, table string, searchName func(string) string) error {
+	rows, err := sqldb.db.QueryContext(ctx, "SELECT id, name FROM "+table+" WHERE search_name = ''")
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", table, err)
+	}
+
+	names := map[int64]string{}
+	for rows.Next() {
+		var id int64
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			rows.Close()
+			return fmt.Errorf("reading %s: %w", table, err)
+		}
+		names[id] = name
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", table, err)
+	}
+
+	for id, name := range names {
+		_, err := sqldb.db.ExecContext(ctx, "UPDATE "+table+" SET search_name = ? WHERE id = ?", searchName(name), id)
+		if err != nil {
+			return fmt.Errorf("writing %s: %w", table, err)
+		}
+	}
+
+	return nil
+}
+
+func (sqldb *SqlDB) AddRecipe(ctx context.Context, recipe persistence.Recipe) error {
+
+	// Start SQL transaction, which is rolled back if ctx is cancelled before commit
+	tx, err := sqldb.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Keep only the first use of each ingredient, however it is spelt
+	recipe.Ingredients = persistence.DistinctIngredients(recipe.Ingredients)
+
+	// Refuse a recipe which would be a component of itself, through the components of the recipes already stored
+	err = persistence.CheckComponents(recipe, func(name string) ([]string, error) {
+		return components(ctx, tx, name)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Insert all ingredients from recipe, ignoring those that are already in db
+	for _, ingredient := range recipe.Ingredients {
+		_, err := tx.ExecContext(ctx, sqldb.dialect.InsertIgnore+" INTO ingredients (name, search_name) VALUES (?, ?)", ingredient.Name, persistence.NormaliseIngredient(ingredient.Name))
+		if err != nil {
+			return fmt.Errorf("writing ingredient: %w", err)
+		}
+	}
+
+	// Delete existing ingredients relationships and instructions for recipe (if it does exist)
+	_, err = tx.ExecContext(ctx, "DELETE FROM recipe_ingredients WHERE recipe_id = (SELECT id FROM recipes WHERE name = ? LIMIT 1)", recipe.Name)
+	if err != nil {
+		return fmt.Errorf("adding recipe: %w", err)
+	}
+	_, err = tx.ExecContext(ctx, "DELETE FROM recipe_steps WHERE recipe_id = (SELECT id FROM recipes WHERE name = ? LIMIT 1)", recipe.Name)
+	if err != nil {
+		return fmt.Errorf("adding recipe: %w", err)
+	}
+
+	// Insert recipe, ignoring it if it is already in db
+	_, err = tx.ExecContext(ctx, sqldb.dialect.InsertIgnore+" INTO recipes (name, search_name) VALUES (?, ?)", recipe.Name, persistence.FoldName(recipe.Name))
+	if err != nil {
+		return fmt.Errorf("adding recipe: %w", err)
+	}
+
+	// Replace the content of the recipe, whether it is new or not
+	_, err = tx.ExecContext(ctx, "UPDATE recipes SET description = ?, servings = ?, prep_minutes = ?, cook_minutes = ?, source = ? WHERE name = ?",
+		recipe.Description, recipe.Servings, recipe.PrepMinutes, recipe.CookMinutes, recipe.Source, recipe.Name)
+	if err != nil {
+		return fmt.Errorf("adding recipe: %w", err)
+	}
+
+	// Add the instructions in their order
+	for position, instruction := range recipe.Instructions {
+		_, err := tx.ExecContext(ctx, "INSERT INTO recipe_steps (recipe_id, position, instruction) SELECT id, ?, ? FROM recipes WHERE name = ?", position, instruction, recipe.Name)
+		if err != nil {
+			return fmt.Errorf("adding instruction: %w", err)
+		}
+	}
+
+	// Add ingredient relationships, remembering their order, details, spelling and whether they are recipes. An ingredient
+	// which the collation treats as a repeat is ignored, so that only its first use is kept
+	for position, ingredient := range recipe.Ingredients {
+		_, err := tx.ExecContext(ctx, sqldb.dialect.InsertIgnore+" INTO recipe_ingredients (recipe_id, ingredient_id, position, quantity, unit, note, display_name, component) SELECT (SELECT id FROM recipes WHERE name = ? LIMIT 1), id, ?, ?, ?, ?, ?, ? FROM ingredients WHERE name = ?", recipe.Name, position, ingredient.Quantity, ingredient.Unit, ingredient.Note, ingredient.Name, ingredient.Component, ingredient.Name)
+		if err != nil {
+			return fmt.Errorf("adding ingredient: %w", err)
+		}
+	}
+
+	// Commit the transaction.
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (sqldb *SqlDB) GetRecipe(ctx context.Context, name string) (persistence.Recipe, error) {
+	// Join from recipes, so that a recipe without ingredients still returns a single row
+	rows, err := sqldb.db.QueryContext(ctx, `
+		SELECT `+recipeColumns+` FROM recipes R
+		LEFT JOIN recipe_ingredients RI ON RI.recipe_id = R.id
+		LEFT JOIN ingredients I ON I.id = RI.ingredient_id
+		WHERE R.name = ?
+		ORDER BY RI.position, I.name`,
+		name,
+	)
+	if err != nil {
+		return persistence.Recipe{Name: name}, fmt.Errorf("executing query: %w", err)
+	}
+	defer rows.Close()
+
+	recipes, err := scanRecipes(rows)
+	if err != nil {
+		return persistence.Recipe{Name: name}, err
+	}
+	if len(recipes) == 0 {
+		return persistence.Recipe{Name: name}, persistence.ErrNoResults
+	}
+
+	if err = sqldb.loadInstructions(ctx, recipes); err != nil {
+		return persistence.Recipe{Name: name}, err
+	}
+
+	return recipes[0], nil
+}
+
+func (sqldb *SqlDB) DeleteRecipe(ctx context.Context, name string) error {
+
+	// Start SQL transaction, which is rolled back if ctx is cancelled before commit
+	tx, err := sqldb.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Delete ingredients relationships and instructions for recipe first, so that no rows are left pointing at it
+	_, err = tx.ExecContext(ctx, "DELETE FROM recipe_ingredients WHERE recipe_id = (SELECT id FROM recipes WHERE name = ? LIMIT 1)", name)
+	if err != nil {
+		return fmt.Errorf("deleting ingredients: %w", err)
+	}
+	_, err = tx.ExecContext(ctx, "DELETE FROM recipe_steps WHERE recipe_id = (SELECT id FROM recipes WHERE name = ? LIMIT 1)", name)
+	if err != nil {
+		return fmt.Errorf("deleting instructions: %w", err)
+	}
+
+	res, err := tx.ExecContext(ctx, "DELETE FROM recipes WHERE name = ?", name)
+	if err != nil {
+		return fmt.Errorf("deleting recipe: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("deleting recipe: %w", err)
+	}
+	if affected == 0 {
+		return persistence.ErrNoResults
+	}
+
+	// Commit the transaction.
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (sqldb *SqlDB) FindRecipes(ctx context.Context, ingredients []string) ([]persistence.Recipe, error) {
+	return sqldb.SearchRecipes(ctx, persistence.Query{Ingredients: ingredients})
+}
+
+func (sqldb *SqlDB) SearchRecipes(ctx context.Context, query persistence.Query) ([]persistence.Recipe, error) {
+	// Construct a statement which loads the names and ingredients (in their recipe order) of all
+	// recipes that match the query, so that a single round trip returns everything
+	filter, args := matchFilter(query)
+	expression, exprArgs, err := exprFilter(query.Expr, query.Variants, filter == "")
+	if err != nil {
+		return nil, fmt.Errorf("finding recipes: %w", err)
+	}
+	exclusion, excluded := excludeFilter(query.Exclude, query.Variants, filter == "" && expression == "")
+	stmt := `
+	SELECT ` + recipeColumns + ` FROM recipes R
+	LEFT JOIN recipe_ingredients RI ON RI.recipe_id = R.id
+	LEFT JOIN ingredients I ON I.id = RI.ingredient_id` + filter + expression + exclusion + `
+	ORDER BY R.name, R.id, RI.position, I.name`
+	args = append(append(args, exprArgs...), excluded...)
+
+	rows, err := sqldb.db.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, fmt.Errorf("finding recipes: %w", err)
+	}
+	defer rows.Close()
+
+	recipes, err := scanRecipes(rows)
+	if err != nil {
+		return nil, err
+	}
+	if err = sqldb.loadInstructions(ctx, recipes); err != nil {
+		return nil, err
+	}
+
+	// The column collation decides the order the database returns, which need not match the
+	// byte-wise order memdb uses, so sort again to return the same ordering everywhere
+	sort.SliceStable(recipes, func(i, j int) bool { return recipes[i].Name < recipes[j].Name })
+
+	return recipes, nil
+}
+
+// exprFilter returns the condition which selects the recipes matching the expression, and its arguments,
+// matching each ingredient by its variants. The condition starts a WHERE clause if first is true, or else continues one.
+func exprFilter(expr persistence.Expr, variants func(string) []string, first bool) (string, []any, error) {
+	if expr == nil {
+		return "", nil, nil
+	}
+
+	keyword := "AND"
+	if first {
+		keyword = "WHERE"
+	}
+	condition, args, err := exprCondition(expr, variants)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return `
+	` + keyword + ` ` + condition, args, nil
+}
+
+// exprCondition compiles the expression into an SQL condition on the recipe R, and its arguments
+func exprCondition(expr persistence.Expr, variants func(string) []string) (string, []any, error) {
+	switch e := expr.(type) {
+	case persistence.Term:
+		names := searchNames([]string{string(e)}, variants)
+		return `R.id IN (` + withUsers(`
+		SELECT QRI.recipe_id FROM recipe_ingredients QRI
+		INNER JOIN ingredients QI ON QI.id = QRI.ingredient_id
+		WHERE QI.search_name IN (?`+strings.Repeat(",?", len(names)-1)+`)`) + `
+	)`, names, nil
+	case persistence.Not:
+		condition, args, err := exprCondition(e.Expr, variants)
+		return "NOT " + condition, args, err
+	case persistence.And:
+		return exprConditions(e, variants, " AND ", "1 = 1")
+	case persistence.Or:
+		return exprConditions(e, variants, " OR ", "1 = 0")
+	}
+
+	return "", nil, fmt.Errorf("unsupported expression (%T)", expr)
+}
+
+// exprConditions joins the conditions of the expressions with op in parentheses, or returns
+// empty if there are none
+func exprConditions(exprs []persistence.Expr, variants func(string) []string, op string, empty string) (string, []any, error) {
+	if len(exprs) == 0 {
+		return empty, nil, nil
+	}
+
+	var conditions []string
+	var args []any
+	for _, e := range exprs {
+		condition, a, err := exprCondition(e, variants)
+		if err != nil {
+			return "", nil, err
+		}
+		conditions = append(conditions, condition)
+		args = append(args, a...)
+	}
+
+	return "(" + strings.Join(conditions, op) + ")", args, nil
+}
+
+// excludeFilter returns the condition which drops the recipes using any variant of any of the ingredients, and
+// its arguments. The condition starts a WHERE clause if first is true, or else continues one.
+func excludeFilter(ingredients []string, variants func(string) []string, first bool) (string, []any) {
+	names := searchNames(ingredients, variants)
+	if len(names) == 0 {
+		return "", nil
+	}
+
+	keyword := "AND"
+	if first {
+		keyword = "WHERE"
+	}
+
+	return `
+	` + keyword + ` R.id NOT IN (` + withUsers(`
+		SELECT XRI.recipe_id FROM recipe_ingredients XRI
+		INNER JOIN ingredients XI ON XI.id = XRI.ingredient_id
+		WHERE XI.search_name IN (?`+strings.Repeat(",?", len(names)-1)+`)`) + `
+	)`, names
+}
+
+// matchFilter returns the WHERE clause which selects the recipes matching the query, and its arguments
+func matchFilter(query persistence.Query) (string, []any) {
+	// Drop repeats of the same ingredient, however they are spelt
+	var terms persistence.And
+	seen := make(map[string]bool)
+	for _, ingredient := range query.Ingredients {
+		if key := persistence.IngredientKey(ingredient); !seen[key] {
+			seen[key] = true
+			terms = append(terms, persistence.Term(ingredient))
+		}
+	}
+
+	if len(terms) == 0 {
+		if query.Mode == persistence.MatchAny || query.Mode == persistence.MatchSubset {
+			// No recipe uses any of no ingredients
+			return `
+	WHERE 1 = 0`, nil
+		}
+		// Every recipe uses all of no ingredients at all
+		return "", nil
+	}
+
+	names := searchNames(query.Ingredients, query.Variants)
+	in := "FI.search_name IN (?" + strings.Repeat(",?", len(names)-1) + ")"
+	switch query.Mode {
+	case persistence.MatchAny:
+		return `
+	WHERE R.id IN (` + withUsers(`
+		SELECT FRI.recipe_id FROM recipe_ingredients FRI
+		INNER JOIN ingredients FI ON FI.id = FRI.ingredient_id
+		WHERE `+in) + `
+	)`, names
+	case persistence.MatchSubset:
+		// A recipe must use one of the ingredients, directly or through its components, as for MatchAny,
+		// and count no more than MaxMissing of its own ingredients which are not in the query
+		return `
+	WHERE R.id IN (` + withUsers(`
+		SELECT FRI.recipe_id FROM recipe_ingredients FRI
+		INNER JOIN ingredients FI ON FI.id = FRI.ingredient_id
+		WHERE `+in) + `
+	)
+	AND R.id IN (
+		SELECT FRI.recipe_id FROM recipe_ingredients FRI
+		INNER JOIN ingredients FI ON FI.id = FRI.ingredient_id
+		GROUP BY FRI.recipe_id
+		HAVING SUM(CASE WHEN ` + in + ` THEN 0 ELSE 1 END) <= ?
+	)`, append(append(names, names...), query.MaxMissing)
+	default:
+		// A recipe must use each ingredient, or one of its synonyms (or kinds, if the query is expanded)
+		condition, args, _ := exprCondition(terms, query.Variants)
+		return `
+	WHERE ` + condition, args
+	}
+}
+
+// withUsers extends the query, which selects the ids of recipes, to select the recipes which use any of them as
+// a component too, all the way up
+func withUsers(query string) string {
+	return `
+		WITH RECURSIVE users (id) AS (` + query + `
+		UNION
+		SELECT CRI.recipe_id FROM users U
+		INNER JOIN recipes C ON C.id = U.id
+		INNER JOIN recipe_ingredients CRI ON CRI.display_name = C.name AND CRI.component = 1
+		)
+		SELECT id FROM users`
+}
+
+// components returns the names of the components of the named recipe, as stored
+func components(ctx context.Context, tx *sql.Tx, name string) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT RI.display_name FROM recipe_ingredients RI
+		INNER JOIN recipes R ON R.id = RI.recipe_id
+		WHERE R.name = ? AND RI.component = 1
+		ORDER BY RI.position`,
+		name,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("reading components: %w", err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var component string
+		if err := rows.Scan(&component); err != nil {
+			return nil, fmt.Errorf("reading components: %w", err)
+		}
+		names = append(names, component)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading components: %w", err)
+	}
+
+	return names, nil
+}
+
+// searchNames returns the distinct normalised names which searching for any of the ingredients matches,
+// which are their variants
+func searchNames(ingredients []string, variants func(string) []string) []any {
+	var names []any
+	seen := make(map[string]bool)
+	for _, ingredient := range ingredients {
+		for _, variant := range variants(ingredient) {
+			if !seen[variant] {
+				seen[variant] = true
+				names = append(names, variant)
+			}
+		}
+	}
+
+	return names
+}
+
+func (sqldb *SqlDB) ListRecipes(ctx context.Context, cursor string, limit int) ([]persistence.Recipe, error) {
+	// Page on the recipes table alone, so that LIMIT counts recipes rather than ingredients.
+	// Both the cursor comparison and the order compare names byte-wise, as memdb does, so
+	// pages neither overlap nor skip recipes whatever the column collation is.
+	rows, err := sqldb.db.QueryContext(ctx, `
+		SELECT `+recipeColumns+` FROM (
+			SELECT id, name, description, servings, prep_minutes, cook_minutes, source
+			FROM recipes WHERE `+sqldb.binary("name")+` > ? ORDER BY `+sqldb.binary("name")+` LIMIT ?
+		) R
+		LEFT JOIN recipe_ingredients RI ON RI.recipe_id = R.id
+		LEFT JOIN ingredients I ON I.id = RI.ingredient_id
+		ORDER BY `+sqldb.binary("R.name")+`, RI.position, I.name`,
+		cursor, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing recipes: %w", err)
+	}
+	defer rows.Close()
+
+	recipes, err := scanRecipes(rows)
+	if err != nil {
+		return nil, err
+	}
+	if err = sqldb.loadInstructions(ctx, recipes); err != nil {
+		return nil, err
+	}
+
+	return recipes, nil
+}
+
+func (sqldb *SqlDB) SearchRecipesByName(ctx context.Context, query persistence.NameQuery, cursor string, limit int) ([]persistence.Recipe, error) {
+	// Prefix patterns can use the index on search_name, while substring patterns have to scan it
+	rows, err := sqldb.db.QueryContext(ctx, `
+		SELECT `+recipeColumns+` FROM (
+			SELECT id, name, description, servings, prep_minutes, cook_minutes, source
+			FROM recipes WHERE search_name LIKE ? ESCAPE '!' AND `+sqldb.binary("name")+` > ? ORDER BY `+sqldb.binary("name")+` LIMIT ?
+		) R
+		LEFT JOIN recipe_ingredients RI ON RI.recipe_id = R.id
+		LEFT JOIN ingredients I ON I.id = RI.ingredient_id
+		ORDER BY `+sqldb.binary("R.name")+`, RI.position, I.name`,
+		namePattern(query), cursor, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("searching recipes: %w", err)
+	}
+	defer rows.Close()
+
+	recipes, err := scanRecipes(rows)
+	if err != nil {
+		return nil, err
+	}
+	if err = sqldb.loadInstructions(ctx, recipes); err != nil {
+		return nil, err
+	}
+
+	return recipes, nil
+}
+
+// binary returns the column compared byte-wise, whatever its collation is
+func (sqldb *SqlDB) binary(column string) string {
+	return column + " COLLATE " + sqldb.dialect.Binary
+}
+
+// likeEscaper escapes the wildcards of LIKE patterns, and the ! which escapes them, with !
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// namePattern returns the LIKE pattern of a name query, which escapes wildcards with !
+func namePattern(query persistence.NameQuery) string {
+	text := likeEscaper.Replace(persistence.FoldName(query.Text))
+	if query.Match == persistence.NameSubstring {
+		return "%" + text + "%"
+	}
+
+	return text + "%"
+}
+
+func (sqldb *SqlDB) RecipeNames(ctx context.Context) ([]string, error) {
+	return sqldb.names(ctx, "SELECT name FROM recipes")
+}
+
+func (sqldb *SqlDB) IngredientNames(ctx context.Context) ([]string, error) {
+	return sqldb.names(ctx, "SELECT DISTINCT display_name FROM recipe_ingredients")
+}
+
+func (sqldb *SqlDB) ListIngredients(ctx context.Context) ([]persistence.IngredientUsage, error) {
+	return sqldb.usage(ctx, "", nil, "ORDER BY 1")
+}
+
+func (sqldb *SqlDB) CompleteIngredient(ctx context.Context, prefix string, limit int) ([]persistence.IngredientUsage, error) {
+	if limit < 0 {
+		limit = 0
+	}
+
+	filter, args := completionFilter(prefix)
+	return sqldb.usage(ctx, "WHERE "+filter, append(args, limit), "ORDER BY 2 DESC, 1 LIMIT ?")
+}
+
+func (sqldb *SqlDB) CatalogueStats(ctx context.Context, top int) (persistence.CatalogueStats, error) {
+	if top < 0 {
+		top = 0
+	}
+
+	var stats persistence.CatalogueStats
+	var total int
+	err := sqldb.db.QueryRowContext(ctx, `
+		SELECT (SELECT COUNT(*) FROM recipes), COUNT(DISTINCT I.search_name), COUNT(*) FROM recipe_ingredients RI
+		INNER JOIN ingredients I ON I.id = RI.ingredient_id`,
+	).Scan(&stats.Recipes, &stats.Ingredients, &total)
+	if err != nil {
+		return persistence.CatalogueStats{}, fmt.Errorf("counting recipes: %w", err)
+	}
+	stats.AverageIngredients = persistence.Average(total, stats.Recipes)
+
+	if stats.MostUsed, err = sqldb.usage(ctx, "", []any{top}, "ORDER BY 2 DESC, 1 LIMIT ?"); err != nil {
+		return persistence.CatalogueStats{}, err
+	}
+	if stats.LeastUsed, err = sqldb.usage(ctx, "", []any{top}, "ORDER BY 2, 1 LIMIT ?"); err != nil {
+		return persistence.CatalogueStats{}, err
+	}
+
+	// A recipe is isolated if no other recipe uses an ingredient with the same normalised name as one of its own
+	stats.IsolatedRecipes, err = sqldb.names(ctx, `
+		SELECT R.name FROM recipes R WHERE NOT EXISTS (
+			SELECT 1 FROM recipe_ingredients RI
+			INNER JOIN ingredients I ON I.id = RI.ingredient_id
+			INNER JOIN ingredients OI ON OI.search_name = I.search_name
+			INNER JOIN recipe_ingredients ORI ON ORI.ingredient_id = OI.id
+			WHERE RI.recipe_id = R.id AND ORI.recipe_id <> R.id
+		)
+		ORDER BY R.name`)
+	if err != nil {
+		return persistence.CatalogueStats{}, err
+	}
+
+	return stats, nil
+}
+
+func (sqldb *SqlDB) SimilarRecipes(ctx context.Context, name string, limit int) ([]persistence.SimilarRecipe, error) {
+	if limit < 0 {
+		limit = 0
+	}
+
+	spellings, keys, err := sqldb.ingredientKeys(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return []persistence.SimilarRecipe{}, nil
+	}
+
+	// Count the ingredients which every other recipe shares with this one, and rank them by the Jaccard index
+	// in the query so that only the top ones come back. Multiplying by 1e0 divides in floating point rather than in integers.
+	in := "(?" + strings.Repeat(",?", len(keys)-1) + ")"
+	rows, err := sqldb.db.QueryContext(ctx, `
+		SELECT name, shared, total FROM (
+			SELECT R.name, COUNT(*) AS shared, (SELECT COUNT(*) FROM recipe_ingredients TRI WHERE TRI.recipe_id = R.id) AS total
+			FROM recipe_ingredients RI
+			INNER JOIN ingredients I ON I.id = RI.ingredient_id
+			INNER JOIN recipes R ON R.id = RI.recipe_id
+			WHERE I.search_name IN `+in+` AND R.name <> ?
+			GROUP BY R.id, R.name
+		) S
+		ORDER BY shared * 1e0 / (? + total - shared) DESC, shared DESC, name
+		LIMIT ?`,
+		append(append([]any{}, keys...), name, len(keys), limit)...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("finding similar recipes: %w", err)
+	}
+	defer rows.Close()
+
+	similar := []persistence.SimilarRecipe{}
+	for rows.Next() {
+		var recipe persistence.SimilarRecipe
+		var shared, total int
+		if err := rows.Scan(&recipe.Name, &shared, &total); err != nil {
+			return nil, fmt.Errorf("reading similar recipe: %w", err)
+		}
+		recipe.Similarity = persistence.Jaccard(shared, len(keys), total)
+		similar = append(similar, recipe)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("finding similar recipes: %w", err)
+	}
+	if len(similar) == 0 {
+		return similar, nil
+	}
+
+	// Find which ingredients the top recipes share, and list them as this recipe spells them
+	args := []any{}
+	for _, recipe := range similar {
+		args = append(args, recipe.Name)
+	}
+	rows, err = sqldb.db.QueryContext(ctx, `
+		SELECT R.name, I.search_name FROM recipe_ingredients RI
+		INNER JOIN ingredients I ON I.id = RI.ingredient_id
+		INNER JOIN recipes R ON R.id = RI.recipe_id
+		WHERE R.name IN (?`+strings.Repeat(",?", len(args)-1)+`) AND I.search_name IN `+in,
+		append(args, keys...)...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("finding shared ingredients: %w", err)
+	}
+	defer rows.Close()
+
+	shared := make(map[string]map[string]bool, len(similar))
+	for rows.Next() {
+		var rname, key string
+		if err := rows.Scan(&rname, &key); err != nil {
+			return nil, fmt.Errorf("reading shared ingredient: %w", err)
+		}
+		if shared[rname] == nil {
+			shared[rname] = make(map[string]bool)
+		}
+		shared[rname][key] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("finding shared ingredients: %w", err)
+	}
+
+	for i := range similar {
+		similar[i].Shared = []string{}
+		for j, key := range keys {
+			if shared[similar[i].Name][key.(string)] {
+				similar[i].Shared = append(similar[i].Shared, spellings[j])
+			}
+		}
+	}
+	persistence.SortBySimilarity(similar)
+
+	return similar, nil
+}
+
+// ingredientKeys returns the names of the ingredients of the named recipe as it spells them and their normalised
+// names, in recipe order, or ErrNoResults if there is no such recipe
+func (sqldb *SqlDB) ingredientKeys(ctx context.Context, name string) ([]string, []any, error) {
+	// Join from recipes, so that a recipe without ingredients still returns a single row
+	rows, err := sqldb.db.QueryContext(ctx, `
+		SELECT RI.display_name, I.search_name FROM recipes R
+		LEFT JOIN recipe_ingredients RI ON RI.recipe_id = R.id
+		LEFT JOIN ingredients I ON I.id = RI.ingredient_id
+		WHERE R.name = ?
+		ORDER BY RI.position`,
+		name,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading ingredients: %w", err)
+	}
+	defer rows.Close()
+
+	found := false
+	var spellings []string
+	var keys []any
+	for rows.Next() {
+		var spelling, key sql.NullString
+		if err := rows.Scan(&spelling, &key); err != nil {
+			return nil, nil, fmt.Errorf("reading ingredient: %w", err)
+		}
+		found = true
+		if spelling.Valid {
+			spellings = append(spellings, spelling.String)
+			keys = append(keys, key.String)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("reading ingredients: %w", err)
+	}
+	if !found {
+		return nil, nil, persistence.ErrNoResults
+	}
+
+	return spellings, keys, nil
+}
+
+// usage counts the recipes which use each ingredient that passes the filter, one row for each normalised
+// name under its first spelling, ordered by order
+func (sqldb *SqlDB) usage(ctx context.Context, filter string, args []any, order string) ([]persistence.IngredientUsage, error) {
+	rows, err := sqldb.db.QueryContext(ctx, `
+		SELECT MIN(RI.display_name), COUNT(DISTINCT RI.recipe_id) FROM recipe_ingredients RI
+		INNER JOIN ingredients I ON I.id = RI.ingredient_id `+filter+`
+		GROUP BY I.search_name `+order,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("counting ingredients: %w", err)
+	}
+	defer rows.Close()
+
+	ingredients := []persistence.IngredientUsage{}
+	for rows.Next() {
+		var ingredient persistence.IngredientUsage
+		if err := rows.Scan(&ingredient.Name, &ingredient.Recipes); err != nil {
+			return nil, fmt.Errorf("reading ingredient: %w", err)
+		}
+		ingredients = append(ingredients, ingredient)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("counting ingredients: %w", err)
+	}
+
+	return ingredients, nil
+}
+
+// completionFilter returns the condition which keeps the ingredients that complete the prefix, and its arguments
+func completionFilter(prefix string) (string, []any) {
+	var conditions []string
+	var args []any
+	for _, p := range persistence.CompletionPrefixes(prefix) {
+		conditions = append(conditions, "I.search_name LIKE ? ESCAPE '!'")
+		args = append(args, likeEscaper.Replace(p)+"%")
+	}
+
+	return "(" + strings.Join(conditions, " OR ") + ")", args
+}
+
+// names returns the single string column of the rows of the query
+func (sqldb *SqlDB) names(ctx context.Context, query string) ([]string, error) {
+	rows, err := sqldb.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("reading names: %w", err)
+	}
+	defer rows.Close()
+
+	names := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("reading name: %w", err)
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading names: %w", err)
+	}
+
+	return names, nil
+}
+
+// ingredientRow holds the ingredient columns of a row from a LEFT JOIN, which are all NULL
+// for a recipe without ingredients
+type ingredientRow struct {
+	name      sql.NullString
+	quantity  sql.NullFloat64
+	unit      sql.NullString
+	note      sql.NullString
+	component sql.NullBool
+}
+
+func (i *ingredientRow) ingredient() persistence.Ingredient {
+	return persistence.Ingredient{Name: i.name.String, Quantity: i.quantity.Float64, Unit: i.unit.String, Note: i.note.String, Component: i.component.Bool}
+}
+
+// recipeColumns are the columns which scanRecipes reads, from recipes R joined to recipe_ingredients RI and ingredients I
+const recipeColumns = "R.name, R.description, R.servings, R.prep_minutes, R.cook_minutes, R.source, RI.display_name, RI.quantity, RI.unit, RI.note, RI.component"
+
+// scanRecipes reads rows of recipeColumns, which must arrive grouped by recipe with the
+// ingredients of each recipe in order. A recipe without ingredients arrives as a single
+// row with a NULL ingredient name. Instructions are not part of the rows, see loadInstructions.
+func scanRecipes(rows *sql.Rows) ([]persistence.Recipe, error) {
+	recipes := []persistence.Recipe{}
+
+	var recipe persistence.Recipe
+	var ingredient ingredientRow
+	for rows.Next() {
+		err := rows.Scan(&recipe.Name, &recipe.Description, &recipe.Servings, &recipe.PrepMinutes, &recipe.CookMinutes, &recipe.Source,
+			&ingredient.name, &ingredient.quantity, &ingredient.unit, &ingredient.note, &ingredient.component)
+		if err != nil {
+			return nil, fmt.Errorf("reading recipe: %w", err)
+		}
+		if len(recipes) == 0 || recipes[len(recipes)-1].Name != recipe.Name {
+			recipes = append(recipes, recipe)
+		}
+		if ingredient.name.Valid {
+			last := &recipes[len(recipes)-1]
+			last.Ingredients = append(last.Ingredients, ingredient.ingredient())
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading recipes: %w", err)
+	}
+
+	return recipes, nil
+}
+
+// loadInstructions reads the instructions of recipes, a batch of recipes per query
+func (sqldb *SqlDB) loadInstructions(ctx context.Context, recipes []persistence.Recipe) error {
+	const batch = 500
+
+	index := make(map[string]int, len(recipes))
+	for i, recipe := range recipes {
+		index[recipe.Name] = i
+	}
+
+	for start := 0; start < len(recipes); start += batch {
+		end := start + batch
+		if end > len(recipes) {
+			end = len(recipes)
+		}
+
+		var args []any
+		for _, recipe := range recipes[start:end] {
+			args = append(args, recipe.Name)
+		}
+
+		rows, err := sqldb.db.QueryContext(ctx, `
+			SELECT R.name, S.instruction FROM recipe_steps S
+			INNER JOIN recipes R ON R.id = S.recipe_id
+			WHERE R.name IN (?`+strings.Repeat(",?", len(args)-1)+`)
+			ORDER BY R.name, S.position`,
+			args...,
+		)
+		if err != nil {
+			return fmt.Errorf("reading instructions: %w", err)
+		}
+
+		var rname, instruction string
+		for rows.Next() {
+			if err := rows.Scan(&rname, &instruction); err != nil {
+				rows.Close()
+				return fmt.Errorf("reading instruction: %w", err)
+			}
+			if i, ok := index[rname]; ok {
+				recipes[i].Instructions = append(recipes[i].Instructions, instruction)
+			}
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return fmt.Errorf("reading instructions: %w", err)
+		}
+	}
+
+	return nil
+}
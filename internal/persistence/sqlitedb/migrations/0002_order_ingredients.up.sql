@@ -0,0 +1 @@
+ALTER TABLE recipe_ingredients ADD COLUMN position INTEGER NOT NULL DEFAULT 0;
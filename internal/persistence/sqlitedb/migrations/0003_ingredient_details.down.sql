@@ -0,0 +1,3 @@
+ALTER TABLE recipe_ingredients DROP COLUMN note;
+ALTER TABLE recipe_ingredients DROP COLUMN unit;
+ALTER TABLE recipe_ingredients DROP COLUMN quantity;
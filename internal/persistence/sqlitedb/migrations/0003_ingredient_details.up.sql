@@ -0,0 +1,3 @@
+ALTER TABLE recipe_ingredients ADD COLUMN quantity REAL NOT NULL DEFAULT 0;
+ALTER TABLE recipe_ingredients ADD COLUMN unit TEXT NOT NULL DEFAULT '';
+ALTER TABLE recipe_ingredients ADD COLUMN note TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,12 @@
+ALTER TABLE recipes ADD COLUMN description TEXT NOT NULL DEFAULT '';
+ALTER TABLE recipes ADD COLUMN servings INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE recipes ADD COLUMN prep_minutes INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE recipes ADD COLUMN cook_minutes INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE recipes ADD COLUMN source TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS recipe_steps (
+    recipe_id INTEGER NOT NULL REFERENCES recipes (id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    instruction TEXT NOT NULL,
+    PRIMARY KEY (recipe_id, position)
+);
@@ -0,0 +1,4 @@
+ALTER TABLE recipe_ingredients DROP COLUMN display_name;
+
+DROP INDEX IF EXISTS ingredients_search_name;
+ALTER TABLE ingredients DROP COLUMN search_name;
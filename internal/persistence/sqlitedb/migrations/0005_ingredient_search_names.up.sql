@@ -0,0 +1,5 @@
+ALTER TABLE ingredients ADD COLUMN search_name TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS ingredients_search_name ON ingredients (search_name);
+
+ALTER TABLE recipe_ingredients ADD COLUMN display_name TEXT NOT NULL DEFAULT '';
+UPDATE recipe_ingredients SET display_name = (SELECT name FROM ingredients WHERE ingredients.id = recipe_ingredients.ingredient_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS recipes_search_name;
+ALTER TABLE recipes DROP COLUMN search_name;
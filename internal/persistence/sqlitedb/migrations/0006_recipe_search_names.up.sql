@@ -0,0 +1,2 @@
+ALTER TABLE recipes ADD COLUMN search_name TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS recipes_search_name ON recipes (search_name);
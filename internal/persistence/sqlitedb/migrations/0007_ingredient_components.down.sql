@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS recipe_ingredients_component;
+ALTER TABLE recipe_ingredients DROP COLUMN component;
@@ -0,0 +1,2 @@
+ALTER TABLE recipe_ingredients ADD COLUMN component INTEGER NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS recipe_ingredients_component ON recipe_ingredients (display_name, component);
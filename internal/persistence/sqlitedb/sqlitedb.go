@@ -0,0 +1,68 @@
+package sqlitedb
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"go-incubator/internal/persistence/sqldb"
+	"io/fs"
+
+	_ "modernc.org/sqlite"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// dialect is the SQLite flavour of the SQL which sqldb writes. A SQLite file has a single
+// writer at a time, so migrations need no lock of their own.
+var dialect = sqldb.Dialect{
+	InsertIgnore: "INSERT OR IGNORE",
+	Binary:       "BINARY",
+}
+
+type SqliteDB struct {
+	sqldb.SqlDB
+}
+
+// NewSqliteDB opens (or creates) the SQLite file at path and applies any schema
+// migrations that have not been applied yet
+func NewSqliteDB(path string) (SqliteDB, error) {
+	sqdb, err := OpenSqliteDB(path)
+	if err != nil {
+		return sqdb, err
+	}
+
+	err = sqdb.MigrateUp(context.Background())
+	if err != nil {
+		return sqdb, fmt.Errorf("migrating database: %w", err)
+	}
+
+	return sqdb, nil
+}
+
+// OpenSqliteDB opens (or creates) the SQLite file at path without touching its schema
+func OpenSqliteDB(path string) (SqliteDB, error) {
+	// Enforce foreign keys and wait for concurrent writers rather than fail
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return SqliteDB{}, fmt.Errorf("opening database: %w", err)
+	}
+
+	// SQLite allows a single writer at a time, so serialise all access through one
+	// connection rather than have concurrent requests fail with "database is locked"
+	db.SetMaxOpenConns(1)
+
+	dir, err := fs.Sub(migrations, "migrations")
+	if err != nil {
+		return SqliteDB{}, fmt.Errorf("reading migrations: %w", err)
+	}
+	sqdb := SqliteDB{SqlDB: sqldb.NewSqlDB(db, dialect, dir)}
+
+	err = db.Ping()
+	if err != nil {
+		return sqdb, fmt.Errorf("pinging database: %w", err)
+	}
+
+	return sqdb, nil
+}
@@ -0,0 +1,124 @@
+package sqlitedb
+
+import (
+	"context"
+	"database/sql"
+	"go-incubator/internal/persistence"
+	"go-incubator/internal/persistence/persistencetest"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func newTestDB(t *testing.T) (SqliteDB, string) {
+	path := filepath.Join(t.TempDir(), "recipes.db")
+	db, err := NewSqliteDB(path)
+	if err != nil {
+		t.Fatalf("NewSqliteDB() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	for _, recipe := range persistencetest.Fixtures {
+		if err := db.AddRecipe(context.Background(), recipe); err != nil {
+			t.Fatalf("SqliteDB.AddRecipe() error = %v", err)
+		}
+	}
+
+	return db, path
+}
+
+func TestSqliteDB_Conformance(t *testing.T) {
+	persistencetest.Run(t, func(t *testing.T) persistence.Persistence {
+		db, err := NewSqliteDB(filepath.Join(t.TempDir(), "recipes.db"))
+		if err != nil {
+			t.Fatalf("NewSqliteDB() error = %v", err)
+		}
+		t.Cleanup(func() { db.Close() })
+		return &db
+	})
+}
+
+func TestSqliteDB_AddRecipe(t *testing.T) {
+	db, path := newTestDB(t)
+	ctx := context.Background()
+
+	// Replacing a recipe must drop its old ingredients
+	err := db.AddRecipe(ctx, persistence.Recipe{Name: "Meatballs", Ingredients: persistence.NamedIngredients([]string{"Ground Beef", "Onion"})})
+	if err != nil {
+		t.Fatalf("SqliteDB.AddRecipe() error = %v", err)
+	}
+
+	// Recipes must survive closing and reopening the file
+	db.Close()
+	db, err = NewSqliteDB(path)
+	if err != nil {
+		t.Fatalf("NewSqliteDB() error = %v", err)
+	}
+	defer db.Close()
+
+	got, err := db.GetRecipe(ctx, "Meatballs")
+	want := persistence.Recipe{Name: "Meatballs", Ingredients: persistence.NamedIngredients([]string{"Ground Beef", "Onion"})}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("SqliteDB.GetRecipe() = %v, %v, want %v", got, err, want)
+	}
+}
+
+func TestSqliteDB_DeleteRecipe(t *testing.T) {
+	db, path := newTestDB(t)
+
+	tests := []struct {
+		name    string
+		rname   string
+		wantErr error
+	}{
+		{name: "1", rname: "BLT", wantErr: nil},
+		{name: "2", rname: "BLT", wantErr: persistence.ErrNoResults},
+		{name: "3", rname: "Pizza", wantErr: persistence.ErrNoResults},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := db.DeleteRecipe(context.Background(), tt.rname); err != tt.wantErr {
+				t.Errorf("SqliteDB.DeleteRecipe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Look at the tables themselves, through a connection of our own
+	raw, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	defer raw.Close()
+
+	for _, table := range []string{"recipe_ingredients", "recipe_steps"} {
+		var count int
+		raw.QueryRow("SELECT COUNT(*) FROM " + table + " WHERE recipe_id NOT IN (SELECT id FROM recipes)").Scan(&count)
+		if count != 0 {
+			t.Errorf("SqliteDB.DeleteRecipe() left %d orphaned %s rows", count, table)
+		}
+	}
+}
+
+func TestSqliteDB_Migrations(t *testing.T) {
+	db, _ := newTestDB(t)
+	ctx := context.Background()
+
+	version, err := db.SchemaVersion(ctx)
+	if err != nil || version == 0 {
+		t.Fatalf("SqliteDB.SchemaVersion() = %v, %v, want a migrated schema", version, err)
+	}
+
+	if err := db.MigrateDown(ctx, version); err != nil {
+		t.Fatalf("SqliteDB.MigrateDown() error = %v", err)
+	}
+	if version, _ := db.SchemaVersion(ctx); version != 0 {
+		t.Errorf("SqliteDB.SchemaVersion() = %v, want 0", version)
+	}
+
+	if err := db.MigrateUp(ctx); err != nil {
+		t.Fatalf("SqliteDB.MigrateUp() error = %v", err)
+	}
+	if _, err := db.FindRecipes(ctx, []string{"Tomato"}); err != nil {
+		t.Errorf("SqliteDB.FindRecipes() after migrating up error = %v", err)
+	}
+}
@@ -0,0 +1,27 @@
+package persistence
+
+// StatsTop is the number of most and least used ingredients which the servers report in CatalogueStats
+const StatsTop = 5
+
+// CatalogueStats summarises all recipes. Ingredients are counted as ListIngredients counts them.
+type CatalogueStats struct {
+	Recipes     int
+	Ingredients int
+	// MostUsed and LeastUsed are the ingredients which the most and fewest recipes use, most and least used
+	// first respectively, and in name order when used equally often
+	MostUsed  []IngredientUsage
+	LeastUsed []IngredientUsage
+	// AverageIngredients is the mean number of ingredients per recipe, or 0 without recipes
+	AverageIngredients float64
+	// IsolatedRecipes are the names of the recipes which share no ingredient with any other recipe, in name order
+	IsolatedRecipes []string
+}
+
+// Average returns the mean number of ingredients per recipe for a total number of ingredients over all recipes
+func Average(ingredients, recipes int) float64 {
+	if recipes == 0 {
+		return 0
+	}
+
+	return float64(ingredients) / float64(recipes)
+}
@@ -0,0 +1,260 @@
+package persistence
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrTaxonomyCycle is returned when an ingredient would become a kind of itself
+var ErrTaxonomyCycle = errors.New("taxonomy: an ingredient cannot be a kind of itself")
+
+// Kind records that an ingredient is a kind of a broader one, its parent, such as Mozzarella is a kind of Cheese
+type Kind struct {
+	Ingredient string
+	Parent     string
+}
+
+// taxonomy is the hierarchy of ingredients which searches with Query.Expand use. Like the synonym
+// table, backends do not store it, so that it can change without touching stored recipes.
+var taxonomy = struct {
+	mu sync.RWMutex
+	// kinds maps the normalised name of each ingredient which has a parent to its Kind, as it was spelt
+	kinds map[string]Kind
+	// children maps the normalised name of each parent to the normalised names of its kinds
+	children map[string][]string
+	// path is the file which the taxonomy was loaded from and is saved to when it changes, if any
+	path string
+}{kinds: map[string]Kind{}, children: map[string][]string{}}
+
+// SetTaxonomy replaces the taxonomy with the kinds, which are not saved anywhere. It returns ErrTaxonomyCycle,
+// and leaves the taxonomy as it was, if an ingredient would be a kind of itself.
+func SetTaxonomy(kinds []Kind) error {
+	return replaceTaxonomy(kinds, "")
+}
+
+// LoadTaxonomy replaces the taxonomy with the one in a JSON file, which maps each ingredient to its
+// parent such as {"Mozzarella": "Cheese", "Cheese": "Dairy"}, and saves later changes to the same file.
+// A file which does not exist yet holds an empty taxonomy.
+func LoadTaxonomy(path string) error {
+	var parents map[string]string
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return fmt.Errorf("reading taxonomy: %w", err)
+	default:
+		if err := json.Unmarshal(data, &parents); err != nil {
+			return fmt.Errorf("reading taxonomy: %w", err)
+		}
+	}
+
+	// Sort the ingredients, so that a file with several spellings of one ingredient always loads the same way
+	names := make([]string, 0, len(parents))
+	for name := range parents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	kinds := make([]Kind, 0, len(names))
+	for _, name := range names {
+		kinds = append(kinds, Kind{Ingredient: name, Parent: parents[name]})
+	}
+
+	return replaceTaxonomy(kinds, path)
+}
+
+// Taxonomy returns all kinds in the taxonomy, in order of their ingredients
+func Taxonomy() []Kind {
+	taxonomy.mu.RLock()
+	defer taxonomy.mu.RUnlock()
+
+	kinds := make([]Kind, 0, len(taxonomy.kinds))
+	for _, kind := range taxonomy.kinds {
+		kinds = append(kinds, kind)
+	}
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i].Ingredient < kinds[j].Ingredient })
+
+	return kinds
+}
+
+// SetKind makes an ingredient a kind of parent in the taxonomy, replacing its previous parent, or with
+// an empty parent removes it from the taxonomy (but not its own kinds). It returns ErrTaxonomyCycle
+// if parent is already a kind of the ingredient. If the taxonomy was loaded from a file, it is saved there.
+func SetKind(ingredient, parent string) error {
+	taxonomy.mu.Lock()
+	defer taxonomy.mu.Unlock()
+
+	kinds := make(map[string]Kind, len(taxonomy.kinds)+1)
+	for k, v := range taxonomy.kinds {
+		kinds[k] = v
+	}
+	if err := setKind(kinds, Kind{Ingredient: ingredient, Parent: parent}); err != nil {
+		return err
+	}
+
+	if taxonomy.path != "" {
+		if err := saveTaxonomy(taxonomy.path, kinds); err != nil {
+			return err
+		}
+	}
+	taxonomy.kinds = kinds
+	taxonomy.children = childrenOf(kinds)
+
+	return nil
+}
+
+// IsA returns true if ingredient is the same as kind or, following the taxonomy, a kind of it, such as
+// Mozzarella is a Cheese and a Dairy product. Synonyms of both are taken into account.
+func IsA(ingredient, kind string) bool {
+	targets := map[string]struct{}{}
+	for _, variant := range IngredientVariants(kind) {
+		targets[variant] = struct{}{}
+	}
+
+	taxonomy.mu.RLock()
+	defer taxonomy.mu.RUnlock()
+
+	return isA(taxonomy.kinds, ingredient, targets)
+}
+
+// ExpandIngredient returns the normalised names which a search for the ingredient matches when it is
+// expanded: those of IngredientVariants followed by those of all of its kinds in the taxonomy
+func ExpandIngredient(name string) []string {
+	taxonomy.mu.RLock()
+	defer taxonomy.mu.RUnlock()
+
+	names := IngredientVariants(name)
+	seen := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		seen[name] = struct{}{}
+	}
+	for i := 0; i < len(names); i++ {
+		for _, child := range taxonomy.children[names[i]] {
+			for _, variant := range IngredientVariants(child) {
+				if _, ok := seen[variant]; !ok {
+					seen[variant] = struct{}{}
+					names = append(names, variant)
+				}
+			}
+		}
+	}
+
+	return names
+}
+
+// replaceTaxonomy replaces the taxonomy with the kinds, which are saved to path when they change if it is not empty
+func replaceTaxonomy(kinds []Kind, path string) error {
+	built := map[string]Kind{}
+	for _, kind := range kinds {
+		if err := setKind(built, kind); err != nil {
+			return err
+		}
+	}
+
+	taxonomy.mu.Lock()
+	defer taxonomy.mu.Unlock()
+	taxonomy.kinds = built
+	taxonomy.children = childrenOf(built)
+	taxonomy.path = path
+
+	return nil
+}
+
+// setKind sets the parent of an ingredient in kinds, or removes it for an empty parent
+func setKind(kinds map[string]Kind, kind Kind) error {
+	key := NormaliseIngredient(kind.Ingredient)
+	if key == "" {
+		return fmt.Errorf("taxonomy: no ingredient specified")
+	}
+	if strings.TrimSpace(kind.Parent) == "" {
+		delete(kinds, key)
+		return nil
+	}
+
+	targets := map[string]struct{}{}
+	for _, variant := range IngredientVariants(kind.Ingredient) {
+		targets[variant] = struct{}{}
+	}
+	if isA(kinds, kind.Parent, targets) {
+		return fmt.Errorf("%w (%s is a %s)", ErrTaxonomyCycle, kind.Parent, kind.Ingredient)
+	}
+	kinds[key] = Kind{Ingredient: strings.TrimSpace(kind.Ingredient), Parent: strings.TrimSpace(kind.Parent)}
+
+	return nil
+}
+
+// isA returns true if the ingredient, or any of its ancestors in kinds, has a normalised name in targets
+func isA(kinds map[string]Kind, ingredient string, targets map[string]struct{}) bool {
+	frontier := IngredientVariants(ingredient)
+	seen := map[string]struct{}{}
+	for len(frontier) > 0 {
+		var next []string
+		for _, name := range frontier {
+			if _, ok := targets[name]; ok {
+				return true
+			}
+			if _, ok := seen[name]; ok {
+				continue
+			}
+			seen[name] = struct{}{}
+			if kind, ok := kinds[name]; ok {
+				next = append(next, IngredientVariants(kind.Parent)...)
+			}
+		}
+		frontier = next
+	}
+
+	return false
+}
+
+// childrenOf indexes kinds by the normalised names of their parents, in order
+func childrenOf(kinds map[string]Kind) map[string][]string {
+	children := make(map[string][]string)
+	for key, kind := range kinds {
+		parent := NormaliseIngredient(kind.Parent)
+		children[parent] = append(children[parent], key)
+	}
+	for _, names := range children {
+		sort.Strings(names)
+	}
+
+	return children
+}
+
+// saveTaxonomy writes kinds to the file at path in the format LoadTaxonomy reads, replacing it
+// in one step so that a crash cannot leave half a file behind
+func saveTaxonomy(path string, kinds map[string]Kind) error {
+	parents := make(map[string]string, len(kinds))
+	for _, kind := range kinds {
+		parents[kind.Ingredient] = kind.Parent
+	}
+	data, err := json.MarshalIndent(parents, "", "  ")
+	if err != nil {
+		return fmt.Errorf("writing taxonomy: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("writing taxonomy: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing taxonomy: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing taxonomy: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("writing taxonomy: %w", err)
+	}
+
+	return nil
+}
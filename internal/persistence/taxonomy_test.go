@@ -0,0 +1,174 @@
+package persistence
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// setTestTaxonomy replaces the taxonomy for the rest of the test
+func setTestTaxonomy(t *testing.T, kinds []Kind) {
+	if err := SetTaxonomy(kinds); err != nil {
+		t.Fatalf("SetTaxonomy() error = %v", err)
+	}
+	t.Cleanup(func() { SetTaxonomy(nil) })
+}
+
+func TestIsA(t *testing.T) {
+	setTestTaxonomy(t, []Kind{{Ingredient: "Mozzarella", Parent: "Cheese"}, {Ingredient: "Cheese", Parent: "Dairy"}, {Ingredient: "Aubergine", Parent: "Vegetable"}})
+	SetSynonyms([][]string{{"Aubergine", "Eggplant"}})
+	t.Cleanup(func() { SetSynonyms(nil) })
+
+	tests := []struct {
+		name       string
+		ingredient string
+		kind       string
+		want       bool
+	}{
+		{name: "1", ingredient: "Mozzarella", kind: "Cheese", want: true},
+		{name: "2", ingredient: "mozzarella", kind: "DAIRY", want: true},
+		{name: "3", ingredient: "Cheese", kind: "Mozzarella", want: false},
+		{name: "4", ingredient: "Cheeses", kind: "cheese", want: true},
+		{name: "5", ingredient: "Eggplants", kind: "Vegetable", want: true},
+		{name: "6", ingredient: "Tomato", kind: "Vegetable", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsA(tt.ingredient, tt.kind); got != tt.want {
+				t.Errorf("IsA() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandIngredient(t *testing.T) {
+	setTestTaxonomy(t, []Kind{{Ingredient: "Mozzarella", Parent: "Cheese"}, {Ingredient: "Gruyere", Parent: "Cheese"}, {Ingredient: "Cheese", Parent: "Dairy"}, {Ingredient: "Butter", Parent: "Dairy"}})
+	SetSynonyms([][]string{{"Mozzarella", "Fior di Latte"}})
+	t.Cleanup(func() { SetSynonyms(nil) })
+
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{name: "1", in: "Dairy", want: []string{"dairy", "butter", "cheese", "gruyere", "mozzarella", "fior di latte"}},
+		{name: "2", in: "cheeses", want: []string{"cheese", "gruyere", "mozzarella", "fior di latte"}},
+		{name: "3", in: "Fior di Latte", want: []string{"mozzarella", "fior di latte"}},
+		{name: "4", in: "Tomato", want: []string{"tomato"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExpandIngredient(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExpandIngredient() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetKind(t *testing.T) {
+	setTestTaxonomy(t, []Kind{{Ingredient: "Mozzarella", Parent: "Cheese"}, {Ingredient: "Cheese", Parent: "Dairy"}})
+
+	tests := []struct {
+		name       string
+		ingredient string
+		parent     string
+		wantErr    error
+		want       []Kind
+	}{
+		{
+			name:       "1",
+			ingredient: "Feta",
+			parent:     "Cheese",
+			wantErr:    nil,
+			want:       []Kind{{Ingredient: "Cheese", Parent: "Dairy"}, {Ingredient: "Feta", Parent: "Cheese"}, {Ingredient: "Mozzarella", Parent: "Cheese"}},
+		},
+		{
+			name:       "2",
+			ingredient: "Dairy",
+			parent:     "mozzarella",
+			wantErr:    ErrTaxonomyCycle,
+			want:       []Kind{{Ingredient: "Cheese", Parent: "Dairy"}, {Ingredient: "Feta", Parent: "Cheese"}, {Ingredient: "Mozzarella", Parent: "Cheese"}},
+		},
+		{
+			name:       "3",
+			ingredient: "Cheeses",
+			parent:     "Cheese",
+			wantErr:    ErrTaxonomyCycle,
+			want:       []Kind{{Ingredient: "Cheese", Parent: "Dairy"}, {Ingredient: "Feta", Parent: "Cheese"}, {Ingredient: "Mozzarella", Parent: "Cheese"}},
+		},
+		{
+			name:       "4",
+			ingredient: "feta",
+			parent:     "Dairy",
+			wantErr:    nil,
+			want:       []Kind{{Ingredient: "Cheese", Parent: "Dairy"}, {Ingredient: "Mozzarella", Parent: "Cheese"}, {Ingredient: "feta", Parent: "Dairy"}},
+		},
+		{
+			name:       "5",
+			ingredient: "Cheese",
+			parent:     "",
+			wantErr:    nil,
+			want:       []Kind{{Ingredient: "Mozzarella", Parent: "Cheese"}, {Ingredient: "feta", Parent: "Dairy"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SetKind(tt.ingredient, tt.parent); !errors.Is(err, tt.wantErr) {
+				t.Errorf("SetKind() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := Taxonomy(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Taxonomy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetTaxonomy_Cycle(t *testing.T) {
+	setTestTaxonomy(t, []Kind{{Ingredient: "Mozzarella", Parent: "Cheese"}})
+
+	err := SetTaxonomy([]Kind{{Ingredient: "A", Parent: "B"}, {Ingredient: "B", Parent: "C"}, {Ingredient: "C", Parent: "A"}})
+	if !errors.Is(err, ErrTaxonomyCycle) {
+		t.Errorf("SetTaxonomy() error = %v, want %v", err, ErrTaxonomyCycle)
+	}
+	if got, want := Taxonomy(), []Kind{{Ingredient: "Mozzarella", Parent: "Cheese"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Taxonomy() = %v, want %v", got, want)
+	}
+}
+
+func TestLoadTaxonomy(t *testing.T) {
+	t.Cleanup(func() { SetTaxonomy(nil) })
+	path := filepath.Join(t.TempDir(), "taxonomy.json")
+
+	// A file which does not exist yet holds an empty taxonomy, and is created by the first change
+	if err := LoadTaxonomy(path); err != nil {
+		t.Fatalf("LoadTaxonomy() error = %v", err)
+	}
+	if got := Taxonomy(); len(got) != 0 {
+		t.Errorf("Taxonomy() = %v, want []", got)
+	}
+	if err := SetKind("Mozzarella", "Cheese"); err != nil {
+		t.Fatalf("SetKind() error = %v", err)
+	}
+	if err := SetKind("Cheese", "Dairy"); err != nil {
+		t.Fatalf("SetKind() error = %v", err)
+	}
+
+	SetTaxonomy(nil)
+	if err := LoadTaxonomy(path); err != nil {
+		t.Fatalf("LoadTaxonomy() error = %v", err)
+	}
+	want := []Kind{{Ingredient: "Cheese", Parent: "Dairy"}, {Ingredient: "Mozzarella", Parent: "Cheese"}}
+	if got := Taxonomy(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Taxonomy() = %v, want %v", got, want)
+	}
+
+	// A file with a cycle is refused
+	if err := os.WriteFile(path, []byte(`{"Cheese": "Mozzarella", "Mozzarella": "Cheese"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadTaxonomy(path); !errors.Is(err, ErrTaxonomyCycle) {
+		t.Errorf("LoadTaxonomy() error = %v, want %v", err, ErrTaxonomyCycle)
+	}
+}
@@ -45,3 +45,45 @@ func GetValue(prompt string) string {
 	}
 	return strings.TrimSpace(str)
 }
+
+// GetCompletedValue prompts the user for a single string value like GetValue, except that a value ending in ?
+// is completed: the user picks one of the completions of the rest of it, or is asked again
+func GetCompletedValue(prompt string, complete func(prefix string) ([]string, error)) string {
+	const other = "Something else"
+	for {
+		s := GetValue(prompt)
+		if !strings.HasSuffix(s, "?") {
+			return s
+		}
+
+		prefix := strings.TrimSpace(strings.TrimSuffix(s, "?"))
+		completions, err := complete(prefix)
+		if err != nil {
+			fmt.Printf("unable to complete %s: %v\n", prefix, err)
+			continue
+		}
+		if len(completions) == 0 {
+			fmt.Printf("nothing starts with %s\n", prefix)
+			continue
+		}
+
+		if choice := Selection(fmt.Sprintf("Which of these did you mean by %s?", s), append(completions, other)); choice != other {
+			return choice
+		}
+	}
+}
+
+// GetNumber prompts the user for a whole number which is not negative, returning 0 if they enter nothing
+func GetNumber(prompt string) int {
+	for {
+		s := GetValue(prompt)
+		if s == "" {
+			return 0
+		}
+		n, err := strconv.Atoi(s)
+		if err == nil && n >= 0 {
+			return n
+		}
+		fmt.Printf("invalid number (%s)\n", s)
+	}
+}
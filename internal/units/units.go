@@ -0,0 +1,179 @@
+// Package units converts, scales and rounds quantities in the units of measurement that recipes use
+package units
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Dimension is what a unit of measurement measures
+type Dimension int
+
+const (
+	Mass Dimension = iota + 1
+	Volume
+)
+
+// System is a system of units of measurement
+type System string
+
+const (
+	Metric   System = "metric"
+	Imperial System = "imperial"
+)
+
+// Unit is a unit of measurement which quantities can be converted from and to
+type Unit struct {
+	// Name is the usual abbreviation of the unit, such as "g" or "tbsp"
+	Name      string
+	Dimension Dimension
+	System    System
+	// Size is the size of the unit in grams or millilitres
+	Size float64
+	// Min is the smallest quantity which Normalise keeps in the unit rather than in a smaller one
+	Min float64
+}
+
+// table lists the units of each dimension and system from the smallest to the largest. Imperial
+// volumes are US customary cups and spoons.
+var table = []Unit{
+	{Name: "mg", Dimension: Mass, System: Metric, Size: 0.001},
+	{Name: "g", Dimension: Mass, System: Metric, Size: 1, Min: 0.1},
+	{Name: "kg", Dimension: Mass, System: Metric, Size: 1000, Min: 1},
+	{Name: "oz", Dimension: Mass, System: Imperial, Size: 28.349523125},
+	{Name: "lb", Dimension: Mass, System: Imperial, Size: 453.59237, Min: 1},
+	{Name: "ml", Dimension: Volume, System: Metric, Size: 1},
+	{Name: "l", Dimension: Volume, System: Metric, Size: 1000, Min: 1},
+	{Name: "tsp", Dimension: Volume, System: Imperial, Size: 4.92892159375},
+	{Name: "tbsp", Dimension: Volume, System: Imperial, Size: 14.78676478125, Min: 1},
+	{Name: "cup", Dimension: Volume, System: Imperial, Size: 236.5882365, Min: 0.25},
+}
+
+// aliases maps the lower case spellings of each unit to its name
+var aliases = map[string]string{
+	"mg": "mg", "milligram": "mg", "milligrams": "mg",
+	"g": "g", "gram": "g", "grams": "g",
+	"kg": "kg", "kilogram": "kg", "kilograms": "kg", "kilo": "kg", "kilos": "kg",
+	"oz": "oz", "ounce": "oz", "ounces": "oz",
+	"lb": "lb", "lbs": "lb", "pound": "lb", "pounds": "lb",
+	"ml": "ml", "millilitre": "ml", "millilitres": "ml", "milliliter": "ml", "milliliters": "ml",
+	"l": "l", "litre": "l", "litres": "l", "liter": "l", "liters": "l",
+	"tsp": "tsp", "teaspoon": "tsp", "teaspoons": "tsp",
+	"tbsp": "tbsp", "tablespoon": "tbsp", "tablespoons": "tbsp",
+	"cup": "cup", "cups": "cup",
+}
+
+// ParseSystem returns the system of units with the name, regardless of case, or an empty System for
+// an empty name
+func ParseSystem(name string) (System, error) {
+	switch system := System(strings.ToLower(strings.TrimSpace(name))); system {
+	case "", Metric, Imperial:
+		return system, nil
+	}
+
+	return "", fmt.Errorf("invalid units (%s)", name)
+}
+
+// Lookup returns the unit with the name or one of its spellings, such as "Tablespoons" for "tbsp". It returns
+// false for anything else, including units such as "can" or "clove" which only count things.
+func Lookup(name string) (Unit, bool) {
+	canonical, ok := aliases[strings.ToLower(strings.TrimSuffix(strings.TrimSpace(name), "."))]
+	if !ok {
+		return Unit{}, false
+	}
+
+	for _, unit := range table {
+		if unit.Name == canonical {
+			return unit, true
+		}
+	}
+
+	return Unit{}, false
+}
+
+// Convert returns the quantity in the unit from as a quantity in the unit to, which must measure the
+// same dimension
+func Convert(quantity float64, from string, to string) (float64, error) {
+	f, ok := Lookup(from)
+	if !ok {
+		return 0, fmt.Errorf("unknown unit (%s)", from)
+	}
+	t, ok := Lookup(to)
+	if !ok {
+		return 0, fmt.Errorf("unknown unit (%s)", to)
+	}
+	if f.Dimension != t.Dimension {
+		return 0, fmt.Errorf("cannot convert %s to %s", from, to)
+	}
+
+	return quantity * f.Size / t.Size, nil
+}
+
+// Normalise converts the quantity to the largest unit of the system which holds at least its Min of it, such as
+// 1500 g to 1.5 kg, or 3 tsp to 1 tbsp. An empty system keeps the system of the unit. A unit which is not
+// converted keeps its spelling, and quantities in units which Lookup does not know are returned as they are.
+func Normalise(quantity float64, unit string, system System) (float64, string) {
+	from, ok := Lookup(unit)
+	if !ok {
+		return quantity, unit
+	}
+	if system == "" {
+		system = from.System
+	}
+
+	base := quantity * from.Size
+	var to Unit
+	for _, u := range table {
+		if u.Dimension != from.Dimension || u.System != system {
+			continue
+		}
+		if to.Name == "" || math.Abs(base)/u.Size >= u.Min {
+			to = u
+		}
+	}
+	if to.Name == from.Name {
+		return quantity, unit
+	}
+
+	return base / to.Size, to.Name
+}
+
+// Round rounds the quantity to a precision which can be measured in a kitchen: to the nearest 5 from 100, to
+// whole numbers from 10, to quarters from 1 and to eighths below that. Quantities which Lookup does not know
+// the unit of, such as 3 eggs or 2 cans, are rounded to halves from 1. A quantity is never rounded to 0.
+func Round(quantity float64, unit string) float64 {
+	magnitude := math.Abs(quantity)
+	if magnitude == 0 {
+		return 0
+	}
+
+	var step float64
+	switch _, ok := Lookup(unit); {
+	case magnitude >= 100:
+		step = 5
+	case magnitude >= 10:
+		step = 1
+	case magnitude >= 1 && !ok:
+		step = 0.5
+	case magnitude >= 1:
+		step = 0.25
+	default:
+		step = 0.125
+	}
+
+	rounded := math.Round(magnitude/step) * step
+	if rounded == 0 {
+		rounded = step
+	}
+
+	return math.Copysign(rounded, quantity)
+}
+
+// Scale multiplies the quantity by factor, then normalises it in the system and rounds it. It returns the
+// scaled quantity and its unit.
+func Scale(quantity float64, unit string, factor float64, system System) (float64, string) {
+	quantity, unit = Normalise(quantity*factor, unit, system)
+
+	return Round(quantity, unit), unit
+}
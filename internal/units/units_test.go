@@ -0,0 +1,175 @@
+package units
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseSystem(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    System
+		wantErr bool
+	}{
+		{name: "1", in: "metric", want: Metric},
+		{name: "2", in: " Imperial ", want: Imperial},
+		{name: "3", in: "", want: ""},
+		{name: "4", in: "nautical", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSystem(tt.in)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("ParseSystem() = %v, %v, want %v, wantErr %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     string
+		want   string
+		wantOk bool
+	}{
+		{name: "1", in: "g", want: "g", wantOk: true},
+		{name: "2", in: "Tablespoons", want: "tbsp", wantOk: true},
+		{name: "3", in: "lbs", want: "lb", wantOk: true},
+		{name: "4", in: " tsp. ", want: "tsp", wantOk: true},
+		{name: "5", in: "cups", want: "cup", wantOk: true},
+		{name: "6", in: "litres", want: "l", wantOk: true},
+		{name: "7", in: "can", wantOk: false},
+		{name: "8", in: "", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Lookup(tt.in)
+			if ok != tt.wantOk || got.Name != tt.want {
+				t.Errorf("Lookup() = %v, %v, want %v, %v", got.Name, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name     string
+		quantity float64
+		from     string
+		to       string
+		want     float64
+		wantErr  bool
+	}{
+		{name: "1", quantity: 1.5, from: "kg", to: "g", want: 1500},
+		{name: "2", quantity: 1, from: "lb", to: "oz", want: 16},
+		{name: "3", quantity: 1, from: "cup", to: "tbsp", want: 16},
+		{name: "4", quantity: 1, from: "tbsp", to: "tsp", want: 3},
+		{name: "5", quantity: 100, from: "g", to: "oz", want: 3.527396},
+		{name: "6", quantity: 2, from: "cups", to: "ml", want: 473.176473},
+		{name: "7", quantity: 1, from: "cup", to: "g", wantErr: true},
+		{name: "8", quantity: 1, from: "can", to: "g", wantErr: true},
+		{name: "9", quantity: 1, from: "g", to: "pinch", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Convert(tt.quantity, tt.from, tt.to)
+			if (err != nil) != tt.wantErr || math.Abs(got-tt.want) > 1e-6 {
+				t.Errorf("Convert() = %v, %v, want %v, wantErr %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNormalise(t *testing.T) {
+	tests := []struct {
+		name         string
+		quantity     float64
+		unit         string
+		system       System
+		wantQuantity float64
+		wantUnit     string
+	}{
+		{name: "1", quantity: 1500, unit: "g", wantQuantity: 1.5, wantUnit: "kg"},
+		{name: "2", quantity: 0.5, unit: "kg", wantQuantity: 500, wantUnit: "g"},
+		{name: "3", quantity: 3, unit: "tsp", wantQuantity: 1, wantUnit: "tbsp"},
+		{name: "4", quantity: 8, unit: "tbsp", wantQuantity: 0.5, wantUnit: "cup"},
+		{name: "5", quantity: 3, unit: "tbsp", wantQuantity: 3, wantUnit: "tbsp"},
+		{name: "6", quantity: 2, unit: "cups", wantQuantity: 2, wantUnit: "cups"},
+		{name: "7", quantity: 750, unit: "ml", wantQuantity: 750, wantUnit: "ml"},
+		{name: "8", quantity: 32, unit: "oz", wantQuantity: 2, wantUnit: "lb"},
+		{name: "9", quantity: 1, unit: "lb", system: Metric, wantQuantity: 453.59237, wantUnit: "g"},
+		{name: "10", quantity: 1, unit: "l", system: Imperial, wantQuantity: 4.226753, wantUnit: "cup"},
+		{name: "11", quantity: 5, unit: "ml", system: Imperial, wantQuantity: 1.014420, wantUnit: "tsp"},
+		{name: "12", quantity: 250, unit: "g", system: Imperial, wantQuantity: 8.818490, wantUnit: "oz"},
+		{name: "13", quantity: 2, unit: "cans", system: Metric, wantQuantity: 2, wantUnit: "cans"},
+		{name: "14", quantity: 3, unit: "", system: Imperial, wantQuantity: 3, wantUnit: ""},
+		{name: "15", quantity: 0.05, unit: "g", wantQuantity: 50, wantUnit: "mg"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotQuantity, gotUnit := Normalise(tt.quantity, tt.unit, tt.system)
+			if math.Abs(gotQuantity-tt.wantQuantity) > 1e-6 || gotUnit != tt.wantUnit {
+				t.Errorf("Normalise() = %v %v, want %v %v", gotQuantity, gotUnit, tt.wantQuantity, tt.wantUnit)
+			}
+		})
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		name     string
+		quantity float64
+		unit     string
+		want     float64
+	}{
+		{name: "1", quantity: 453.59237, unit: "g", want: 455},
+		{name: "2", quantity: 12.6, unit: "g", want: 13},
+		{name: "3", quantity: 1.3, unit: "cup", want: 1.25},
+		{name: "4", quantity: 0.33, unit: "tsp", want: 0.375},
+		{name: "5", quantity: 0.01, unit: "tsp", want: 0.125},
+		{name: "6", quantity: 1.3, unit: "", want: 1.5},
+		{name: "7", quantity: 2.2, unit: "cans", want: 2},
+		{name: "8", quantity: 0.3, unit: "", want: 0.25},
+		{name: "9", quantity: 0, unit: "g", want: 0},
+		{name: "10", quantity: 2, unit: "tbsp", want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Round(tt.quantity, tt.unit); got != tt.want {
+				t.Errorf("Round() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScale(t *testing.T) {
+	tests := []struct {
+		name         string
+		quantity     float64
+		unit         string
+		factor       float64
+		system       System
+		wantQuantity float64
+		wantUnit     string
+	}{
+		{name: "1", quantity: 500, unit: "g", factor: 3, wantQuantity: 1.5, wantUnit: "kg"},
+		{name: "2", quantity: 1, unit: "tsp", factor: 1.5, wantQuantity: 1.5, wantUnit: "tsp"},
+		{name: "3", quantity: 2, unit: "tsp", factor: 6, wantQuantity: 0.25, wantUnit: "cup"},
+		{name: "4", quantity: 2, unit: "cups", factor: 0.5, wantQuantity: 1, wantUnit: "cups"},
+		{name: "5", quantity: 1, unit: "cup", factor: 0.125, wantQuantity: 2, wantUnit: "tbsp"},
+		{name: "6", quantity: 3, unit: "", factor: 1.0 / 3, wantQuantity: 1, wantUnit: ""},
+		{name: "7", quantity: 400, unit: "g", factor: 1.5, system: Imperial, wantQuantity: 1.25, wantUnit: "lb"},
+		{name: "8", quantity: 1, unit: "cup", factor: 2, system: Metric, wantQuantity: 475, wantUnit: "ml"},
+		{name: "9", quantity: 2, unit: "cloves", factor: 0.25, wantQuantity: 0.5, wantUnit: "cloves"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotQuantity, gotUnit := Scale(tt.quantity, tt.unit, tt.factor, tt.system)
+			if gotQuantity != tt.wantQuantity || gotUnit != tt.wantUnit {
+				t.Errorf("Scale() = %v %v, want %v %v", gotQuantity, gotUnit, tt.wantQuantity, tt.wantUnit)
+			}
+		})
+	}
+}
@@ -25,10 +25,39 @@ const _ = grpc.SupportPackageIsVersion7
 type RecipeServiceClient interface {
 	// Adds or updates a recipe
 	AddRecipe(ctx context.Context, in *Recipe, opts ...grpc.CallOption) (*emptypb.Empty, error)
-	// Gets a recipe by name
+	// Gets a recipe by name. If there is no such recipe, the NotFound error carries
+	// a Suggestion with the names of similar recipes in its details.
 	GetRecipe(ctx context.Context, in *RecipeRequest, opts ...grpc.CallOption) (*Recipe, error)
-	// Finds recipes based on list of ingredients
+	// Deletes a recipe by name
+	DeleteRecipe(ctx context.Context, in *RecipeRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Finds recipes based on list of ingredients, such as those which use all of them
+	// or those which can be made from them. The best matching recipes come first.
 	FindRecipes(ctx context.Context, in *FindRequest, opts ...grpc.CallOption) (*Recipes, error)
+	// Lists all recipes in name order, a page at a time. The hybrid server also
+	// serves this as GET /recipes when no ingredients are specified.
+	ListRecipes(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*RecipePage, error)
+	// Searches recipes by name, for those whose names start with or contain some text, in name
+	// order a page at a time. Case and whitespace do not count.
+	SearchRecipesByName(ctx context.Context, in *NameSearchRequest, opts ...grpc.CallOption) (*RecipePage, error)
+	// Lists every ingredient which at least one recipe uses, in name order, with the number of recipes
+	// which use it
+	ListIngredients(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Ingredients, error)
+	// Completes the start of an ingredient name, with the ingredients which recipes use most first.
+	// Case, whitespace and plurals do not count.
+	CompleteIngredient(ctx context.Context, in *CompleteRequest, opts ...grpc.CallOption) (*Ingredients, error)
+	// Gets the recipes which are most like a recipe, by the share of all of their ingredients which they have in
+	// common, with the most similar first. Only recipes sharing at least one ingredient are returned. If there is no
+	// such recipe, the NotFound error carries a Suggestion with the names of similar recipes in its details.
+	GetSimilarRecipes(ctx context.Context, in *SimilarRequest, opts ...grpc.CallOption) (*SimilarRecipes, error)
+	// Gets statistics about all recipes, such as the most and least used ingredients and the recipes
+	// which share no ingredient with any other
+	GetStats(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Stats, error)
+	// Gets the ingredient taxonomy, which FindRecipes follows when expand is set
+	GetTaxonomy(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Taxonomy, error)
+	// Admin: makes an ingredient a kind of its parent in the ingredient taxonomy, replacing its previous
+	// parent, or removes it from the taxonomy if parent is empty. Fails with InvalidArgument if the parent
+	// is already a kind of the ingredient.
+	SetIngredientKind(ctx context.Context, in *Kind, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type recipeServiceClient struct {
@@ -57,6 +86,15 @@ func (c *recipeServiceClient) GetRecipe(ctx context.Context, in *RecipeRequest,
 	return out, nil
 }
 
+func (c *recipeServiceClient) DeleteRecipe(ctx context.Context, in *RecipeRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/recipesvc.RecipeService/DeleteRecipe", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *recipeServiceClient) FindRecipes(ctx context.Context, in *FindRequest, opts ...grpc.CallOption) (*Recipes, error) {
 	out := new(Recipes)
 	err := c.cc.Invoke(ctx, "/recipesvc.RecipeService/FindRecipes", in, out, opts...)
@@ -66,16 +104,117 @@ func (c *recipeServiceClient) FindRecipes(ctx context.Context, in *FindRequest,
 	return out, nil
 }
 
+func (c *recipeServiceClient) ListRecipes(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*RecipePage, error) {
+	out := new(RecipePage)
+	err := c.cc.Invoke(ctx, "/recipesvc.RecipeService/ListRecipes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *recipeServiceClient) SearchRecipesByName(ctx context.Context, in *NameSearchRequest, opts ...grpc.CallOption) (*RecipePage, error) {
+	out := new(RecipePage)
+	err := c.cc.Invoke(ctx, "/recipesvc.RecipeService/SearchRecipesByName", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *recipeServiceClient) ListIngredients(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Ingredients, error) {
+	out := new(Ingredients)
+	err := c.cc.Invoke(ctx, "/recipesvc.RecipeService/ListIngredients", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *recipeServiceClient) CompleteIngredient(ctx context.Context, in *CompleteRequest, opts ...grpc.CallOption) (*Ingredients, error) {
+	out := new(Ingredients)
+	err := c.cc.Invoke(ctx, "/recipesvc.RecipeService/CompleteIngredient", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *recipeServiceClient) GetSimilarRecipes(ctx context.Context, in *SimilarRequest, opts ...grpc.CallOption) (*SimilarRecipes, error) {
+	out := new(SimilarRecipes)
+	err := c.cc.Invoke(ctx, "/recipesvc.RecipeService/GetSimilarRecipes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *recipeServiceClient) GetStats(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Stats, error) {
+	out := new(Stats)
+	err := c.cc.Invoke(ctx, "/recipesvc.RecipeService/GetStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *recipeServiceClient) GetTaxonomy(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Taxonomy, error) {
+	out := new(Taxonomy)
+	err := c.cc.Invoke(ctx, "/recipesvc.RecipeService/GetTaxonomy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *recipeServiceClient) SetIngredientKind(ctx context.Context, in *Kind, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/recipesvc.RecipeService/SetIngredientKind", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RecipeServiceServer is the server API for RecipeService service.
 // All implementations should embed UnimplementedRecipeServiceServer
 // for forward compatibility
 type RecipeServiceServer interface {
 	// Adds or updates a recipe
 	AddRecipe(context.Context, *Recipe) (*emptypb.Empty, error)
-	// Gets a recipe by name
+	// Gets a recipe by name. If there is no such recipe, the NotFound error carries
+	// a Suggestion with the names of similar recipes in its details.
 	GetRecipe(context.Context, *RecipeRequest) (*Recipe, error)
-	// Finds recipes based on list of ingredients
+	// Deletes a recipe by name
+	DeleteRecipe(context.Context, *RecipeRequest) (*emptypb.Empty, error)
+	// Finds recipes based on list of ingredients, such as those which use all of them
+	// or those which can be made from them. The best matching recipes come first.
 	FindRecipes(context.Context, *FindRequest) (*Recipes, error)
+	// Lists all recipes in name order, a page at a time. The hybrid server also
+	// serves this as GET /recipes when no ingredients are specified.
+	ListRecipes(context.Context, *ListRequest) (*RecipePage, error)
+	// Searches recipes by name, for those whose names start with or contain some text, in name
+	// order a page at a time. Case and whitespace do not count.
+	SearchRecipesByName(context.Context, *NameSearchRequest) (*RecipePage, error)
+	// Lists every ingredient which at least one recipe uses, in name order, with the number of recipes
+	// which use it
+	ListIngredients(context.Context, *emptypb.Empty) (*Ingredients, error)
+	// Completes the start of an ingredient name, with the ingredients which recipes use most first.
+	// Case, whitespace and plurals do not count.
+	CompleteIngredient(context.Context, *CompleteRequest) (*Ingredients, error)
+	// Gets the recipes which are most like a recipe, by the share of all of their ingredients which they have in
+	// common, with the most similar first. Only recipes sharing at least one ingredient are returned. If there is no
+	// such recipe, the NotFound error carries a Suggestion with the names of similar recipes in its details.
+	GetSimilarRecipes(context.Context, *SimilarRequest) (*SimilarRecipes, error)
+	// Gets statistics about all recipes, such as the most and least used ingredients and the recipes
+	// which share no ingredient with any other
+	GetStats(context.Context, *emptypb.Empty) (*Stats, error)
+	// Gets the ingredient taxonomy, which FindRecipes follows when expand is set
+	GetTaxonomy(context.Context, *emptypb.Empty) (*Taxonomy, error)
+	// Admin: makes an ingredient a kind of its parent in the ingredient taxonomy, replacing its previous
+	// parent, or removes it from the taxonomy if parent is empty. Fails with InvalidArgument if the parent
+	// is already a kind of the ingredient.
+	SetIngredientKind(context.Context, *Kind) (*emptypb.Empty, error)
 }
 
 // UnimplementedRecipeServiceServer should be embedded to have forward compatible implementations.
@@ -88,9 +227,36 @@ func (UnimplementedRecipeServiceServer) AddRecipe(context.Context, *Recipe) (*em
 func (UnimplementedRecipeServiceServer) GetRecipe(context.Context, *RecipeRequest) (*Recipe, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetRecipe not implemented")
 }
+func (UnimplementedRecipeServiceServer) DeleteRecipe(context.Context, *RecipeRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteRecipe not implemented")
+}
 func (UnimplementedRecipeServiceServer) FindRecipes(context.Context, *FindRequest) (*Recipes, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FindRecipes not implemented")
 }
+func (UnimplementedRecipeServiceServer) ListRecipes(context.Context, *ListRequest) (*RecipePage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRecipes not implemented")
+}
+func (UnimplementedRecipeServiceServer) SearchRecipesByName(context.Context, *NameSearchRequest) (*RecipePage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchRecipesByName not implemented")
+}
+func (UnimplementedRecipeServiceServer) ListIngredients(context.Context, *emptypb.Empty) (*Ingredients, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListIngredients not implemented")
+}
+func (UnimplementedRecipeServiceServer) CompleteIngredient(context.Context, *CompleteRequest) (*Ingredients, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompleteIngredient not implemented")
+}
+func (UnimplementedRecipeServiceServer) GetSimilarRecipes(context.Context, *SimilarRequest) (*SimilarRecipes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSimilarRecipes not implemented")
+}
+func (UnimplementedRecipeServiceServer) GetStats(context.Context, *emptypb.Empty) (*Stats, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStats not implemented")
+}
+func (UnimplementedRecipeServiceServer) GetTaxonomy(context.Context, *emptypb.Empty) (*Taxonomy, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTaxonomy not implemented")
+}
+func (UnimplementedRecipeServiceServer) SetIngredientKind(context.Context, *Kind) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetIngredientKind not implemented")
+}
 
 // UnsafeRecipeServiceServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to RecipeServiceServer will
@@ -139,6 +305,24 @@ func _RecipeService_GetRecipe_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RecipeService_DeleteRecipe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecipeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RecipeServiceServer).DeleteRecipe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/recipesvc.RecipeService/DeleteRecipe",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RecipeServiceServer).DeleteRecipe(ctx, req.(*RecipeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _RecipeService_FindRecipes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(FindRequest)
 	if err := dec(in); err != nil {
@@ -157,6 +341,150 @@ func _RecipeService_FindRecipes_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RecipeService_ListRecipes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RecipeServiceServer).ListRecipes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/recipesvc.RecipeService/ListRecipes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RecipeServiceServer).ListRecipes(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RecipeService_SearchRecipesByName_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NameSearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RecipeServiceServer).SearchRecipesByName(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/recipesvc.RecipeService/SearchRecipesByName",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RecipeServiceServer).SearchRecipesByName(ctx, req.(*NameSearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RecipeService_ListIngredients_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RecipeServiceServer).ListIngredients(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/recipesvc.RecipeService/ListIngredients",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RecipeServiceServer).ListIngredients(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RecipeService_CompleteIngredient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RecipeServiceServer).CompleteIngredient(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/recipesvc.RecipeService/CompleteIngredient",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RecipeServiceServer).CompleteIngredient(ctx, req.(*CompleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RecipeService_GetSimilarRecipes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SimilarRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RecipeServiceServer).GetSimilarRecipes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/recipesvc.RecipeService/GetSimilarRecipes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RecipeServiceServer).GetSimilarRecipes(ctx, req.(*SimilarRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RecipeService_GetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RecipeServiceServer).GetStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/recipesvc.RecipeService/GetStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RecipeServiceServer).GetStats(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RecipeService_GetTaxonomy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RecipeServiceServer).GetTaxonomy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/recipesvc.RecipeService/GetTaxonomy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RecipeServiceServer).GetTaxonomy(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RecipeService_SetIngredientKind_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Kind)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RecipeServiceServer).SetIngredientKind(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/recipesvc.RecipeService/SetIngredientKind",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RecipeServiceServer).SetIngredientKind(ctx, req.(*Kind))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // RecipeService_ServiceDesc is the grpc.ServiceDesc for RecipeService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -172,10 +500,46 @@ var RecipeService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetRecipe",
 			Handler:    _RecipeService_GetRecipe_Handler,
 		},
+		{
+			MethodName: "DeleteRecipe",
+			Handler:    _RecipeService_DeleteRecipe_Handler,
+		},
 		{
 			MethodName: "FindRecipes",
 			Handler:    _RecipeService_FindRecipes_Handler,
 		},
+		{
+			MethodName: "ListRecipes",
+			Handler:    _RecipeService_ListRecipes_Handler,
+		},
+		{
+			MethodName: "SearchRecipesByName",
+			Handler:    _RecipeService_SearchRecipesByName_Handler,
+		},
+		{
+			MethodName: "ListIngredients",
+			Handler:    _RecipeService_ListIngredients_Handler,
+		},
+		{
+			MethodName: "CompleteIngredient",
+			Handler:    _RecipeService_CompleteIngredient_Handler,
+		},
+		{
+			MethodName: "GetSimilarRecipes",
+			Handler:    _RecipeService_GetSimilarRecipes_Handler,
+		},
+		{
+			MethodName: "GetStats",
+			Handler:    _RecipeService_GetStats_Handler,
+		},
+		{
+			MethodName: "GetTaxonomy",
+			Handler:    _RecipeService_GetTaxonomy_Handler,
+		},
+		{
+			MethodName: "SetIngredientKind",
+			Handler:    _RecipeService_SetIngredientKind_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "recipesvc.proto",